@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/backfill"
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/worker"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+func main() {
+	defaults := backfill.DefaultConfig()
+
+	var (
+		configPath  *string = flag.String("config", "", "Path to configuration file (defaults to $ENV_FILE or .env)")
+		name        *string = flag.String("name", defaults.Name, "Backfill job name; a job with the same name resumes from its checkpoint")
+		from        *uint64 = flag.Uint64("from", 0, "First block to backfill")
+		to          *int64  = flag.Int64("to", -1, "Last block to backfill (inclusive, -1 for the latest block)")
+		chunkSize   *int    = flag.Int("chunk-size", defaults.ChunkSize, "Blocks committed per checkpoint")
+		concurrency *int    = flag.Int("concurrency", defaults.SyncOptions.MaxConcurrency, "Concurrent block fetch batches")
+		batchSize   *int    = flag.Int("batch-size", defaults.SyncOptions.BatchSize, "Blocks fetched per JSON-RPC batch")
+		rpcBatch    *int    = flag.Int("rpc-batch-size", defaults.SyncOptions.RPCBatchSize, "Maximum calls per JSON-RPC batch request")
+		metricsAddr *string = flag.String("metrics-addr", "", "Address for the metrics endpoint (defaults to :PROMETHEUS_PORT, \"off\" to disable)")
+	)
+	flag.Parse()
+
+	// 加载配置
+	loader := config.NewLoader(*configPath)
+	if *configPath == "" {
+		loader = config.NewLoader(loader.GetEnvFile())
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建日志记录器
+	log, err := logger.New(logger.Config{
+		Level:     cfg.Logging.Level,
+		Format:    cfg.Logging.Format,
+		Output:    cfg.Logging.Output,
+		FilePath:  cfg.Logging.FilePath,
+		Component: "backfill",
+	})
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建数据库连接
+	pgManager, err := database.NewPostgresManager(cfg.Database, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create PostgreSQL manager")
+	}
+	defer pgManager.Close()
+
+	// 注册 Prometheus 指标并暴露指标端点
+	m := metrics.NewMetrics("blockchain_monitor")
+	if err := m.Register(); err != nil {
+		log.WithError(err).Fatal("Failed to register metrics")
+	}
+
+	var metricsServer *http.Server
+	if *metricsAddr != "off" {
+		addr := *metricsAddr
+		if addr == "" {
+			addr = fmt.Sprintf(":%d", cfg.Monitor.PrometheusPort)
+		}
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle(cfg.Monitor.MetricsPath, m.Handler())
+		metricsServer = &http.Server{
+			Addr:              addr,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.WithError(err).Error("Metrics server stopped")
+			}
+		}()
+	}
+
+	// 创建客户端池
+	chainID := big.NewInt(cfg.Ethereum.ChainID)
+	pool, err := worker.NewClientPool(cfg, log, m)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create client pool")
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 未指定结束区块时回填到最新区块
+	endBlock := uint64(*to)
+	if *to < 0 {
+		latest, err := ethereum.NewBlockService(pool, log.Logger).GetLatestBlockNumber(ctx)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to get latest block number")
+		}
+		endBlock = latest.Uint64()
+	}
+	if endBlock < *from {
+		log.Fatalf("End block %d is before start block %d", endBlock, *from)
+	}
+
+	backfillConfig := backfill.DefaultConfig()
+	backfillConfig.Name = *name
+	backfillConfig.StartBlock = *from
+	backfillConfig.EndBlock = endBlock
+	backfillConfig.ChunkSize = *chunkSize
+	backfillConfig.ReceiptConcurrency = cfg.Worker.PoolSize
+	backfillConfig.WriteBatchSize = cfg.Worker.BatchSize
+	backfillConfig.SyncOptions.MaxConcurrency = *concurrency
+	backfillConfig.SyncOptions.BatchSize = *batchSize
+	backfillConfig.SyncOptions.RPCBatchSize = *rpcBatch
+	backfillConfig.Network = cfg.Ethereum.Network
+
+	// 回填的日志使用当前已上传的合约 ABI 解码
+	abis := ethereum.NewABIRegistry()
+	if err := worker.LoadContractABIs(ctx, pgManager.GetDB(), abis); err != nil {
+		log.WithError(err).Warn("Failed to load contract ABIs")
+	}
+
+	b := backfill.New(backfillConfig, pgManager, pool, types.LatestSignerForChainID(chainID), abis, m, log)
+
+	// 收到退出信号时停止，已提交的进度会保留
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigCh
+		log.WithField("signal", sig.String()).Info("Received shutdown signal, stopping backfill")
+		cancel()
+	}()
+
+	runErr := b.Run(ctx)
+
+	if metricsServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Warn("Failed to shut down metrics server")
+		}
+		shutdownCancel()
+	}
+
+	if runErr != nil {
+		log.WithError(runErr).Fatal("Backfill failed")
+	}
+}
@@ -1,24 +1,96 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/server"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
 )
 
 func main() {
+	var (
+		configPath      *string        = flag.String("config", "", "Path to configuration file (defaults to $ENV_FILE or .env)")
+		shutdownTimeout *time.Duration = flag.Duration("shutdown-timeout", 15*time.Second, "Graceful shutdown timeout")
+	)
+	flag.Parse()
+
+	// 加载配置
+	loader := config.NewLoader(*configPath)
+	if *configPath == "" {
+		loader = config.NewLoader(loader.GetEnvFile())
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建日志记录器
+	log, err := logger.New(logger.Config{
+		Level:     cfg.Logging.Level,
+		Format:    cfg.Logging.Format,
+		Output:    cfg.Logging.Output,
+		FilePath:  cfg.Logging.FilePath,
+		Component: "server",
+	})
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建数据库连接
+	pgManager, err := database.NewPostgresManager(cfg.Database, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create PostgreSQL manager")
+	}
+	defer pgManager.Close()
 
-	fmt.Println("Blockchain Monitor Server - Step 1.1 Implementation")
-	fmt.Println("Version: v0.1.0")
-	fmt.Println("Status: Project Structure Initialized")
+	redisManager, err := database.NewRedisManager(cfg.Redis, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create Redis manager")
+	}
+	defer redisManager.Close()
+
+	// 注册 Prometheus 指标
+	m := metrics.NewMetrics("blockchain_monitor")
+	if err := m.Register(); err != nil {
+		log.WithError(err).Fatal("Failed to register metrics")
+	}
+
+	srv := server.New(cfg, log, pgManager, redisManager, m)
 
-	// TODO: 在后续步骤中实现服务器启动逻辑
-	log.Println("Server initialization placeholder - to be implemented in Step 1.2")
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start()
+	}()
 
-	// 检查环境变量文件是否存在
-	if _, err := os.Stat(".env"); os.IsNotExist(err) {
-		log.Println("Warning: .env file not found. Please copy .env.example to .env and configure")
-	} else {
-		log.Println("Environment configuration file found")
+	// 等待退出信号
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case sig := <-sigCh:
+		log.WithField("signal", sig.String()).Info("Received shutdown signal")
+	case err := <-errCh:
+		if err != nil {
+			log.WithError(err).Error("HTTP server stopped unexpectedly")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("Graceful shutdown failed")
 	}
+
+	log.Info("Server stopped")
 }
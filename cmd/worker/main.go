@@ -1,16 +1,105 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/worker"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
 )
 
 func main() {
-	fmt.Println("Blockchain Monitor Worker - Step 1.1 Implementation")
-	fmt.Println("Version: v0.1.0")
-	fmt.Println("Status: Project Structure Initialized")
+	var (
+		configPath *string = flag.String("config", "", "Path to configuration file (defaults to $ENV_FILE or .env)")
+	)
+	flag.Parse()
+
+	// 加载配置
+	loader := config.NewLoader(*configPath)
+	if *configPath == "" {
+		loader = config.NewLoader(loader.GetEnvFile())
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建日志记录器
+	log, err := logger.New(logger.Config{
+		Level:     cfg.Logging.Level,
+		Format:    cfg.Logging.Format,
+		Output:    cfg.Logging.Output,
+		FilePath:  cfg.Logging.FilePath,
+		Component: "worker",
+	})
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 创建数据库连接
+	pgManager, err := database.NewPostgresManager(cfg.Database, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create PostgreSQL manager")
+	}
+	defer pgManager.Close()
+
+	redisManager, err := database.NewRedisManager(cfg.Redis, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create Redis manager")
+	}
+	defer redisManager.Close()
+
+	// 注册 Prometheus 指标并暴露指标端点
+	m := metrics.NewMetrics("blockchain_monitor")
+	if err := m.Register(); err != nil {
+		log.WithError(err).Fatal("Failed to register metrics")
+	}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle(cfg.Monitor.MetricsPath, m.Handler())
+	metricsServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Monitor.PrometheusPort),
+		Handler:           metricsMux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("Metrics server stopped")
+		}
+	}()
+
+	// 创建并启动采集进程
+	w, err := worker.New(cfg, log, pgManager, redisManager, m)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create worker")
+	}
+	if err := w.Start(); err != nil {
+		log.WithError(err).Fatal("Failed to start worker")
+	}
+
+	// 等待退出信号
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-sigCh
+	log.WithField("signal", sig.String()).Info("Received shutdown signal")
+
+	w.Stop()
 
-	// TODO: 在后续步骤中实现工作进程逻辑
-	log.Println("Worker initialization placeholder - to be implemented in later steps")
-	log.Println("Project structure setup completed successfully!")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("Failed to shut down metrics server")
+	}
 }
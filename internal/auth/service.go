@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+var (
+	// ErrInvalidCredentials 用户名、密码或 API Key 错误
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountLocked 连续登录失败导致账户被临时锁定
+	ErrAccountLocked = errors.New("account locked")
+	// ErrAccountInactive 账户未激活、已暂停或已删除
+	ErrAccountInactive = errors.New("account inactive")
+	// ErrSessionRevoked 会话已注销或已过期
+	ErrSessionRevoked = errors.New("session revoked")
+)
+
+// IsAuthError 判断错误是否由凭证问题导致（应返回 401/403），而非内部故障
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenExpired) ||
+		errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrAccountLocked) ||
+		errors.Is(err, ErrAccountInactive) || errors.Is(err, ErrSessionRevoked)
+}
+
+// TokenPair 登录或刷新后返回的令牌
+type TokenPair struct {
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int64        `json:"expires_in"` // 访问令牌剩余秒数
+	User         *models.User `json:"user,omitempty"`
+}
+
+// Service 认证服务
+type Service struct {
+	users      *repository.UserRepository
+	sessions   *repository.UserSessionRepository
+	tokens     *TokenManager
+	refreshTTL time.Duration
+	logger     *logger.Logger
+}
+
+// NewService 创建认证服务
+func NewService(db repository.Executor, cfg config.SecurityConfig, log *logger.Logger) *Service {
+	return &Service{
+		users:      repository.NewUserRepository(db),
+		sessions:   repository.NewUserSessionRepository(db),
+		tokens:     NewTokenManager(cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL),
+		refreshTTL: cfg.RefreshTokenTTL,
+		logger:     log,
+	}
+}
+
+// Login 校验用户名/邮箱与密码，成功后创建会话并签发令牌
+// 先校验密码，密码错误时无论账户是否存在、锁定或停用都返回 ErrInvalidCredentials，避免泄露账户状态
+func (s *Service) Login(ctx context.Context, login, password, ip, userAgent string) (*TokenPair, error) {
+	user, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.CheckPassword(password) {
+		// 锁定期间的失败不再累计，避免延长锁定
+		if !user.IsLocked() {
+			user.IncrementFailedLogin()
+			if err := s.users.UpdateLoginState(ctx, user); err != nil {
+				return nil, err
+			}
+			if user.IsLocked() {
+				s.logger.WithFields(logrus.Fields{
+					"user_id": user.ID,
+					"ip":      ip,
+					"until":   user.LockedUntil,
+				}).Warn("Account locked after repeated login failures")
+			}
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := checkUsable(user); err != nil {
+		return nil, err
+	}
+
+	// 登录成功，清除失败计数与过期的锁定
+	user.UnlockAccount()
+	user.UpdateLastLogin(ip)
+	if err := s.users.UpdateLoginState(ctx, user); err != nil {
+		return nil, err
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	pair, refreshID, err := s.issue(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.UserSession{
+		UserID:         user.ID,
+		SessionID:      sessionID,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		ExpiresAt:      time.Now().Add(s.refreshTTL),
+		IsActive:       true,
+		RefreshTokenID: refreshID,
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	pair.User = sanitize(user)
+	return pair, nil
+}
+
+// Refresh 使用刷新令牌签发新令牌并延长会话
+// 每个刷新令牌只能使用一次：刷新时轮换会话保存的令牌标识，旧令牌随即失效；
+// 已轮换的令牌再次使用说明令牌可能泄露，注销整个会话
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := s.tokens.Parse(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authorizeSession(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, refreshID, err := s.issue(user.ID, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessions.RotateRefreshToken(ctx, claims.SessionID, claims.ID, refreshID, time.Now().Add(s.refreshTTL))
+	if errors.Is(err, models.ErrRecordNotFound) {
+		s.logger.WithField("user_id", user.ID).Warn("Refresh token reused, revoking session")
+		if err := s.sessions.Revoke(ctx, claims.SessionID); err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pair.User = sanitize(user)
+	return pair, nil
+}
+
+// Logout 注销会话，该会话签发的全部令牌随即失效
+func (s *Service) Logout(ctx context.Context, sessionID string) error {
+	if err := s.sessions.Revoke(ctx, sessionID); err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeUserSessions 注销用户的全部会话，用于停用账户或强制下线
+func (s *Service) RevokeUserSessions(ctx context.Context, userID uint64) (int64, error) {
+	return s.sessions.RevokeAllForUser(ctx, userID)
+}
+
+// AuthenticateToken 校验访问令牌，返回用户与会话标识
+func (s *Service) AuthenticateToken(ctx context.Context, token string) (*models.User, string, error) {
+	claims, err := s.tokens.Parse(token, TokenTypeAccess)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.authorizeSession(ctx, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, claims.SessionID, nil
+}
+
+// AuthenticateAPIKey 按 users.api_key 认证用户
+func (s *Service) AuthenticateAPIKey(ctx context.Context, apiKey string) (*models.User, error) {
+	user, err := s.users.GetByAPIKey(ctx, apiKey)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUsable(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// authorizeSession 确认会话仍有效且用户可登录
+func (s *Service) authorizeSession(ctx context.Context, claims *Claims) (*models.User, error) {
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessions.GetBySessionID(ctx, claims.SessionID)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		return nil, ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsValid() || session.UserID != userID {
+		return nil, ErrSessionRevoked
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUsable(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// issue 为会话签发访问令牌与刷新令牌，同时返回刷新令牌标识
+func (s *Service) issue(userID uint64, sessionID string) (*TokenPair, string, error) {
+	access, accessClaims, err := s.tokens.Issue(userID, sessionID, TokenTypeAccess)
+	if err != nil {
+		return nil, "", err
+	}
+	refresh, refreshClaims, err := s.tokens.Issue(userID, sessionID, TokenTypeRefresh)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(accessClaims.ExpiresAtTime()).Seconds()),
+	}, refreshClaims.ID, nil
+}
+
+// sanitize 返回去除密码哈希与 API Key 的用户副本
+func sanitize(user *models.User) *models.User {
+	u := *user
+	u.Password = ""
+	u.APIKey = ""
+	return &u
+}
+
+// checkUsable 检查账户状态
+func checkUsable(user *models.User) error {
+	if user.IsLocked() {
+		return ErrAccountLocked
+	}
+	if !user.IsActive() {
+		return ErrAccountInactive
+	}
+	return nil
+}
+
+// newSessionID 生成随机会话标识
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
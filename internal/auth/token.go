@@ -0,0 +1,147 @@
+// Package auth 实现基于 JWT 与 API Key 的用户认证和会话管理
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TokenType 令牌类型
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"  // 访问令牌
+	TokenTypeRefresh TokenType = "refresh" // 刷新令牌
+)
+
+var (
+	// ErrInvalidToken 令牌格式或签名无效
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenExpired 令牌已过期
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// jwtHeader 固定的 HS256 头部
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims 令牌声明
+type Claims struct {
+	// 用户ID（字符串形式，符合 JWT 规范）
+	Subject string `json:"sub"`
+	// 会话标识，对应 user_sessions.session_id
+	SessionID string `json:"sid"`
+	// 令牌标识，刷新令牌的标识保存在 user_sessions.refresh_token_id 中用于轮换
+	ID string `json:"jti"`
+	// 令牌类型
+	Type TokenType `json:"typ"`
+	// 签发时间（Unix 秒）
+	IssuedAt int64 `json:"iat"`
+	// 过期时间（Unix 秒）
+	ExpiresAt int64 `json:"exp"`
+}
+
+// UserID 解析用户ID
+func (c *Claims) UserID() (uint64, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	return id, nil
+}
+
+// ExpiresAtTime 返回过期时间
+func (c *Claims) ExpiresAtTime() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// TokenManager 使用 HMAC-SHA256 签发与校验令牌
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager 创建令牌管理器
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+	return &TokenManager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Issue 为会话签发指定类型的令牌
+func (tm *TokenManager) Issue(userID uint64, sessionID string, tokenType TokenType) (string, *Claims, error) {
+	ttl := tm.accessTTL
+	if tokenType == TokenTypeRefresh {
+		ttl = tm.refreshTTL
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		Subject:   strconv.FormatUint(userID, 10),
+		SessionID: sessionID,
+		ID:        hex.EncodeToString(id),
+		Type:      tokenType,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode claims: %w", err)
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + tm.sign(unsigned), claims, nil
+}
+
+// Parse 校验签名、类型与有效期并返回声明
+func (tm *TokenManager) Parse(token string, expected TokenType) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(tm.sign(parts[0]+"."+parts[1])), []byte(parts[2])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Type != expected || claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+// sign 计算签名
+func (tm *TokenManager) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, tm.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,297 @@
+// Package backfill 实现历史区块回填，进度保存在数据库中以便中断后继续
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/internal/worker"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// Config 回填配置
+type Config struct {
+	// 任务名称，用于保存与恢复进度
+	Name string
+	// 起始区块号
+	StartBlock uint64
+	// 结束区块号（包含）
+	EndBlock uint64
+	// 每次提交的区块数量
+	ChunkSize int
+	// 并发获取收据的区块数
+	ReceiptConcurrency int
+	// 单条 INSERT 写入的最大行数
+	WriteBatchSize int
+	// 区块拉取选项
+	SyncOptions *ethereum.BlockSyncOptions
+	// 网络名称
+	Network string
+	// 单个分段的超时时间
+	ChunkTimeout time.Duration
+}
+
+// DefaultConfig 返回默认回填配置
+func DefaultConfig() *Config {
+	return &Config{
+		Name:               "default",
+		ChunkSize:          100,
+		ReceiptConcurrency: 10,
+		WriteBatchSize:     500,
+		SyncOptions: &ethereum.BlockSyncOptions{
+			BatchSize:       10,
+			MaxConcurrency:  5,
+			RetryAttempts:   3,
+			RetryDelay:      time.Second,
+			VerifyIntegrity: true,
+			RPCBatchSize:    ethereum.DefaultRPCBatchSize,
+		},
+		ChunkTimeout: 5 * time.Minute,
+	}
+}
+
+// Backfiller 历史区块回填器
+type Backfiller struct {
+	config       *Config
+	postgres     *database.PostgresManager
+	pool         *ethereum.ClientPool
+	blockService *ethereum.BlockService
+	signer       types.Signer
+	abis         *ethereum.ABIRegistry
+	checkpoints  *repository.CheckpointRepository
+	metrics      *metrics.Metrics
+	logger       *logger.Logger
+}
+
+// New 创建回填器
+func New(
+	config *Config,
+	postgres *database.PostgresManager,
+	pool *ethereum.ClientPool,
+	signer types.Signer,
+	abis *ethereum.ABIRegistry,
+	m *metrics.Metrics,
+	log *logger.Logger,
+) *Backfiller {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Backfiller{
+		config:       config,
+		postgres:     postgres,
+		pool:         pool,
+		blockService: ethereum.NewBlockService(pool, log.Logger),
+		signer:       signer,
+		abis:         abis,
+		checkpoints:  repository.NewCheckpointRepository(postgres.GetDB()),
+		metrics:      m,
+		logger:       log,
+	}
+}
+
+// Run 从上次进度开始回填直到结束区块或 ctx 取消
+func (b *Backfiller) Run(ctx context.Context) error {
+	checkpoint, err := b.loadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if checkpoint.IsCompleted() {
+		b.logger.WithField("name", checkpoint.Name).Info("Backfill already completed")
+		return nil
+	}
+
+	next := checkpoint.NextBlock()
+	b.logger.WithFields(logrus.Fields{
+		"name": checkpoint.Name,
+		"from": next,
+		"to":   checkpoint.EndBlock,
+	}).Info("Starting backfill")
+
+	started := time.Now()
+	var processed uint64
+
+	for next <= checkpoint.EndBlock {
+		to := min(next+uint64(b.config.ChunkSize)-1, checkpoint.EndBlock)
+
+		chunkStart := time.Now()
+		txCount, err := b.processChunk(ctx, checkpoint, next, to)
+		if err != nil {
+			b.markFailed(checkpoint)
+			return fmt.Errorf("backfill of blocks %d-%d failed: %w", next, to, err)
+		}
+
+		blocks := to - next + 1
+		processed += blocks
+		rate := float64(processed) / time.Since(started).Seconds()
+
+		b.metrics.BackfillBlocksTotal.Add(float64(blocks))
+		b.metrics.BackfillTransactionsTotal.Add(float64(txCount))
+		b.metrics.BackfillBlocksPerSecond.Set(rate)
+		b.metrics.BackfillCurrentBlock.Set(float64(to))
+		b.metrics.BlockchainBlocksProcessed.WithLabelValues(b.config.Network).Add(float64(blocks))
+
+		b.logger.WithFields(logrus.Fields{
+			"from":           next,
+			"to":             to,
+			"transactions":   txCount,
+			"chunk_duration": time.Since(chunkStart).String(),
+			"blocks_per_sec": fmt.Sprintf("%.2f", rate),
+			"remaining":      checkpoint.EndBlock - to,
+		}).Info("Backfill chunk committed")
+
+		next = to + 1
+	}
+
+	checkpoint.Status = models.BackfillStatusCompleted
+	if err := b.checkpoints.Update(ctx, checkpoint); err != nil {
+		return err
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"name":     checkpoint.Name,
+		"blocks":   processed,
+		"duration": time.Since(started).String(),
+	}).Info("Backfill completed")
+	return nil
+}
+
+// loadCheckpoint 读取或创建回填进度
+func (b *Backfiller) loadCheckpoint(ctx context.Context) (*models.BackfillCheckpoint, error) {
+	checkpoint, err := b.checkpoints.Get(ctx, b.config.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint == nil {
+		checkpoint = &models.BackfillCheckpoint{
+			Name:       b.config.Name,
+			StartBlock: b.config.StartBlock,
+			EndBlock:   b.config.EndBlock,
+			Status:     models.BackfillStatusRunning,
+		}
+		if err := b.checkpoints.Create(ctx, checkpoint); err != nil {
+			return nil, err
+		}
+		return checkpoint, nil
+	}
+
+	if checkpoint.StartBlock != b.config.StartBlock {
+		b.logger.WithFields(logrus.Fields{
+			"name":      checkpoint.Name,
+			"requested": b.config.StartBlock,
+			"stored":    checkpoint.StartBlock,
+		}).Warn("Start block differs from stored checkpoint, resuming from checkpoint")
+	}
+
+	// 允许延长已有任务的结束区块
+	if b.config.EndBlock > checkpoint.EndBlock {
+		checkpoint.EndBlock = b.config.EndBlock
+	}
+	checkpoint.Status = models.BackfillStatusRunning
+	if err := b.checkpoints.Update(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// processChunk 拉取一段区块并与进度在同一事务中提交，返回写入的交易数
+func (b *Backfiller) processChunk(ctx context.Context, checkpoint *models.BackfillCheckpoint, from, to uint64) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.config.ChunkTimeout)
+	defer cancel()
+
+	blocks, err := b.blockService.GetBlockRange(ctx,
+		new(big.Int).SetUint64(from), new(big.Int).SetUint64(to), b.config.SyncOptions)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(len(blocks)) != to-from+1 {
+		return 0, fmt.Errorf("expected %d blocks, got %d", to-from+1, len(blocks))
+	}
+
+	data, err := b.buildBlockData(ctx, blocks)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := b.postgres.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := worker.PersistBlockData(ctx, tx, data, b.config.WriteBatchSize); err != nil {
+		return 0, err
+	}
+
+	progress := *checkpoint
+	progress.LastBlock = &to
+	if err := b.checkpoints.WithTx(tx).Update(ctx, &progress); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit: %w", err)
+	}
+	checkpoint.LastBlock = &to
+
+	txCount := 0
+	for _, d := range data {
+		txCount += len(d.Transactions)
+	}
+	return txCount, nil
+}
+
+// buildBlockData 并发获取收据并转换区块数据，结果保持区块顺序
+func (b *Backfiller) buildBlockData(ctx context.Context, blocks []*types.Block) ([]*worker.BlockData, error) {
+	data := make([]*worker.BlockData, len(blocks))
+	errs := make([]error, len(blocks))
+
+	semaphore := make(chan struct{}, b.config.ReceiptConcurrency)
+	var wg sync.WaitGroup
+
+	for i, block := range blocks {
+		wg.Add(1)
+		go func(i int, block *types.Block) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			receipts, err := worker.FetchReceipts(ctx, b.pool, block)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to fetch receipts for block %d: %w", block.NumberU64(), err)
+				return
+			}
+			data[i], errs[i] = worker.BuildBlockData(block, receipts, b.signer, b.abis)
+		}(i, block)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// markFailed 记录失败状态，保留已提交的进度
+func (b *Backfiller) markFailed(checkpoint *models.BackfillCheckpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	checkpoint.Status = models.BackfillStatusFailed
+	if err := b.checkpoints.Update(ctx, checkpoint); err != nil {
+		b.logger.WithError(err).Warn("Failed to record backfill failure")
+	}
+}
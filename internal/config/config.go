@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -31,6 +34,8 @@ type Config struct {
 	Alert AlertConfig `json:"alert"`
 	// 工作进程配置
 	Worker WorkerConfig `json:"worker"`
+	// 通知渠道配置
+	Notification NotificationConfig `json:"notification"`
 }
 
 // AppConfig 应用程序基础配置
@@ -109,6 +114,22 @@ type EthereumConfig struct {
 	ChainID int64 `json:"chain_id" env:"ETH_CHAIN_ID" validate:"required"`
 	// 以太坊超时时间
 	Timeout time.Duration `json:"timeout" env:"ETH_TIMEOUT"`
+	// 备用HTTP URL，与 HTTPURL 组成客户端池
+	HTTPFallbackURLs []string `json:"http_fallback_urls" env:"ETH_HTTP_FALLBACK_URLS" validate:"dive,url"`
+	// 客户端池负载均衡策略
+	LoadBalanceStrategy string `json:"load_balance_strategy" env:"ETH_LOAD_BALANCE_STRATEGY" validate:"oneof=round_robin random priority healthy latency"`
+	// 节点允许落后于客户端池最高区块的最大区块数，超过时暂停使用该节点
+	MaxHeadLag int `json:"max_head_lag" env:"ETH_MAX_HEAD_LAG" validate:"min=1"`
+	// 法定数量读取所需的一致节点数，0表示客户端池节点数的多数
+	QuorumSize int `json:"quorum_size" env:"ETH_QUORUM_SIZE" validate:"min=0"`
+	// 是否以法定数量读取参与告警评估的区块哈希与收据
+	QuorumReads bool `json:"quorum_reads" env:"ETH_QUORUM_READS"`
+	// 是否对超过延迟分位数仍未返回的读取请求进行对冲
+	HedgeEnabled bool `json:"hedge_enabled" env:"ETH_HEDGE_ENABLED"`
+	// 触发对冲的延迟百分位
+	HedgePercentile int `json:"hedge_percentile" env:"ETH_HEDGE_PERCENTILE" validate:"min=1,max=99"`
+	// 对冲请求数占读取请求数的最大百分比
+	HedgeBudgetPercent int `json:"hedge_budget_percent" env:"ETH_HEDGE_BUDGET_PERCENT" validate:"min=1,max=100"`
 }
 
 // TelegramConfig Telegram Bot配置
@@ -145,12 +166,16 @@ type LoggingConfig struct {
 type SecurityConfig struct {
 	// JWT密钥
 	JWTSecret string `json:"jwt_secret" env:"JWT_SECRET" validate:"required,min=32"`
-	// API密钥
-	APIKey string `json:"api_key" env:"API_KEY" validate:"required"`
 	// CORS允许的来源
 	CORSAllowedOrigins []string `json:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	// 受信反向代理的IP或CIDR，仅来自这些地址的请求才读取 X-Forwarded-For
+	TrustedProxies []string `json:"trusted_proxies" env:"TRUSTED_PROXIES"`
 	// 加密密钥
 	EncryptionKey string `json:"encryption_key" env:"ENCRYPTION_KEY" validate:"min=32"`
+	// 访问令牌有效期
+	AccessTokenTTL time.Duration `json:"access_token_ttl" env:"JWT_ACCESS_TTL"`
+	// 刷新令牌有效期，同时作为会话有效期
+	RefreshTokenTTL time.Duration `json:"refresh_token_ttl" env:"JWT_REFRESH_TTL"`
 }
 
 // RateLimitConfig 限流配置
@@ -159,11 +184,15 @@ type RateLimitConfig struct {
 	Requests int `json:"requests" env:"RATE_LIMIT_REQUESTS" validate:"min=1"`
 	// 限流窗口时间
 	Window time.Duration `json:"window" env:"RATE_LIMIT_WINDOW" validate:"required"`
+	// 按角色覆盖的窗口内请求次数，格式为 role:requests，如 admin:1000
+	RoleOverrides []string `json:"role_overrides" env:"RATE_LIMIT_ROLE_OVERRIDES"`
+	// 认证前每个客户端IP的窗口内请求次数，限制凭证猜测
+	IPRequests int `json:"ip_requests" env:"RATE_LIMIT_IP_REQUESTS" validate:"min=1"`
 }
 
 // AlertConfig 告警配置
 type AlertConfig struct {
-	// 告警冷却时间
+	// 告警冷却时间，同一规则同一分组两次告警的最小间隔
 	Cooldown time.Duration `json:"cooldown" env:"ALERT_COOLDOWN" validate:"required"`
 	// 每小时最大告警次数
 	MaxPerHour int `json:"max_per_hour" env:"MAX_ALERTS_PER_HOUR" validate:"min=1"`
@@ -171,6 +200,8 @@ type AlertConfig struct {
 	RetryAttempts int `json:"retry_attempts" env:"ALERT_RETRY_ATTEMPTS"`
 	// 告警重试间隔
 	RetryInterval time.Duration `json:"retry_interval" env:"ALERT_RETRY_INTERVAL"`
+	// 条件表达式可引用的地址名单，格式为 name:addr1|addr2，如 exchanges:0xabc...|0xdef...
+	Watchlists []string `json:"watchlists" env:"ALERT_WATCHLISTS"`
 }
 
 // WorkerConfig 工作进程配置
@@ -183,6 +214,101 @@ type WorkerConfig struct {
 	Timeout time.Duration `json:"timeout" env:"WORKER_TIMEOUT" validate:"required"`
 	// 工作进程批处理大小
 	BatchSize int `json:"batch_size" env:"WORKER_BATCH_SIZE"`
+	// 链重组检测深度（区块数），0 表示关闭
+	ReorgDepth int `json:"reorg_depth" env:"WORKER_REORG_DEPTH" validate:"min=0"`
+}
+
+// NotificationConfig 通知渠道配置
+type NotificationConfig struct {
+	// SMTP服务器地址
+	SMTPHost string `json:"smtp_host" env:"SMTP_HOST"`
+	// SMTP服务器端口
+	SMTPPort int `json:"smtp_port" env:"SMTP_PORT"`
+	// SMTP用户名
+	SMTPUsername string `json:"smtp_username" env:"SMTP_USERNAME"`
+	// SMTP密码
+	SMTPPassword string `json:"smtp_password" env:"SMTP_PASSWORD"`
+	// 发件人地址
+	SMTPFrom string `json:"smtp_from" env:"SMTP_FROM"`
+	// 短信网关URL
+	SMSGatewayURL string `json:"sms_gateway_url" env:"SMS_GATEWAY_URL" validate:"omitempty,url"`
+	// 短信网关API Key
+	SMSAPIKey string `json:"sms_api_key" env:"SMS_API_KEY"`
+	// HTTP通知请求超时时间
+	Timeout time.Duration `json:"timeout" env:"NOTIFICATION_TIMEOUT"`
+}
+
+// RoleLimits 解析按角色覆盖的请求次数
+func (r *RateLimitConfig) RoleLimits() (map[string]int, error) {
+	limits := make(map[string]int, len(r.RoleOverrides))
+	for _, override := range r.RoleOverrides {
+		if override == "" {
+			continue
+		}
+		role, value, ok := strings.Cut(override, ":")
+		role = strings.TrimSpace(role)
+		if !ok || role == "" {
+			return nil, fmt.Errorf("invalid rate limit role override %q, expected role:requests", override)
+		}
+		requests, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || requests < 1 {
+			return nil, fmt.Errorf("invalid rate limit for role %s: %q", role, value)
+		}
+		limits[role] = requests
+	}
+	return limits, nil
+}
+
+// TrustedProxyNetworks 解析受信反向代理地址，单个IP视为 /32 或 /128 网段
+func (s *SecurityConfig) TrustedProxyNetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(s.TrustedProxies))
+	for _, proxy := range s.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, expected IP or CIDR", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, expected IP or CIDR", proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// WatchlistMap 解析地址名单
+func (a *AlertConfig) WatchlistMap() (map[string][]string, error) {
+	lists := make(map[string][]string, len(a.Watchlists))
+	for _, entry := range a.Watchlists {
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid watchlist %q, expected name:addr1|addr2", entry)
+		}
+		var addresses []string
+		for _, address := range strings.Split(value, "|") {
+			if address = strings.TrimSpace(address); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+		lists[name] = append(lists[name], addresses...)
+	}
+	return lists, nil
 }
 
 // GetDSN 获取数据库连接字符串
@@ -78,6 +78,10 @@ func (l *Loader) setDefaults(cfg *Config) {
 	cfg.Ethereum.Network = "mainnet"
 	cfg.Ethereum.ChainID = 1
 	cfg.Ethereum.Timeout = 30 * time.Second
+	cfg.Ethereum.LoadBalanceStrategy = "latency"
+	cfg.Ethereum.MaxHeadLag = 5
+	cfg.Ethereum.HedgePercentile = 95
+	cfg.Ethereum.HedgeBudgetPercent = 10
 
 	// Telegram默认配置
 	cfg.Telegram.BotToken = "your-telegram-bot-token"
@@ -93,9 +97,14 @@ func (l *Loader) setDefaults(cfg *Config) {
 	cfg.Logging.Format = "json"
 	cfg.Logging.Output = "stdout"
 
+	// 认证默认配置
+	cfg.Security.AccessTokenTTL = 15 * time.Minute
+	cfg.Security.RefreshTokenTTL = 7 * 24 * time.Hour
+
 	// 限流默认配置
 	cfg.RateLimit.Requests = 100
 	cfg.RateLimit.Window = 60 * time.Second
+	cfg.RateLimit.IPRequests = 300
 
 	// 告警默认配置
 	cfg.Alert.Cooldown = 300 * time.Second
@@ -108,6 +117,11 @@ func (l *Loader) setDefaults(cfg *Config) {
 	cfg.Worker.QueueSize = 1000
 	cfg.Worker.Timeout = 30 * time.Second
 	cfg.Worker.BatchSize = 100
+	cfg.Worker.ReorgDepth = 64
+
+	// 通知渠道默认配置
+	cfg.Notification.SMTPPort = 587
+	cfg.Notification.Timeout = 10 * time.Second
 }
 
 // MustLoad 加载配置，失败时panic
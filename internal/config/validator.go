@@ -87,6 +87,21 @@ func (v *Validator) customValidation(cfg *Config) error {
 		return fmt.Errorf("jwt_secret must be at least 32 characters long")
 	}
 
+	// 验证受信反向代理配置
+	if _, err := cfg.Security.TrustedProxyNetworks(); err != nil {
+		return err
+	}
+
+	// 验证限流角色覆盖配置
+	if _, err := cfg.RateLimit.RoleLimits(); err != nil {
+		return err
+	}
+
+	// 验证告警地址名单配置
+	if _, err := cfg.Alert.WatchlistMap(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
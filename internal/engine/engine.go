@@ -0,0 +1,706 @@
+// Package engine 实现告警规则评估引擎，将链上事件与告警规则匹配并生成告警记录
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// RuleStore 告警规则存储
+type RuleStore interface {
+	// ListActive 获取所有激活的规则
+	ListActive(ctx context.Context) ([]*models.AlertRule, error)
+	// UpdateTriggerStats 更新规则触发统计
+	UpdateTriggerStats(ctx context.Context, id, triggerCount uint64, lastTriggered time.Time) error
+}
+
+// Config 引擎配置
+type Config struct {
+	// 规则重新加载间隔
+	ReloadInterval time.Duration
+	// 告警输出缓冲区大小
+	BufferSize int
+	// 链ID，用于恢复交易发送方
+	ChainID *big.Int
+	// 数据库操作超时时间
+	StoreTimeout time.Duration
+	// 条件表达式 watchlist 函数使用的地址名单
+	Watchlists expr.Watchlists
+	// 合约 ABI 注册表，用于解码交易输入与合约日志，为空时仅使用内置 ABI
+	ABIs *ethereum.ABIRegistry
+}
+
+// DefaultConfig 返回默认引擎配置
+func DefaultConfig() *Config {
+	return &Config{
+		ReloadInterval: 30 * time.Second,
+		BufferSize:     1000,
+		ChainID:        big.NewInt(1),
+		StoreTimeout:   5 * time.Second,
+	}
+}
+
+// typeSources 各告警类型关注的事件来源，自定义规则匹配所有来源
+var typeSources = map[models.AlertType][]string{
+	models.AlertTypeGasPrice:          {SourceGasPrice},
+	models.AlertTypeLargeTransfer:     {SourceTransaction},
+	models.AlertTypeBlockTime:         {SourceBlock},
+	models.AlertTypeNetworkCongestion: {SourceBlock},
+	models.AlertTypeContractEvent:     {SourceTransaction, SourceLog},
+	models.AlertTypeAddressActivity:   {SourceTransaction},
+	models.AlertTypeTokenTransfer:     {SourceTransaction, SourceLog},
+	models.AlertTypeSystemHealth:      {SourceSystem},
+	models.AlertTypeCustom:            {SourceBlock, SourceTransaction, SourceLog, SourceGasPrice, SourceSystem},
+}
+
+// primaryFields 各告警类型的主指标字段，规则的 Threshold/Operator 作用于该字段
+var primaryFields = map[models.AlertType]string{
+	models.AlertTypeGasPrice:          FieldGasPriceGwei,
+	models.AlertTypeLargeTransfer:     FieldValueEth,
+	models.AlertTypeBlockTime:         FieldBlockTime,
+	models.AlertTypeNetworkCongestion: FieldGasUtilization,
+}
+
+// Resolution 规则条件恢复事件，条件持续一个 TimeWindow 不满足时生成
+type Resolution struct {
+	// 规则快照
+	Rule models.AlertRule
+	// 解决时间
+	ResolvedAt time.Time
+}
+
+// conditionState 规则条件状态，用于判断自动解决
+type conditionState struct {
+	// 条件最近一次满足后尚未解决
+	firing bool
+	// 条件开始持续不满足的时间
+	falseSince time.Time
+}
+
+// Engine 告警规则评估引擎
+// 同时实现 ethereum.BlockEventHandler 与 ethereum.TxEventHandler
+type Engine struct {
+	config  *Config
+	store   RuleStore
+	metrics *metrics.Metrics
+	logger  *logger.Logger
+	signer  types.Signer
+	abis    *ethereum.ABIRegistry
+
+	// 按事件来源索引的激活规则
+	rules   map[string][]*models.AlertRule
+	rulesMu sync.RWMutex
+
+	// 保护规则的触发状态（TriggerCount/LastTriggered）与条件状态
+	triggerMu sync.Mutex
+	// 按规则 ID 记录的条件状态
+	conditions map[uint64]*conditionState
+
+	// 按表达式源码缓存的编译结果
+	programs sync.Map
+
+	// 按规则 ID 记录的聚合窗口
+	windows   map[uint64]*slidingWindow
+	windowsMu sync.Mutex
+
+	// 上一个区块头，用于计算出块时间
+	lastHeader   *types.Header
+	lastHeaderMu sync.Mutex
+
+	alerts      chan *models.Alert
+	resolutions chan *Resolution
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建告警引擎
+func New(config *Config, store RuleStore, m *metrics.Metrics, log *logger.Logger) *Engine {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	abis := config.ABIs
+	if abis == nil {
+		abis = ethereum.NewABIRegistry()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Engine{
+		config:      config,
+		store:       store,
+		metrics:     m,
+		logger:      log,
+		signer:      types.LatestSignerForChainID(config.ChainID),
+		abis:        abis,
+		rules:       make(map[string][]*models.AlertRule),
+		conditions:  make(map[uint64]*conditionState),
+		windows:     make(map[uint64]*slidingWindow),
+		alerts:      make(chan *models.Alert, config.BufferSize),
+		resolutions: make(chan *Resolution, config.BufferSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start 加载规则并启动定期重新加载
+func (e *Engine) Start() error {
+	if err := e.ReloadRules(); err != nil {
+		return err
+	}
+
+	e.wg.Add(1)
+	go e.reloadLoop()
+
+	return nil
+}
+
+// Stop 停止引擎
+func (e *Engine) Stop() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// Alerts 返回引擎生成的告警
+func (e *Engine) Alerts() <-chan *models.Alert {
+	return e.alerts
+}
+
+// Resolutions 返回规则条件恢复事件
+func (e *Engine) Resolutions() <-chan *Resolution {
+	return e.resolutions
+}
+
+// ReloadRules 从存储重新加载激活规则，保留内存中较新的触发状态
+func (e *Engine) ReloadRules() error {
+	ctx, cancel := context.WithTimeout(e.ctx, e.config.StoreTimeout)
+	defer cancel()
+
+	rules, err := e.store.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	// 保留旧规则的触发状态，避免数据库写入延迟导致冷却失效
+	e.rulesMu.RLock()
+	previous := make(map[uint64]*models.AlertRule)
+	for _, list := range e.rules {
+		for _, rule := range list {
+			previous[rule.ID] = rule
+		}
+	}
+	e.rulesMu.RUnlock()
+
+	indexed := make(map[string][]*models.AlertRule)
+	e.triggerMu.Lock()
+	for _, rule := range rules {
+		if old, ok := previous[rule.ID]; ok && old.LastTriggered != nil &&
+			(rule.LastTriggered == nil || old.LastTriggered.After(*rule.LastTriggered)) {
+			rule.LastTriggered = old.LastTriggered
+			rule.TriggerCount = old.TriggerCount
+		}
+		for _, source := range typeSources[rule.Type] {
+			indexed[source] = append(indexed[source], rule)
+		}
+	}
+	e.triggerMu.Unlock()
+
+	e.rulesMu.Lock()
+	e.rules = indexed
+	e.rulesMu.Unlock()
+
+	// 丢弃已删除或不再聚合的规则的窗口，以及不再使用的表达式
+	aggregated := make(map[uint64]bool, len(rules))
+	expressions := make(map[string]bool)
+	for _, rule := range rules {
+		aggregated[rule.ID] = rule.IsAggregated()
+		if conditions, err := rule.GetConditions(); err == nil {
+			for _, condition := range conditions {
+				if condition.Expression != "" {
+					expressions[condition.Expression] = true
+				}
+			}
+		}
+	}
+	e.programs.Range(func(source, _ interface{}) bool {
+		if !expressions[source.(string)] {
+			e.programs.Delete(source)
+		}
+		return true
+	})
+	e.windowsMu.Lock()
+	for id := range e.windows {
+		if !aggregated[id] {
+			delete(e.windows, id)
+		}
+	}
+	e.windowsMu.Unlock()
+
+	e.logger.WithField("rules", len(rules)).Debug("Alert rules reloaded")
+	return nil
+}
+
+// reloadLoop 定期重新加载规则
+func (e *Engine) reloadLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.ReloadRules(); err != nil {
+				e.logger.WithError(err).Warn("Failed to reload alert rules")
+			}
+		}
+	}
+}
+
+// GetName 返回处理器名称
+func (e *Engine) GetName() string {
+	return "alert_engine"
+}
+
+// HandleError 处理订阅错误
+func (e *Engine) HandleError(err error) {
+	e.logger.WithError(err).Debug("Alert engine received subscription error")
+}
+
+// HandleBlock 评估区块事件
+func (e *Engine) HandleBlock(event *ethereum.BlockEvent) error {
+	e.lastHeaderMu.Lock()
+	parent := e.lastHeader
+	e.lastHeader = event.Header
+	e.lastHeaderMu.Unlock()
+
+	e.Evaluate(NewBlockEvent(event.Header, parent))
+	return nil
+}
+
+// HandleReorg 将出块时间基准回退到共同祖先，并生成链重组系统事件
+func (e *Engine) HandleReorg(event *ethereum.ReorgEvent) error {
+	e.lastHeaderMu.Lock()
+	e.lastHeader = event.CommonAncestor
+	e.lastHeaderMu.Unlock()
+
+	e.Evaluate(NewSystemEvent("chain", "reorg", map[string]interface{}{
+		FieldReorgDepth:  float64(event.Depth),
+		FieldBlockNumber: float64(event.CommonAncestor.Number.Uint64()),
+	}))
+	return nil
+}
+
+// HandleQuorumMismatch 将节点读取结果与多数节点不一致转换为系统健康事件
+func (e *Engine) HandleQuorumMismatch(mismatch *ethereum.QuorumMismatch) {
+	e.Evaluate(NewSystemEvent("rpc", "quorum_mismatch", map[string]interface{}{
+		FieldEndpoint:      ethereum.EndpointName(mismatch.Endpoint),
+		FieldRPCMethod:     mismatch.Method,
+		FieldQuorumTarget:  mismatch.Target,
+		FieldExpected:      mismatch.Expected,
+		FieldActual:        mismatch.Actual,
+		FieldAgreeing:      float64(mismatch.Agreeing),
+		FieldResponding:    float64(mismatch.Responding),
+		FieldQuorumReached: mismatch.QuorumReached,
+	}))
+}
+
+// HandleTransaction 评估交易事件，仅哈希的事件无法评估
+func (e *Engine) HandleTransaction(event *ethereum.TxEvent) error {
+	if event.Transaction == nil {
+		return nil
+	}
+	tx := event.Transaction
+	e.Evaluate(NewTransactionEvent(tx, e.signer, e.abis.DecodeCall(tx.To(), tx.Data())))
+	return nil
+}
+
+// HandleLogs 评估已确认区块的合约日志，被重组移除的日志不评估
+func (e *Engine) HandleLogs(logs []*types.Log) {
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		e.Evaluate(NewLogEvent(log, e.abis.DecodeLog(log)))
+	}
+}
+
+// WatchGasPrices 消费 Gas 价格采样直到通道关闭或引擎停止
+func (e *Engine) WatchGasPrices(prices <-chan *ethereum.GasPriceInfo) {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for {
+			select {
+			case <-e.ctx.Done():
+				return
+			case info, ok := <-prices:
+				if !ok {
+					return
+				}
+				e.Evaluate(NewGasPriceEvent(info))
+			}
+		}
+	}()
+}
+
+// Evaluate 使用所有相关规则评估事件
+func (e *Engine) Evaluate(event *Event) {
+	e.rulesMu.RLock()
+	rules := e.rules[event.SourceType]
+	e.rulesMu.RUnlock()
+
+	for _, rule := range rules {
+		if rule.IsAggregated() {
+			e.evaluateAggregate(rule, event)
+			continue
+		}
+
+		matched, triggerValue, matchedValue, err := e.evaluateRule(rule, event)
+		if err != nil {
+			e.logger.WithFields(logrus.Fields{
+				"rule_id": rule.ID,
+				"source":  event.SourceType,
+			}).WithError(err).Debug("Alert rule evaluation failed")
+			continue
+		}
+		if !matched {
+			e.observeClear(rule)
+			continue
+		}
+
+		e.observeMatch(rule)
+		e.trigger(rule, event, triggerValue, matchedValue)
+	}
+}
+
+// evaluateRule 评估单个规则：先比较主指标与阈值，再按顺序组合各条件
+// 条件的 LogicalOp 表示其与前面结果的组合方式，缺省为 AND
+func (e *Engine) evaluateRule(rule *models.AlertRule, event *Event) (bool, float64, interface{}, error) {
+	var triggerValue float64
+	var matchedValue interface{}
+
+	if field, ok := primaryFields[rule.Type]; ok {
+		value, exists := event.Fields[field]
+		if !exists {
+			return false, 0, nil, nil
+		}
+		matched, err := rule.EvaluateCondition(models.AlertCondition{
+			Field:    field,
+			Operator: rule.Operator,
+			Value:    rule.Threshold,
+		}, value)
+		if err != nil || !matched {
+			return false, 0, nil, err
+		}
+		triggerValue, _ = value.(float64)
+		matchedValue = value
+	}
+
+	conditions, err := rule.GetConditions()
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("invalid conditions: %w", err)
+	}
+
+	if len(conditions) == 0 {
+		if rule.Type == models.AlertTypeSystemHealth && event.SourceType == SourceSystem {
+			// 系统健康事件本身即表示异常（如 quorum_mismatch），没有条件的系统健康规则直接触发
+			return true, triggerValue, event.Fields[FieldReason], nil
+		}
+		// 没有主指标也没有条件的其他规则不触发
+		return matchedValue != nil, triggerValue, matchedValue, nil
+	}
+
+	result, value, err := e.matchConditions(rule, conditions, event)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if matchedValue == nil && value != nil {
+		matchedValue = value
+		if f, ok := value.(float64); ok {
+			triggerValue = f
+		}
+	}
+
+	return result, triggerValue, matchedValue, nil
+}
+
+// matchConditions 按顺序组合各条件，返回组合结果与第一个满足的字段条件的字段值
+func (e *Engine) matchConditions(rule *models.AlertRule, conditions []models.AlertCondition, event *Event) (bool, interface{}, error) {
+	var matchedValue interface{}
+
+	result := false
+	for i, condition := range conditions {
+		match := false
+		if condition.Expression != "" {
+			var err error
+			if match, err = e.evaluateExpression(condition.Expression, event); err != nil {
+				return false, nil, err
+			}
+		} else if value, exists := event.Fields[condition.Field]; exists {
+			var err error
+			match, err = rule.EvaluateCondition(normalizeCondition(condition), value)
+			if err != nil {
+				return false, nil, fmt.Errorf("condition %q: %w", condition.Field, err)
+			}
+			if match && matchedValue == nil {
+				matchedValue = value
+			}
+		}
+
+		if i == 0 {
+			result = match
+			continue
+		}
+		switch condition.LogicalOp {
+		case models.LogicalOr:
+			result = result || match
+		default:
+			result = result && match
+		}
+	}
+
+	return result, matchedValue, nil
+}
+
+// evaluateExpression 编译（带缓存）并求值条件表达式
+func (e *Engine) evaluateExpression(source string, event *Event) (bool, error) {
+	var program *expr.Program
+	if cached, ok := e.programs.Load(source); ok {
+		program = cached.(*expr.Program)
+	} else {
+		compiled, err := models.CompileExpression(source)
+		if err != nil {
+			return false, fmt.Errorf("invalid expression: %w", err)
+		}
+		e.programs.Store(source, compiled)
+		program = compiled
+	}
+
+	matched, err := program.Eval(event.Vars, e.config.Watchlists)
+	if err != nil {
+		return false, fmt.Errorf("expression %q: %w", source, err)
+	}
+	return matched, nil
+}
+
+// evaluateAggregate 评估窗口聚合规则：满足条件的事件计入规则的滑动窗口，再以窗口聚合值与阈值比较
+// 只有计入窗口的事件会触发告警，其他事件仅推进窗口并用于判断自动解决
+func (e *Engine) evaluateAggregate(rule *models.AlertRule, event *Event) {
+	field := rule.AggregationField
+	if field == "" {
+		field = primaryFields[rule.Type]
+	}
+
+	sample, sampled, err := e.aggregateSample(rule, field, event)
+	if err != nil {
+		e.logger.WithFields(logrus.Fields{
+			"rule_id": rule.ID,
+			"source":  event.SourceType,
+		}).WithError(err).Debug("Alert rule evaluation failed")
+		return
+	}
+
+	size := time.Duration(rule.TimeWindow) * time.Second
+
+	e.windowsMu.Lock()
+	window, ok := e.windows[rule.ID]
+	if !ok || window.field != field {
+		window = &slidingWindow{field: field}
+		e.windows[rule.ID] = window
+	}
+	window.prune(event.Timestamp, size)
+	if sampled {
+		window.add(event.Timestamp, sample)
+	}
+	value, ok := window.aggregate(rule.Aggregation, size)
+	samples := window.count
+	e.windowsMu.Unlock()
+
+	matched := false
+	if ok {
+		matched, err = rule.EvaluateCondition(models.AlertCondition{
+			Field:    field,
+			Operator: rule.Operator,
+			Value:    rule.Threshold,
+		}, value)
+		if err != nil {
+			e.logger.WithField("rule_id", rule.ID).WithError(err).Debug("Alert rule evaluation failed")
+			return
+		}
+	}
+	if !matched {
+		e.observeClear(rule)
+		return
+	}
+
+	e.observeMatch(rule)
+	if sampled {
+		e.trigger(rule, withWindowFields(event, value, samples), value, value)
+	}
+}
+
+// aggregateSample 检查事件是否满足规则条件并取出样本值，count 与 rate 不需要样本值
+func (e *Engine) aggregateSample(rule *models.AlertRule, field string, event *Event) (float64, bool, error) {
+	conditions, err := rule.GetConditions()
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid conditions: %w", err)
+	}
+	if len(conditions) > 0 {
+		matched, _, err := e.matchConditions(rule, conditions, event)
+		if err != nil || !matched {
+			return 0, false, err
+		}
+	}
+
+	if !rule.Aggregation.NeedsField() {
+		return 0, true, nil
+	}
+	if field == "" {
+		return 0, false, fmt.Errorf("aggregation %s requires aggregation_field", rule.Aggregation)
+	}
+	value, ok := event.Fields[field].(float64)
+	if !ok {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
+
+// withWindowFields 复制事件并附加窗口聚合结果，事件字段可能被其他规则共享，不能原地修改
+func withWindowFields(event *Event, value, samples float64) *Event {
+	fields := make(map[string]interface{}, len(event.Fields)+2)
+	for k, v := range event.Fields {
+		fields[k] = v
+	}
+	fields[FieldWindowValue] = value
+	fields[FieldWindowSamples] = samples
+
+	copied := *event
+	copied.Fields = fields
+	return &copied
+}
+
+// normalizeCondition 将字符串条件值转换为小写，与事件字段保持一致
+func normalizeCondition(condition models.AlertCondition) models.AlertCondition {
+	if s, ok := condition.Value.(string); ok {
+		condition.Value = strings.ToLower(s)
+	}
+	return condition
+}
+
+// observeMatch 记录规则条件满足
+func (e *Engine) observeMatch(rule *models.AlertRule) {
+	e.triggerMu.Lock()
+	e.conditions[rule.ID] = &conditionState{firing: true}
+	e.triggerMu.Unlock()
+}
+
+// observeClear 记录规则条件不满足，持续一个 TimeWindow 后生成解决事件
+// 进程启动后未见过的规则视为可能仍在告警，以便解决重启前遗留的告警
+func (e *Engine) observeClear(rule *models.AlertRule) {
+	now := time.Now()
+
+	e.triggerMu.Lock()
+	state, ok := e.conditions[rule.ID]
+	if !ok {
+		state = &conditionState{firing: true}
+		e.conditions[rule.ID] = state
+	}
+	if !state.firing {
+		e.triggerMu.Unlock()
+		return
+	}
+	if state.falseSince.IsZero() {
+		state.falseSince = now
+	}
+	if now.Sub(state.falseSince) < time.Duration(rule.TimeWindow)*time.Second {
+		e.triggerMu.Unlock()
+		return
+	}
+	state.firing = false
+	snapshot := *rule
+	e.triggerMu.Unlock()
+
+	select {
+	case e.resolutions <- &Resolution{Rule: snapshot, ResolvedAt: now}:
+	default:
+		e.logger.WithField("rule_id", rule.ID).Warn("Resolution channel full, dropping resolution")
+	}
+}
+
+// trigger 检查冷却时间并生成告警
+// 告警成功进入输出通道后才占用冷却期并写入触发统计，通道已满被丢弃的告警不影响下次触发
+func (e *Engine) trigger(rule *models.AlertRule, event *Event, triggerValue float64, matchedValue interface{}) {
+	e.triggerMu.Lock()
+	if !rule.CanTrigger() {
+		e.triggerMu.Unlock()
+		return
+	}
+	snapshot := *rule
+	snapshot.IncrementTriggerCount()
+
+	alert := &models.Alert{
+		RuleID:       rule.ID,
+		Type:         rule.Type,
+		Severity:     rule.Severity,
+		Title:        rule.Name,
+		Message:      fmt.Sprintf("告警规则 %s 由 %s %s 触发，触发值 %v", rule.Name, event.SourceType, event.SourceID, matchedValue),
+		TriggerValue: triggerValue,
+		TriggerTime:  event.Timestamp,
+		GroupCount:   1,
+		Status:       models.NotificationStatusPending,
+		Rule:         snapshot,
+	}
+	if err := alert.SetTriggerData(&models.AlertTriggerData{
+		SourceType:   event.SourceType,
+		SourceID:     event.SourceID,
+		MatchedValue: matchedValue,
+		Context:      event.Fields,
+		Timestamp:    event.Timestamp,
+	}); err != nil {
+		e.logger.WithError(err).WithField("rule_id", rule.ID).Warn("Failed to encode trigger data")
+	}
+
+	// 持有锁入队，并发事件中只有成功入队的一个占用冷却期
+	select {
+	case e.alerts <- alert:
+		rule.TriggerCount = snapshot.TriggerCount
+		rule.LastTriggered = snapshot.LastTriggered
+		e.triggerMu.Unlock()
+	default:
+		e.triggerMu.Unlock()
+		e.logger.WithField("rule_id", rule.ID).Warn("Alert channel full, dropping alert")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, e.config.StoreTimeout)
+	if err := e.store.UpdateTriggerStats(ctx, rule.ID, snapshot.TriggerCount, *snapshot.LastTriggered); err != nil {
+		e.logger.WithError(err).WithField("rule_id", rule.ID).Warn("Failed to update rule trigger stats")
+	}
+	cancel()
+
+	e.metrics.AlertsTotal.WithLabelValues(string(rule.Type), string(rule.Severity)).Inc()
+
+	e.logger.WithFields(logrus.Fields{
+		"rule_id": rule.ID,
+		"source":  event.SourceType,
+		"value":   matchedValue,
+	}).Info("Alert triggered")
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// memoryRuleStore 内存规则存储，记录触发统计的更新次数
+type memoryRuleStore struct {
+	mu           sync.Mutex
+	rules        []*models.AlertRule
+	statsUpdates int
+}
+
+func (s *memoryRuleStore) ListActive(context.Context) ([]*models.AlertRule, error) {
+	return s.rules, nil
+}
+
+func (s *memoryRuleStore) UpdateTriggerStats(context.Context, uint64, uint64, time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statsUpdates++
+	return nil
+}
+
+// newTestEngine 使用给定规则创建并启动引擎
+func newTestEngine(t *testing.T, config *Config, rules ...*models.AlertRule) (*Engine, *memoryRuleStore) {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	store := &memoryRuleStore{rules: rules}
+	e := New(config, store, metrics.NewMetrics("test"), &logger.Logger{Logger: log})
+	if err := e.Start(); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	t.Cleanup(e.Stop)
+	return e, store
+}
+
+// systemHealthRule 没有条件的系统健康规则
+func systemHealthRule(id uint64) *models.AlertRule {
+	return &models.AlertRule{
+		BaseModel:  models.BaseModel{ID: id},
+		Name:       "rpc divergence",
+		Type:       models.AlertTypeSystemHealth,
+		Severity:   models.SeverityHigh,
+		Status:     models.AlertStatusActive,
+		Conditions: `[]`,
+		Operator:   models.OpGreaterThan,
+		TimeWindow: 60,
+		Cooldown:   300,
+	}
+}
+
+// testMismatch 测试用节点结果不一致事件
+func testMismatch() *ethereum.QuorumMismatch {
+	return &ethereum.QuorumMismatch{
+		Method:        "eth_getBlockByNumber",
+		Target:        "100",
+		Endpoint:      "https://rpc.example.com/key",
+		Expected:      "0xaaa",
+		Actual:        "0xbbb",
+		Agreeing:      2,
+		Responding:    3,
+		QuorumReached: true,
+	}
+}
+
+func TestSystemHealthRuleTriggersOnQuorumMismatch(t *testing.T) {
+	e, _ := newTestEngine(t, nil, systemHealthRule(1))
+
+	e.HandleQuorumMismatch(testMismatch())
+
+	select {
+	case alert := <-e.Alerts():
+		if alert.RuleID != 1 || alert.Type != models.AlertTypeSystemHealth {
+			t.Fatalf("unexpected alert: %+v", alert)
+		}
+		data, err := alert.GetTriggerData()
+		if err != nil {
+			t.Fatalf("failed to decode trigger data: %v", err)
+		}
+		if data.MatchedValue != "quorum_mismatch" || data.Context[FieldEndpoint] != "rpc.example.com" {
+			t.Fatalf("unexpected trigger data: %+v", data)
+		}
+	default:
+		t.Fatal("expected an alert for the quorum mismatch")
+	}
+}
+
+func TestDroppedAlertDoesNotConsumeCooldown(t *testing.T) {
+	config := DefaultConfig()
+	config.BufferSize = 1
+	e, store := newTestEngine(t, config, systemHealthRule(1), systemHealthRule(2))
+
+	// 两条规则争用容量为 1 的输出通道，规则 2 的告警被丢弃
+	e.HandleQuorumMismatch(testMismatch())
+	if alert := <-e.Alerts(); alert.RuleID != 1 {
+		t.Fatalf("expected the alert of rule 1, got rule %d", alert.RuleID)
+	}
+	if store.statsUpdates != 1 {
+		t.Fatalf("expected trigger stats only for the enqueued alert, got %d updates", store.statsUpdates)
+	}
+
+	// 规则 1 处于冷却期，规则 2 未占用冷却期，可以再次触发
+	e.HandleQuorumMismatch(testMismatch())
+	select {
+	case alert := <-e.Alerts():
+		if alert.RuleID != 2 || alert.Rule.TriggerCount != 1 {
+			t.Fatalf("expected the first alert of rule 2, got rule %d count %d", alert.RuleID, alert.Rule.TriggerCount)
+		}
+	default:
+		t.Fatal("expected rule 2 to trigger after its alert was dropped")
+	}
+}
@@ -0,0 +1,295 @@
+package engine
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
+)
+
+// 事件来源类型
+const (
+	SourceBlock       = "block"
+	SourceTransaction = "transaction"
+	SourceLog         = "log"
+	SourceGasPrice    = "gas_price"
+	SourceSystem      = "system"
+)
+
+// 区块事件字段
+const (
+	FieldBlockNumber    = "block_number"
+	FieldBlockHash      = "block_hash"
+	FieldMiner          = "miner"
+	FieldGasUsed        = "gas_used"
+	FieldGasLimit       = "gas_limit"
+	FieldGasUtilization = "gas_utilization" // 百分比
+	FieldBaseFeeGwei    = "base_fee_gwei"
+	FieldBlockTime      = "block_time" // 与父区块的时间间隔（秒）
+)
+
+// 交易事件字段
+const (
+	FieldTxHash             = "tx_hash"
+	FieldFrom               = "from"
+	FieldTo                 = "to"
+	FieldValueEth           = "value_eth"
+	FieldGas                = "gas"
+	FieldGasPriceGwei       = "gas_price_gwei"
+	FieldMaxPriorityFeeGwei = "max_priority_fee_gwei"
+	FieldNonce              = "nonce"
+	FieldMethodID           = "method_id"
+	FieldInputSize          = "input_size"
+	FieldIsContractCreation = "is_contract_creation"
+)
+
+// 合约日志事件字段（另包含 FieldTxHash、FieldBlockNumber）
+const (
+	FieldContract       = "contract"
+	FieldLogIndex       = "log_index"
+	FieldTopic0         = "topic0"
+	FieldEventName      = "event_name"
+	FieldEventSignature = "event_signature"
+	FieldTokenStandard  = "token_standard" // erc20、erc721 或 erc1155，仅内置 ABI 解码时存在
+)
+
+// ABI 解码字段，交易事件为调用的方法与输入参数，日志事件为事件参数
+const (
+	FieldMethodName = "method_name"
+	FieldArgPrefix  = "arg." // 参数字段前缀，如 arg.value；整数参数为代币最小单位的数值
+)
+
+// Gas 价格事件字段（另包含 FieldGasPriceGwei、FieldBaseFeeGwei）
+const (
+	FieldFastGwei        = "fast_gwei"
+	FieldInstantGwei     = "instant_gwei"
+	FieldPriorityFeeGwei = "priority_fee_gwei"
+)
+
+// 系统事件字段
+const (
+	FieldComponent = "component"
+	FieldReason    = "reason"
+
+	FieldReorgDepth = "reorg_depth" // 被孤立的区块数量
+
+	FieldEndpoint      = "endpoint"       // 结果不一致的节点主机名
+	FieldRPCMethod     = "rpc_method"     // 法定数量读取的 RPC 方法
+	FieldQuorumTarget  = "quorum_target"  // 读取对象，如区块号、交易哈希
+	FieldExpected      = "expected"       // 多数节点返回的结果
+	FieldActual        = "actual"         // 该节点返回的结果
+	FieldAgreeing      = "agreeing"       // 结果一致的节点数
+	FieldResponding    = "responding"     // 返回结果的节点数
+	FieldQuorumReached = "quorum_reached" // 是否达到法定数量
+)
+
+// 窗口聚合字段，仅出现在聚合规则生成的告警上下文中
+const (
+	FieldWindowValue   = "window_value"   // 窗口聚合值
+	FieldWindowSamples = "window_samples" // 窗口内样本数
+)
+
+// Event 告警引擎的统一输入事件
+// 数值字段统一为 float64，地址与哈希统一为小写字符串，以便与条件值直接比较
+type Event struct {
+	// 来源类型
+	SourceType string
+	// 来源ID（区块哈希、交易哈希等）
+	SourceID string
+	// 字段值
+	Fields map[string]interface{}
+	// 条件表达式使用的类型化字段（tx.*、block.*、gas.*、system.*）
+	Vars expr.Vars
+	// 事件时间
+	Timestamp time.Time
+}
+
+// NewBlockEvent 由区块头构建事件，parent 为空时不计算出块时间
+func NewBlockEvent(header, parent *types.Header) *Event {
+	fields := map[string]interface{}{
+		FieldBlockNumber: float64(header.Number.Uint64()),
+		FieldBlockHash:   strings.ToLower(header.Hash().Hex()),
+		FieldMiner:       strings.ToLower(header.Coinbase.Hex()),
+		FieldGasUsed:     float64(header.GasUsed),
+		FieldGasLimit:    float64(header.GasLimit),
+	}
+
+	if header.GasLimit > 0 {
+		fields[FieldGasUtilization] = float64(header.GasUsed) / float64(header.GasLimit) * 100
+	}
+	if header.BaseFee != nil {
+		fields[FieldBaseFeeGwei] = weiToGwei(header.BaseFee)
+	}
+	if parent != nil && parent.Hash() == header.ParentHash {
+		fields[FieldBlockTime] = float64(header.Time) - float64(parent.Time)
+	}
+
+	return &Event{
+		SourceType: SourceBlock,
+		SourceID:   fields[FieldBlockHash].(string),
+		Fields:     fields,
+		Vars:       ethereum.BlockVars(header),
+		Timestamp:  time.Unix(int64(header.Time), 0),
+	}
+}
+
+// NewTransactionEvent 由交易构建事件，call 为按 ABI 解码的交易输入，无法解码时为空
+func NewTransactionEvent(tx *types.Transaction, signer types.Signer, call *ethereum.DecodedCall) *Event {
+	hash := strings.ToLower(tx.Hash().Hex())
+
+	fields := map[string]interface{}{
+		FieldTxHash:             hash,
+		FieldValueEth:           weiToEther(tx.Value()),
+		FieldGas:                float64(tx.Gas()),
+		FieldGasPriceGwei:       weiToGwei(tx.GasPrice()),
+		FieldNonce:              float64(tx.Nonce()),
+		FieldInputSize:          float64(len(tx.Data())),
+		FieldIsContractCreation: tx.To() == nil,
+	}
+
+	if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType {
+		fields[FieldMaxPriorityFeeGwei] = weiToGwei(tx.GasTipCap())
+	}
+	if from, err := types.Sender(signer, tx); err == nil {
+		fields[FieldFrom] = strings.ToLower(from.Hex())
+	}
+	if to := tx.To(); to != nil {
+		fields[FieldTo] = strings.ToLower(to.Hex())
+	}
+	if len(tx.Data()) >= 4 {
+		fields[FieldMethodID] = hexutil.Encode(tx.Data()[:4])
+	}
+	if call != nil {
+		fields[FieldMethodName] = call.Name
+		addArgFields(fields, call.Args)
+	}
+
+	return &Event{
+		SourceType: SourceTransaction,
+		SourceID:   hash,
+		Fields:     fields,
+		Vars:       ethereum.TransactionVars(tx, signer, call),
+		Timestamp:  time.Now(),
+	}
+}
+
+// NewLogEvent 由合约日志构建事件，decoded 为按 ABI 解码的事件，无法解码时为空
+func NewLogEvent(log *types.Log, decoded *ethereum.DecodedEvent) *Event {
+	txHash := strings.ToLower(log.TxHash.Hex())
+
+	fields := map[string]interface{}{
+		FieldContract:    strings.ToLower(log.Address.Hex()),
+		FieldTxHash:      txHash,
+		FieldBlockNumber: float64(log.BlockNumber),
+		FieldLogIndex:    float64(log.Index),
+	}
+	if len(log.Topics) > 0 {
+		fields[FieldTopic0] = strings.ToLower(log.Topics[0].Hex())
+	}
+	if decoded != nil {
+		fields[FieldEventName] = decoded.Name
+		fields[FieldEventSignature] = decoded.Signature
+		if decoded.Standard != "" {
+			fields[FieldTokenStandard] = decoded.Standard
+		}
+		addArgFields(fields, decoded.Args)
+	}
+
+	timestamp := time.Now()
+	if log.BlockTimestamp > 0 {
+		timestamp = time.Unix(int64(log.BlockTimestamp), 0)
+	}
+
+	return &Event{
+		SourceType: SourceLog,
+		SourceID:   fmt.Sprintf("%s:%d", txHash, log.Index),
+		Fields:     fields,
+		Vars:       ethereum.LogVars(log, decoded),
+		Timestamp:  timestamp,
+	}
+}
+
+// addArgFields 将解码参数加入事件字段，整数转换为 float64，地址与字节已是小写十六进制
+// 数组与结构体参数只能通过条件表达式访问
+func addArgFields(fields map[string]interface{}, args map[string]interface{}) {
+	for name, value := range args {
+		switch v := value.(type) {
+		case *big.Int:
+			f, _ := new(big.Float).SetInt(v).Float64()
+			fields[FieldArgPrefix+name] = f
+		case string, bool:
+			fields[FieldArgPrefix+name] = v
+		}
+	}
+}
+
+// NewGasPriceEvent 由 Gas 价格采样构建事件
+func NewGasPriceEvent(info *ethereum.GasPriceInfo) *Event {
+	fields := map[string]interface{}{}
+
+	if info.Standard != nil {
+		fields[FieldGasPriceGwei] = weiToGwei(info.Standard)
+	}
+	if info.Fast != nil {
+		fields[FieldFastGwei] = weiToGwei(info.Fast)
+	}
+	if info.Instant != nil {
+		fields[FieldInstantGwei] = weiToGwei(info.Instant)
+	}
+	if info.BaseFee != nil {
+		fields[FieldBaseFeeGwei] = weiToGwei(info.BaseFee)
+	}
+	if info.Priority != nil {
+		fields[FieldPriorityFeeGwei] = weiToGwei(info.Priority)
+	}
+
+	return &Event{
+		SourceType: SourceGasPrice,
+		SourceID:   info.Timestamp.UTC().Format(time.RFC3339),
+		Fields:     fields,
+		Vars:       ethereum.GasVars(info),
+		Timestamp:  info.Timestamp,
+	}
+}
+
+// NewSystemEvent 构建系统健康事件
+func NewSystemEvent(component, reason string, fields map[string]interface{}) *Event {
+	all := map[string]interface{}{
+		FieldComponent: component,
+		FieldReason:    reason,
+	}
+	vars := expr.MapVars{}
+	for k, v := range fields {
+		all[k] = v
+	}
+	for k, v := range all {
+		vars["system."+k] = v
+	}
+
+	return &Event{
+		SourceType: SourceSystem,
+		SourceID:   component,
+		Fields:     all,
+		Vars:       vars,
+		Timestamp:  time.Now(),
+	}
+}
+
+// weiToGwei 将 Wei 转换为 Gwei
+func weiToGwei(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(models.WeiPerGwei)).Float64()
+	return f
+}
+
+// weiToEther 将 Wei 转换为 ETH
+func weiToEther(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(models.WeiPerEther)).Float64()
+	return f
+}
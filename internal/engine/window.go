@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// windowBucket 一秒内的样本汇总
+type windowBucket struct {
+	// 秒级时间戳
+	second int64
+	// 样本数
+	count float64
+	// 样本值之和
+	sum float64
+}
+
+// slidingWindow 按秒分桶的滑动窗口，内存占用与窗口秒数成正比，与事件数量无关
+// 非并发安全，由 Engine 加锁访问
+type slidingWindow struct {
+	// 聚合字段，字段变更时窗口重置
+	field string
+	// 按时间升序的分桶
+	buckets []windowBucket
+	// 窗口内样本总数与总和
+	count, sum float64
+}
+
+// add 记录一个样本，早于最新分桶的样本计入最新分桶
+func (w *slidingWindow) add(at time.Time, value float64) {
+	second := at.Unix()
+	if n := len(w.buckets); n > 0 && w.buckets[n-1].second >= second {
+		w.buckets[n-1].count++
+		w.buckets[n-1].sum += value
+	} else {
+		w.buckets = append(w.buckets, windowBucket{second: second, count: 1, sum: value})
+	}
+	w.count++
+	w.sum += value
+}
+
+// prune 移除早于 (now - size) 的分桶
+func (w *slidingWindow) prune(now time.Time, size time.Duration) {
+	cutoff := now.Add(-size).Unix()
+	i := 0
+	for i < len(w.buckets) && w.buckets[i].second <= cutoff {
+		w.count -= w.buckets[i].count
+		w.sum -= w.buckets[i].sum
+		i++
+	}
+	if i > 0 {
+		w.buckets = append(w.buckets[:0], w.buckets[i:]...)
+	}
+	if len(w.buckets) == 0 {
+		// 清零浮点累计误差
+		w.count, w.sum = 0, 0
+	}
+}
+
+// aggregate 计算窗口聚合值，窗口为空时 avg 没有意义，返回 false
+func (w *slidingWindow) aggregate(fn models.AggregationFunc, size time.Duration) (float64, bool) {
+	switch fn {
+	case models.AggregationAvg:
+		if w.count == 0 {
+			return 0, false
+		}
+		return w.sum / w.count, true
+	case models.AggregationSum:
+		return w.sum, true
+	case models.AggregationCount:
+		return w.count, true
+	case models.AggregationRate:
+		return w.count / size.Seconds(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/auth"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// APIKeyHeader API Key 请求头
+const APIKeyHeader = "X-API-Key"
+
+// Authenticator 认证凭证校验，*auth.Service 实现该接口
+type Authenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (*models.User, string, error)
+	AuthenticateAPIKey(ctx context.Context, apiKey string) (*models.User, error)
+}
+
+// AuthMiddleware 认证中间件，接受 Bearer JWT 或 X-API-Key
+type AuthMiddleware struct {
+	authenticator Authenticator
+	logger        *logger.Logger
+}
+
+// NewAuthMiddleware 创建认证中间件
+func NewAuthMiddleware(authenticator Authenticator, logger *logger.Logger) *AuthMiddleware {
+	return &AuthMiddleware{
+		authenticator: authenticator,
+		logger:        logger,
+	}
+}
+
+// Middleware 认证中间件处理函数
+// 未携带凭证的请求原样放行，由 RequireUser 决定是否需要登录；凭证无效时直接返回 401
+func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var (
+			user      *models.User
+			sessionID string
+			err       error
+		)
+		if token, ok := bearerToken(r); ok {
+			user, sessionID, err = m.authenticator.AuthenticateToken(ctx, token)
+		} else if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+			user, err = m.authenticator.AuthenticateAPIKey(ctx, apiKey)
+		} else {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err != nil {
+			if auth.IsAuthError(err) {
+				writeErrorJSON(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			m.logger.WithError(err).Error("Failed to authenticate request")
+			writeErrorJSON(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		ctx = WithUser(ctx, user)
+		if sessionID != "" {
+			ctx = WithSessionID(ctx, sessionID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bearerToken 读取 Authorization: Bearer 令牌
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIPResolver 解析请求的客户端IP地址
+// 默认使用连接的对端地址；仅当对端是受信代理时才读取 X-Forwarded-For，
+// 并从右向左取第一个非受信代理的地址，客户端自行伪造的左侧条目不会被采用
+type ClientIPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientIPResolver 创建客户端IP解析器，trustedProxies 为空时忽略所有转发头
+func NewClientIPResolver(trustedProxies []*net.IPNet) *ClientIPResolver {
+	return &ClientIPResolver{
+		trustedProxies: trustedProxies,
+	}
+}
+
+// ClientIP 获取客户端IP地址
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !c.trusted(remote) {
+		return remote
+	}
+
+	// 多个 X-Forwarded-For 头按出现顺序拼接，最右侧为最近一跳
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			// 无法解析的条目之前的地址均不可信
+			return remote
+		}
+		if !c.trusted(hops[i]) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		// 所有转发地址都是受信代理时取最早的一跳
+		return hops[0]
+	}
+
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(xri) != nil {
+		return xri
+	}
+	return remote
+}
+
+// trusted 判断地址是否属于受信代理
+func (c *ClientIPResolver) trusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range c.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
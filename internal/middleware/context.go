@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// contextKey 请求上下文键类型，避免与其他包冲突
+type contextKey string
+
+const (
+	// userContextKey 已认证用户的上下文键
+	userContextKey contextKey = "user"
+	// sessionContextKey 当前会话标识的上下文键，API Key 认证时为空
+	sessionContextKey contextKey = "session"
+)
+
+// WithUser 将已认证用户写入请求上下文
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext 从请求上下文读取已认证用户
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*models.User)
+	return user, ok && user != nil
+}
+
+// WithSessionID 将当前会话标识写入请求上下文
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionContextKey, sessionID)
+}
+
+// SessionIDFromContext 从请求上下文读取当前会话标识
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(sessionContextKey).(string)
+	return sessionID, ok && sessionID != ""
+}
+
+// RequireUser 要求请求已认证，否则返回 401
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFromContext(r.Context()); !ok {
+			writeErrorJSON(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission 要求已认证用户拥有任一指定权限，否则返回 403
+func RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := UserFromContext(r.Context())
+			if !ok {
+				writeErrorJSON(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			for _, permission := range permissions {
+				if user.HasPermission(permission) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			writeErrorJSON(w, http.StatusForbidden, "permission denied")
+		})
+	}
+}
+
+// writeErrorJSON 写入 JSON 错误响应
+func writeErrorJSON(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body, _ := models.NewErrorResponse(message, status).ToJSON()
+	_, _ = w.Write(body)
+}
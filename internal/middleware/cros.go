@@ -22,7 +22,7 @@ func NewCORSMiddleware(allowedOrigins []string) *CORSMiddleware {
 	return &CORSMiddleware{
 		allowedOrigins: allowedOrigins,
 		allowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		allowedHeaders: []string{"Content-Type", "Authorization", "X-Requested-With"},
+		allowedHeaders: []string{"Content-Type", "Authorization", "X-Requested-With", APIKeyHeader},
 	}
 }
 
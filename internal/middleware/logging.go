@@ -54,14 +54,3 @@ func (m *LoggingMiddleware) Middleware(next http.Handler) http.Handler {
 		m.logger.LogHTTPRequest(r.Method, r.URL.Path, r.UserAgent(), r.RemoteAddr, rw.statusCode, duration)
 	})
 }
-
-// getClientIP 获取客户端IP地址
-func getClientIP(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return xff
-	}
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
-	}
-	return r.RemoteAddr
-}
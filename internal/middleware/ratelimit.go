@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+const (
+	// rateLimitKeyPrefix 限流计数的 Redis 键前缀
+	rateLimitKeyPrefix = "ratelimit:"
+	// preAuthKeyPrefix 认证前按客户端 IP 计数的键前缀，与认证后的 IP 计数分开
+	preAuthKeyPrefix = rateLimitKeyPrefix + "preauth:ip:"
+)
+
+// RateLimiter 滑动窗口限流器，*database.RedisManager 实现该接口
+type RateLimiter interface {
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (*database.RateLimitResult, error)
+}
+
+// RateLimitMiddleware 限流中间件，依次按 API Key、用户、客户端 IP 计数
+type RateLimitMiddleware struct {
+	limiter RateLimiter
+	// 默认窗口内请求次数
+	requests int
+	// 窗口时长
+	window time.Duration
+	// 按角色覆盖的请求次数
+	roleLimits map[string]int
+	// 认证前每个客户端 IP 的窗口内请求次数
+	ipRequests int
+	clientIP   *ClientIPResolver
+	logger     *logger.Logger
+}
+
+// NewRateLimitMiddleware 创建限流中间件
+func NewRateLimitMiddleware(limiter RateLimiter, cfg config.RateLimitConfig, clientIP *ClientIPResolver, logger *logger.Logger) *RateLimitMiddleware {
+	roleLimits, err := cfg.RoleLimits()
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring invalid rate limit role overrides")
+		roleLimits = nil
+	}
+	return &RateLimitMiddleware{
+		limiter:    limiter,
+		requests:   cfg.Requests,
+		window:     cfg.Window,
+		roleLimits: roleLimits,
+		ipRequests: cfg.IPRequests,
+		clientIP:   clientIP,
+		logger:     logger,
+	}
+}
+
+// Middleware 限流中间件处理函数，需位于认证中间件之后以便识别用户
+// Redis 不可用时放行请求，避免限流故障导致整个 API 不可用
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, limit := m.subject(r)
+		if m.allow(w, r, rateLimitKeyPrefix+key, limit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// PreAuthMiddleware 认证前按客户端 IP 限流，需位于认证中间件之前
+// 凭证无效的请求在认证中间件直接返回 401，不经过 Middleware，由此限制凭证猜测与认证查询次数
+func (m *RateLimitMiddleware) PreAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.allow(w, r, preAuthKeyPrefix+m.clientIP.ClientIP(r), m.ipRequests) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allow 判定 key 的本次请求是否放行，写入限流响应头；被拒绝时写入 429 响应
+func (m *RateLimitMiddleware) allow(w http.ResponseWriter, r *http.Request, key string, limit int) bool {
+	result, err := m.limiter.AllowSlidingWindow(r.Context(), key, limit, m.window)
+	if err != nil {
+		m.logger.WithError(err).Warn("Rate limit check failed, allowing request")
+		return true
+	}
+
+	header := w.Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	if !result.Allowed {
+		header.Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		writeErrorJSON(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return false
+	}
+	return true
+}
+
+// subject 确定限流计数键与限额
+// API Key 请求按密钥哈希计数，已登录用户按用户计数，其余按客户端 IP 计数；角色覆盖对已认证请求生效
+func (m *RateLimitMiddleware) subject(r *http.Request) (string, int) {
+	limit := m.requests
+
+	user, ok := UserFromContext(r.Context())
+	if ok {
+		if roleLimit, exists := m.roleLimits[user.Role.String()]; exists {
+			limit = roleLimit
+		}
+	}
+
+	if _, bearer := bearerToken(r); !bearer {
+		if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" && ok {
+			sum := sha256.Sum256([]byte(apiKey))
+			return "apikey:" + hex.EncodeToString(sum[:16]), limit
+		}
+	}
+	if ok {
+		return "user:" + strconv.FormatUint(user.ID, 10), limit
+	}
+	return "ip:" + m.clientIP.ClientIP(r), limit
+}
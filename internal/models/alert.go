@@ -3,6 +3,9 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"strings"
 	"time"
 
@@ -40,6 +43,22 @@ type AlertRule struct {
 	//冷却时间
 	Cooldown int32 `json:"cooldown" validate:"min=0"` // 冷却时间（秒）
 
+	// 窗口聚合配置，为空时逐个事件比较
+	//聚合函数
+	Aggregation AggregationFunc `json:"aggregation" gorm:"type:varchar(20)"`
+	//聚合字段，为空时使用规则类型的主指标
+	AggregationField string `json:"aggregation_field" gorm:"size:100"`
+
+	// 分组配置
+	//分组字段
+	GroupBy string `json:"group_by" gorm:"type:text"` // JSON 数组，触发上下文中的字段名
+	//分组窗口
+	GroupWindow int32 `json:"group_window" validate:"min=0"` // 分组窗口（秒），0 表示不分组
+
+	// 升级配置
+	//升级策略
+	EscalationPolicy string `json:"escalation_policy" gorm:"type:text"` // JSON 格式
+
 	// 通知配置
 	//通知渠道
 	NotificationChannels string `json:"notification_channels" gorm:"type:text"` // JSON 数组
@@ -60,7 +79,7 @@ type AlertRule struct {
 
 	// 关联关系
 	//用户
-	User User `json:"user,omitempty"`
+	User User `json:"user,omitempty" validate:"-"`
 	//告警记录
 	Alerts []Alert `json:"alerts,omitempty"`
 }
@@ -89,6 +108,12 @@ type Alert struct {
 	//触发时间
 	TriggerTime time.Time `json:"trigger_time" gorm:"index"`
 
+	// 分组信息
+	//分组键
+	GroupKey string `json:"group_key" gorm:"size:255;index"`
+	//聚合的告警数量
+	GroupCount int32 `json:"group_count" gorm:"default:1"`
+
 	// 处理状态
 	//状态
 	Status NotificationStatus `json:"status" gorm:"type:varchar(20);index;default:'pending'"` // pending, sent, failed
@@ -103,18 +128,35 @@ type Alert struct {
 	//重试次数
 	RetryCount int32 `json:"retry_count" validate:"min=0"`
 
+	// 生命周期
+	//确认人
+	AcknowledgedBy *uint64 `json:"acknowledged_by"`
+	//确认时间
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	//解决人，自动解决时为空
+	ResolvedBy *uint64 `json:"resolved_by"`
+	//解决时间
+	ResolvedAt *time.Time `json:"resolved_at" gorm:"index"`
+	//静默截止时间
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+	//已执行的升级步骤数
+	EscalationLevel int32 `json:"escalation_level" gorm:"default:0"`
+
 	// 关联关系
-	Rule AlertRule `json:"rule,omitempty"`
+	Rule AlertRule `json:"rule,omitempty" validate:"-"`
 }
 
 // AlertCondition 告警条件结构
+// 设置 Expression 时按表达式求值，忽略 Field/Operator/Value
 type AlertCondition struct {
 	//字段
-	Field string `json:"field" validate:"required"`
+	Field string `json:"field" validate:"required_without=Expression"`
 	//操作符
-	Operator ComparisonOperator `json:"operator" validate:"required"`
+	Operator ComparisonOperator `json:"operator" validate:"required_without=Expression"`
 	//值
-	Value interface{} `json:"value" validate:"required"`
+	Value interface{} `json:"value" validate:"required_without=Expression"`
+	//表达式，如 tx.value > 10 ether && tx.to in watchlist("exchanges")
+	Expression string `json:"expression,omitempty" validate:"max=4096"`
 	//逻辑操作符
 	LogicalOp LogicalOperator `json:"logical_op,omitempty"`
 }
@@ -129,6 +171,95 @@ type NotificationConfig struct {
 	Enabled bool `json:"enabled"`
 	//配置
 	Config map[string]interface{} `json:"config,omitempty"` // 额外配置
+	//摘要频率，为空时实时发送
+	Digest DigestFrequency `json:"digest,omitempty"`
+}
+
+// Validate 验证通知渠道配置
+// Webhook、Slack、Discord 的目标由用户提供，须为指向公网地址的 http(s) URL
+func (c NotificationConfig) Validate() error {
+	if !c.Channel.IsValid() {
+		return errors.New("invalid notification channel")
+	}
+	if c.Digest != "" && !c.Digest.IsValid() {
+		return errors.New("invalid digest frequency")
+	}
+	switch c.Channel {
+	case ChannelWebhook, ChannelSlack, ChannelDiscord:
+		if err := ValidateWebhookURL(c.Target); err != nil {
+			return fmt.Errorf("invalid %s target: %w", c.Channel, err)
+		}
+	}
+	return nil
+}
+
+// ValidateWebhookURL 验证回调 URL：仅允许 http(s)，主机不能是 localhost、本机、内网或链路本地地址
+// 域名在发送时由通知客户端再次检查解析结果
+func ValidateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.New("malformed url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url scheme must be http or https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("url host is required")
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return errors.New("url host must not be localhost")
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return errors.New("url host must be a public address")
+	}
+	return nil
+}
+
+// IsPublicIP 判断地址是否可作为外部回调目标：排除本机、内网、链路本地、组播与未指定地址
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// EscalationPolicy 告警升级策略
+// 告警在触发后超过步骤时长仍未确认时，按步骤依次升级：严重级别升高一级并通知该步骤的渠道
+type EscalationPolicy struct {
+	//升级步骤，按 After 升序
+	Steps []EscalationStep `json:"steps" validate:"required,min=1,dive"`
+	//升级后的最高严重级别，为空时为 critical
+	MaxSeverity AlertSeverity `json:"max_severity,omitempty"`
+}
+
+// EscalationStep 告警升级步骤
+type EscalationStep struct {
+	//自触发起未确认的分钟数
+	After int32 `json:"after" validate:"min=1"`
+	//升级通知渠道
+	Channels []NotificationConfig `json:"channels" validate:"required,min=1,dive"`
+}
+
+// AlertEscalation 告警升级记录，用于审计值班交接
+type AlertEscalation struct {
+	BaseModel
+
+	//告警ID
+	AlertID uint64 `json:"alert_id" gorm:"index;not null"`
+	//规则ID
+	RuleID uint64 `json:"rule_id" gorm:"index;not null"`
+	//升级步骤序号，从 1 开始
+	Level int32 `json:"level" gorm:"not null"`
+	//升级前严重级别
+	FromSeverity AlertSeverity `json:"from_severity" gorm:"type:varchar(20);not null"`
+	//升级后严重级别
+	ToSeverity AlertSeverity `json:"to_severity" gorm:"type:varchar(20);not null"`
+	//通知渠道类型
+	Channels string `json:"channels" gorm:"type:text"` // JSON 数组
+	//是否全部通知成功
+	Notified bool `json:"notified"`
+	//错误信息
+	ErrorMessage string `json:"error_message" gorm:"type:text"`
 }
 
 // AlertTriggerData 告警触发数据结构
@@ -154,6 +285,26 @@ func (Alert) TableName() string {
 	return "alerts"
 }
 
+func (AlertEscalation) TableName() string {
+	return "alert_escalations"
+}
+
+// AlertCondition 相关方法
+
+// Validate 验证条件的操作符或表达式
+func (c AlertCondition) Validate() error {
+	if c.Expression != "" {
+		if _, err := CompileExpression(c.Expression); err != nil {
+			return fmt.Errorf("invalid condition expression: %w", err)
+		}
+		return nil
+	}
+	if !c.Operator.IsValid() {
+		return errors.New("invalid condition operator")
+	}
+	return nil
+}
+
 // AlertRule 相关方法
 
 // BeforeSave 保存前的钩子函数
@@ -200,14 +351,46 @@ func (ar *AlertRule) Validate() error {
 		if err := validate.Struct(condition); err != nil {
 			return errors.New("invalid condition: " + err.Error())
 		}
-		if !condition.Operator.IsValid() {
-			return errors.New("invalid condition operator")
+		if err := condition.Validate(); err != nil {
+			return err
 		}
 		if condition.LogicalOp != "" && !condition.LogicalOp.IsValid() {
 			return errors.New("invalid logical operator")
 		}
 	}
 
+	// 验证窗口聚合
+	if ar.Aggregation != "" && !ar.Aggregation.IsValid() {
+		return errors.New("invalid aggregation function")
+	}
+
+	// 验证分组字段 JSON 格式
+	if _, err := ar.GetGroupBy(); err != nil {
+		return errors.New("invalid group_by format")
+	}
+
+	// 验证升级策略
+	policy, err := ar.GetEscalationPolicy()
+	if err != nil {
+		return errors.New("invalid escalation policy format")
+	}
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证通知渠道摘要频率
+	channels, err := ar.GetNotificationChannels()
+	if err != nil {
+		return errors.New("invalid notification channels format")
+	}
+	for _, channel := range channels {
+		if err := channel.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -248,6 +431,62 @@ func (ar *AlertRule) SetNotificationChannels(channels []NotificationConfig) erro
 	return nil
 }
 
+// GetGroupBy 获取分组字段
+func (ar *AlertRule) GetGroupBy() ([]string, error) {
+	var fields []string
+	if ar.GroupBy == "" {
+		return fields, nil
+	}
+	err := json.Unmarshal([]byte(ar.GroupBy), &fields)
+	return fields, err
+}
+
+// SetGroupBy 设置分组字段
+func (ar *AlertRule) SetGroupBy(fields []string) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	ar.GroupBy = string(data)
+	return nil
+}
+
+// GetEscalationPolicy 获取升级策略，未配置时返回 nil
+func (ar *AlertRule) GetEscalationPolicy() (*EscalationPolicy, error) {
+	if ar.EscalationPolicy == "" || ar.EscalationPolicy == "null" {
+		return nil, nil
+	}
+	var policy EscalationPolicy
+	if err := json.Unmarshal([]byte(ar.EscalationPolicy), &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetEscalationPolicy 设置升级策略，nil 表示清除
+func (ar *AlertRule) SetEscalationPolicy(policy *EscalationPolicy) error {
+	if policy == nil {
+		ar.EscalationPolicy = ""
+		return nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	ar.EscalationPolicy = string(data)
+	return nil
+}
+
+// IsAggregated 检查规则是否按时间窗口聚合评估
+func (ar *AlertRule) IsAggregated() bool {
+	return ar.Aggregation != ""
+}
+
+// IsGrouped 检查规则是否启用分组
+func (ar *AlertRule) IsGrouped() bool {
+	return ar.GroupWindow > 0
+}
+
 // IsActive 检查规则是否激活
 func (ar *AlertRule) IsActive() bool {
 	return ar.Status == AlertStatusActive
@@ -314,6 +553,10 @@ func (ar *AlertRule) EvaluateCondition(condition AlertCondition, value interface
 	case OpNotContains:
 		contains, err := containsValue(value, condition.Value)
 		return !contains, err
+	case OpStartsWith:
+		return matchAffix(value, condition.Value, strings.HasPrefix)
+	case OpEndsWith:
+		return matchAffix(value, condition.Value, strings.HasSuffix)
 	default:
 		return false, errors.New("unsupported operator")
 	}
@@ -329,6 +572,44 @@ func (ar *AlertRule) FromJSON(data []byte) error {
 	return json.Unmarshal(data, ar)
 }
 
+// Validate 验证升级策略
+func (p *EscalationPolicy) Validate() error {
+	if err := validator.New().Struct(p); err != nil {
+		return errors.New("invalid escalation policy: " + err.Error())
+	}
+	if p.MaxSeverity != "" && !p.MaxSeverity.IsValid() {
+		return errors.New("invalid escalation max_severity")
+	}
+	for i, step := range p.Steps {
+		if i > 0 && step.After <= p.Steps[i-1].After {
+			return errors.New("escalation steps must be in ascending order of after")
+		}
+		for _, channel := range step.Channels {
+			if err := channel.Validate(); err != nil {
+				return errors.New("invalid escalation notification channel: " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// NextStep 返回告警下一个待执行的升级步骤及其到期时间，已无步骤时返回 nil
+func (p *EscalationPolicy) NextStep(alert *Alert) (*EscalationStep, time.Time) {
+	if int(alert.EscalationLevel) >= len(p.Steps) {
+		return nil, time.Time{}
+	}
+	step := &p.Steps[alert.EscalationLevel]
+	return step, alert.TriggerTime.Add(time.Duration(step.After) * time.Minute)
+}
+
+// GetMaxSeverity 返回升级的最高严重级别
+func (p *EscalationPolicy) GetMaxSeverity() AlertSeverity {
+	if p.MaxSeverity == "" {
+		return SeverityCritical
+	}
+	return p.MaxSeverity
+}
+
 // Alert 相关方法
 
 // Validate 验证告警记录
@@ -392,6 +673,12 @@ func (a *Alert) MarkAsFailed(errorMsg string) {
 	a.RetryCount++
 }
 
+// MarkAsSuppressed 标记为被限流抑制，reason 记录抑制原因
+func (a *Alert) MarkAsSuppressed(reason string) {
+	a.Status = NotificationStatusSuppressed
+	a.ErrorMessage = reason
+}
+
 // CanRetry 检查是否可以重试
 func (a *Alert) CanRetry() bool {
 	return a.Status == NotificationStatusFailed && a.RetryCount < 3
@@ -399,7 +686,36 @@ func (a *Alert) CanRetry() bool {
 
 // IsResolved 检查告警是否已解决
 func (a *Alert) IsResolved() bool {
-	return a.Status == NotificationStatusSent
+	return a.ResolvedAt != nil
+}
+
+// IsAcknowledged 检查告警是否已确认
+func (a *Alert) IsAcknowledged() bool {
+	return a.AcknowledgedAt != nil
+}
+
+// IsSnoozed 检查告警在指定时间是否处于静默期
+func (a *Alert) IsSnoozed(at time.Time) bool {
+	return a.SnoozedUntil != nil && at.Before(*a.SnoozedUntil)
+}
+
+// Acknowledge 由指定用户确认告警
+func (a *Alert) Acknowledge(userID uint64) {
+	now := time.Now()
+	a.AcknowledgedBy = &userID
+	a.AcknowledgedAt = &now
+}
+
+// Resolve 解决告警，resolvedBy 为空表示自动解决
+func (a *Alert) Resolve(resolvedBy *uint64, at time.Time) {
+	a.ResolvedBy = resolvedBy
+	a.ResolvedAt = &at
+	a.SnoozedUntil = nil
+}
+
+// Snooze 静默告警至指定时间
+func (a *Alert) Snooze(until time.Time) {
+	a.SnoozedUntil = &until
 }
 
 // GetAge 获取告警年龄（从触发到现在的时间）
@@ -432,6 +748,45 @@ type AlertRuleQueryParams struct {
 	UserID    *uint64       `json:"user_id"`
 }
 
+// BuildWhereClause 构建查询条件，未指定状态时排除已删除的规则
+func (q *AlertRuleQueryParams) BuildWhereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if q.UserID != nil {
+		add("user_id = $%d", *q.UserID)
+	}
+	if q.Name != "" {
+		add("name ILIKE $%d", "%"+q.Name+"%")
+	}
+	if q.AlertType != "" {
+		add("type = $%d", q.AlertType)
+	}
+	if q.Severity != "" {
+		add("severity = $%d", q.Severity)
+	}
+	if q.Status != "" {
+		add("status = $%d", q.Status)
+	} else {
+		add("status <> $%d", AlertStatusDeleted)
+	}
+
+	// 时间范围
+	if q.StartTime != nil {
+		add("created_at >= $%d", *q.StartTime)
+	}
+	if q.EndTime != nil {
+		add("created_at <= $%d", *q.EndTime)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // AlertQueryParams 告警记录查询参数
 type AlertQueryParams struct {
 	PaginationParams
@@ -445,10 +800,80 @@ type AlertQueryParams struct {
 	NotificationSent *bool              `json:"notification_sent"`
 	MinTriggerValue  *float64           `json:"min_trigger_value"`
 	MaxTriggerValue  *float64           `json:"max_trigger_value"`
+	Acknowledged     *bool              `json:"acknowledged"`
+	Resolved         *bool              `json:"resolved"`
+}
+
+// BuildWhereClause 构建查询条件，指定用户时只返回该用户规则产生的告警
+func (q *AlertQueryParams) BuildWhereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if q.UserID != nil {
+		add("rule_id IN (SELECT id FROM alert_rules WHERE user_id = $%d)", *q.UserID)
+	}
+	if q.RuleID != nil {
+		add("rule_id = $%d", *q.RuleID)
+	}
+	if q.AlertType != "" {
+		add("type = $%d", q.AlertType)
+	}
+	if q.Severity != "" {
+		add("severity = $%d", q.Severity)
+	}
+	if q.Status != "" {
+		add("status = $%d", q.Status)
+	}
+	if q.NotificationSent != nil {
+		add("notification_sent = $%d", *q.NotificationSent)
+	}
+	if q.MinTriggerValue != nil {
+		add("trigger_value >= $%d", *q.MinTriggerValue)
+	}
+	if q.MaxTriggerValue != nil {
+		add("trigger_value <= $%d", *q.MaxTriggerValue)
+	}
+	if q.Acknowledged != nil {
+		conditions = append(conditions, nullCondition("acknowledged_at", *q.Acknowledged))
+	}
+	if q.Resolved != nil {
+		conditions = append(conditions, nullCondition("resolved_at", *q.Resolved))
+	}
+
+	// 时间范围
+	if q.StartTime != nil {
+		add("trigger_time >= $%d", *q.StartTime)
+	}
+	if q.EndTime != nil {
+		add("trigger_time <= $%d", *q.EndTime)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullCondition 按列是否为空构建查询条件
+func nullCondition(column string, notNull bool) string {
+	if notNull {
+		return column + " IS NOT NULL"
+	}
+	return column + " IS NULL"
 }
 
 // 请求结构
 
+// SnoozeAlertRequest 静默告警请求
+type SnoozeAlertRequest struct {
+	Duration int32 `json:"duration" validate:"required,min=60,max=604800"` // 静默时长（秒），最长 7 天
+}
+
 // CreateAlertRuleRequest 创建告警规则请求
 type CreateAlertRuleRequest struct {
 	Name                 string               `json:"name" validate:"required,min=1,max=255"`
@@ -460,6 +885,11 @@ type CreateAlertRuleRequest struct {
 	Operator             ComparisonOperator   `json:"operator" validate:"required"`
 	TimeWindow           int32                `json:"time_window" validate:"min=1"`
 	Cooldown             int32                `json:"cooldown" validate:"min=0"`
+	Aggregation          AggregationFunc      `json:"aggregation"`
+	AggregationField     string               `json:"aggregation_field" validate:"max=100"`
+	GroupBy              []string             `json:"group_by"`
+	GroupWindow          int32                `json:"group_window" validate:"min=0"`
+	EscalationPolicy     *EscalationPolicy    `json:"escalation_policy"`
 	NotificationChannels []NotificationConfig `json:"notification_channels"`
 	NotificationTemplate string               `json:"notification_template"`
 }
@@ -478,7 +908,13 @@ func (r *CreateAlertRuleRequest) ToAlertRule(userID uint64) (*AlertRule, error)
 		return nil, err
 	}
 
-	return &AlertRule{
+	// 序列化分组字段
+	groupByJSON, err := json.Marshal(r.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &AlertRule{
 		Name:                 r.Name,
 		Description:          r.Description,
 		Type:                 r.Type,
@@ -489,10 +925,18 @@ func (r *CreateAlertRuleRequest) ToAlertRule(userID uint64) (*AlertRule, error)
 		Operator:             r.Operator,
 		TimeWindow:           r.TimeWindow,
 		Cooldown:             r.Cooldown,
+		Aggregation:          r.Aggregation,
+		AggregationField:     r.AggregationField,
+		GroupBy:              string(groupByJSON),
+		GroupWindow:          r.GroupWindow,
 		NotificationChannels: string(channelsJSON),
 		NotificationTemplate: r.NotificationTemplate,
 		UserID:               userID,
-	}, nil
+	}
+	if err := rule.SetEscalationPolicy(r.EscalationPolicy); err != nil {
+		return nil, err
+	}
+	return rule, nil
 }
 
 // Validate 验证创建请求
@@ -502,11 +946,19 @@ func (r *CreateAlertRuleRequest) Validate() error {
 		return err
 	}
 
-	// 验证条件
+	// 验证条件，表达式在此编译以便创建规则时报告语法与类型错误
 	for _, condition := range r.Conditions {
 		if err := validate.Struct(condition); err != nil {
 			return err
 		}
+		if err := condition.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证窗口聚合
+	if r.Aggregation != "" && !r.Aggregation.IsValid() {
+		return errors.New("invalid aggregation function")
 	}
 
 	// 验证通知渠道
@@ -514,6 +966,9 @@ func (r *CreateAlertRuleRequest) Validate() error {
 		if err := validate.Struct(channel); err != nil {
 			return err
 		}
+		if err := channel.Validate(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -530,6 +985,12 @@ type UpdateAlertRuleRequest struct {
 	Operator             *ComparisonOperator   `json:"operator"`
 	TimeWindow           *int32                `json:"time_window" validate:"omitempty,min=1"`
 	Cooldown             *int32                `json:"cooldown" validate:"omitempty,min=0"`
+	Aggregation          *AggregationFunc      `json:"aggregation"` // 空字符串表示取消聚合
+	AggregationField     *string               `json:"aggregation_field" validate:"omitempty,max=100"`
+	GroupBy              *[]string             `json:"group_by"`
+	GroupWindow          *int32                `json:"group_window" validate:"omitempty,min=0"`
+	EscalationPolicy     *EscalationPolicy     `json:"escalation_policy"`
+	ClearEscalation      bool                  `json:"clear_escalation"` // 为 true 时移除升级策略
 	NotificationChannels *[]NotificationConfig `json:"notification_channels"`
 	NotificationTemplate *string               `json:"notification_template"`
 }
@@ -565,6 +1026,27 @@ func (r *UpdateAlertRuleRequest) ApplyToAlertRule(rule *AlertRule) error {
 	if r.Cooldown != nil {
 		rule.Cooldown = *r.Cooldown
 	}
+	if r.Aggregation != nil {
+		rule.Aggregation = *r.Aggregation
+	}
+	if r.AggregationField != nil {
+		rule.AggregationField = *r.AggregationField
+	}
+	if r.GroupBy != nil {
+		if err := rule.SetGroupBy(*r.GroupBy); err != nil {
+			return err
+		}
+	}
+	if r.GroupWindow != nil {
+		rule.GroupWindow = *r.GroupWindow
+	}
+	if r.ClearEscalation {
+		rule.EscalationPolicy = ""
+	} else if r.EscalationPolicy != nil {
+		if err := rule.SetEscalationPolicy(r.EscalationPolicy); err != nil {
+			return err
+		}
+	}
 	if r.NotificationChannels != nil {
 		if err := rule.SetNotificationChannels(*r.NotificationChannels); err != nil {
 			return err
@@ -627,3 +1109,15 @@ func containsValue(haystack, needle interface{}) (bool, error) {
 
 	return false, errors.New("unsupported types for contains operation")
 }
+
+// matchAffix 检查字符串前缀/后缀
+func matchAffix(value, affix interface{}, match func(string, string) bool) (bool, error) {
+	valueStr, ok1 := value.(string)
+	affixStr, ok2 := affix.(string)
+
+	if ok1 && ok2 {
+		return match(valueStr, affixStr), nil
+	}
+
+	return false, errors.New("unsupported types for prefix/suffix operation")
+}
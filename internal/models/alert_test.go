@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXX", true},
+		{"http://example.com:8080/hook", true},
+		{"https://8.8.8.8/hook", true},
+		{"ftp://example.com/hook", false},
+		{"file:///etc/passwd", false},
+		{"https:///no-host", false},
+		{"http://localhost:9090/metrics", false},
+		{"http://api.localhost/", false},
+		{"http://127.0.0.1/", false},
+		{"http://10.0.0.5/", false},
+		{"http://192.168.1.1/", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[::1]:8080/", false},
+		{"http://[fd00::1]/", false},
+		{"http://0.0.0.0/", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateWebhookURL(tt.url)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want valid=%v", tt.url, err, tt.valid)
+		}
+	}
+}
+
+func TestNotificationConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config NotificationConfig
+		valid  bool
+	}{
+		{"public webhook", NotificationConfig{Channel: ChannelWebhook, Target: "https://example.com/hook"}, true},
+		{"internal webhook", NotificationConfig{Channel: ChannelWebhook, Target: "http://10.1.2.3/hook"}, false},
+		{"internal slack", NotificationConfig{Channel: ChannelSlack, Target: "http://127.0.0.1/"}, false},
+		{"internal discord", NotificationConfig{Channel: ChannelDiscord, Target: "http://localhost/"}, false},
+		{"email is not a url", NotificationConfig{Channel: ChannelEmail, Target: "ops@example.com"}, true},
+		{"unknown channel", NotificationConfig{Channel: "pager", Target: "x"}, false},
+		{"invalid digest", NotificationConfig{Channel: ChannelEmail, Target: "ops@example.com", Digest: "weekly"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid=%v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+// BackfillStatus 回填任务状态
+type BackfillStatus string
+
+const (
+	BackfillStatusRunning   BackfillStatus = "running"   // 进行中
+	BackfillStatusCompleted BackfillStatus = "completed" // 已完成
+	BackfillStatusFailed    BackfillStatus = "failed"    // 失败
+)
+
+// BackfillCheckpoint 历史区块回填进度
+type BackfillCheckpoint struct {
+	BaseModel
+
+	// 任务名称，同名任务从上次进度继续
+	Name string `json:"name" validate:"required,max=100"`
+	// 起始区块号
+	StartBlock uint64 `json:"start_block"`
+	// 结束区块号（包含）
+	EndBlock uint64 `json:"end_block" validate:"gtefield=StartBlock"`
+	// 已完整写入的最后一个区块号，为空表示尚未写入
+	LastBlock *uint64 `json:"last_block"`
+	// 任务状态
+	Status BackfillStatus `json:"status"`
+}
+
+// TableName 指定表名
+func (BackfillCheckpoint) TableName() string {
+	return "backfill_checkpoints"
+}
+
+// NextBlock 返回下一个待回填的区块号
+func (c *BackfillCheckpoint) NextBlock() uint64 {
+	if c.LastBlock == nil {
+		return c.StartBlock
+	}
+	return *c.LastBlock + 1
+}
+
+// IsCompleted 检查回填是否已完成
+func (c *BackfillCheckpoint) IsCompleted() bool {
+	return c.LastBlock != nil && *c.LastBlock >= c.EndBlock
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -332,43 +333,43 @@ func (q *BlockQueryParams) BuildWhereClause() (string, []interface{}) {
 
 	// 区块号范围
 	if q.MinNumber != nil {
-		conditions = append(conditions, "number >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "number >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MinNumber)
 		argIndex++
 	}
 	if q.MaxNumber != nil {
-		conditions = append(conditions, "number <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "number <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MaxNumber)
 		argIndex++
 	}
 
 	// 矿工过滤
 	if q.Miner != "" {
-		conditions = append(conditions, "miner = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "miner = $"+strconv.Itoa(argIndex))
 		args = append(args, q.Miner)
 		argIndex++
 	}
 
 	// Gas 使用量范围
 	if q.MinGasUsed != nil {
-		conditions = append(conditions, "gas_used >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "gas_used >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MinGasUsed)
 		argIndex++
 	}
 	if q.MaxGasUsed != nil {
-		conditions = append(conditions, "gas_used <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "gas_used <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MaxGasUsed)
 		argIndex++
 	}
 
 	// 交易数量范围
 	if q.MinTxCount != nil {
-		conditions = append(conditions, "transaction_count >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "transaction_count >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MinTxCount)
 		argIndex++
 	}
 	if q.MaxTxCount != nil {
-		conditions = append(conditions, "transaction_count <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "transaction_count <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MaxTxCount)
 		argIndex++
 	}
@@ -384,12 +385,12 @@ func (q *BlockQueryParams) BuildWhereClause() (string, []interface{}) {
 
 	// 时间范围
 	if q.StartTime != nil {
-		conditions = append(conditions, "timestamp >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "timestamp >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.StartTime)
 		argIndex++
 	}
 	if q.EndTime != nil {
-		conditions = append(conditions, "timestamp <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "timestamp <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.EndTime)
 		argIndex++
 	}
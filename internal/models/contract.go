@@ -0,0 +1,74 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-playground/validator/v10"
+
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+)
+
+// ContractABI 合约 ABI，用于将合约日志与交易输入解码为具名参数
+type ContractABI struct {
+	BaseModel
+
+	//合约地址（校验和格式）
+	Address string `json:"address" gorm:"size:42;uniqueIndex;not null"`
+	//合约名称
+	Name string `json:"name" gorm:"size:255"`
+	//ABI 定义
+	ABI string `json:"abi" gorm:"type:text;not null"` // JSON 数组
+	//上传用户ID
+	UserID uint64 `json:"user_id" gorm:"index"`
+}
+
+// TableName 指定表名
+func (ContractABI) TableName() string {
+	return "contract_abis"
+}
+
+// 合约 ABI 上传请求结构
+type UploadContractABIRequest struct {
+	Address string `json:"address" validate:"required,len=42"`
+	Name    string `json:"name" validate:"max=255"`
+	// ABI JSON 数组，也接受编译工具输出的 JSON 字符串形式
+	ABI json.RawMessage `json:"abi" validate:"required"`
+}
+
+// Validate 验证上传请求，ABI 须能被解析且至少包含一个事件或方法
+func (r *UploadContractABIRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if !common.IsHexAddress(r.Address) {
+		return errors.New("invalid contract address")
+	}
+	_, err := ethereum.ParseABI(r.definition())
+	return err
+}
+
+// ToContractABI 转换为合约 ABI 模型，地址统一为校验和格式
+func (r *UploadContractABIRequest) ToContractABI(userID uint64) *ContractABI {
+	return &ContractABI{
+		Address: common.HexToAddress(r.Address).Hex(),
+		Name:    r.Name,
+		ABI:     r.definition(),
+		UserID:  userID,
+	}
+}
+
+// definition 返回 ABI JSON 文本，字符串形式的 ABI 先去除一层引号
+func (r *UploadContractABIRequest) definition() string {
+	raw := bytes.TrimSpace(r.ABI)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s
+		}
+	}
+	return string(raw)
+}
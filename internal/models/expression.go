@@ -0,0 +1,28 @@
+package models
+
+import (
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
+)
+
+// AlertExpressionEnv 告警条件表达式可引用的字段
+// 在链上字段（tx.*、block.*、log.*、gas.*）的基础上增加系统健康事件字段
+var AlertExpressionEnv = ethereum.ExpressionEnv.Merge(expr.NewEnv(map[string]expr.Type{
+	"system.component":      expr.TypeString,
+	"system.reason":         expr.TypeString,
+	"system.reorg_depth":    expr.TypeNumber,
+	"system.block_number":   expr.TypeNumber,
+	"system.endpoint":       expr.TypeString,
+	"system.rpc_method":     expr.TypeString,
+	"system.quorum_target":  expr.TypeString,
+	"system.expected":       expr.TypeString,
+	"system.actual":         expr.TypeString,
+	"system.agreeing":       expr.TypeNumber,
+	"system.responding":     expr.TypeNumber,
+	"system.quorum_reached": expr.TypeBool,
+}))
+
+// CompileExpression 编译告警条件表达式
+func CompileExpression(source string) (*expr.Program, error) {
+	return expr.Compile(source, AlertExpressionEnv)
+}
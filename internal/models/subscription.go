@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -49,7 +50,7 @@ type Subscription struct {
 	ExpiresAt *time.Time `json:"expires_at"`
 
 	// 关联关系
-	User User `json:"user,omitempty"`
+	User User `json:"user,omitempty" validate:"-"`
 }
 
 // SubscriptionConfig 订阅配置基础结构
@@ -185,6 +186,17 @@ func (s *Subscription) Validate() error {
 		}
 	}
 
+	// 验证通知渠道
+	channels, err := s.GetNotificationChannels()
+	if err != nil {
+		return errors.New("invalid notification channels format")
+	}
+	for _, channel := range channels {
+		if err := channel.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -459,28 +471,28 @@ func (q *SubscriptionQueryParams) BuildWhereClause() (string, []interface{}) {
 
 	// 名称过滤
 	if q.Name != "" {
-		conditions = append(conditions, "name ILIKE $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "name ILIKE $"+strconv.Itoa(argIndex))
 		args = append(args, "%"+q.Name+"%")
 		argIndex++
 	}
 
 	// 类型过滤
 	if q.Type != "" {
-		conditions = append(conditions, "type = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "type = $"+strconv.Itoa(argIndex))
 		args = append(args, q.Type)
 		argIndex++
 	}
 
 	// 状态过滤
 	if q.Status != "" {
-		conditions = append(conditions, "status = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "status = $"+strconv.Itoa(argIndex))
 		args = append(args, q.Status)
 		argIndex++
 	}
 
 	// 用户过滤
 	if q.UserID != nil {
-		conditions = append(conditions, "user_id = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "user_id = $"+strconv.Itoa(argIndex))
 		args = append(args, *q.UserID)
 		argIndex++
 	}
@@ -490,18 +502,18 @@ func (q *SubscriptionQueryParams) BuildWhereClause() (string, []interface{}) {
 		if *q.Expired {
 			conditions = append(conditions, "expires_at IS NOT NULL AND expires_at < NOW()")
 		} else {
-			conditions = append(conditions, "expires_at IS NULL OR expires_at >= NOW()")
+			conditions = append(conditions, "(expires_at IS NULL OR expires_at >= NOW())")
 		}
 	}
 
 	// 时间范围
 	if q.StartTime != nil {
-		conditions = append(conditions, "created_at >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "created_at >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.StartTime)
 		argIndex++
 	}
 	if q.EndTime != nil {
-		conditions = append(conditions, "created_at <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "created_at <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.EndTime)
 		argIndex++
 	}
@@ -598,6 +610,9 @@ func (r *CreateSubscriptionRequest) Validate() error {
 		if err := validate.Struct(channel); err != nil {
 			return err
 		}
+		if err := channel.Validate(); err != nil {
+			return err
+		}
 	}
 
 	return nil
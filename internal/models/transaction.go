@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
 )
 
 // Transaction 交易数据模型
@@ -83,6 +86,10 @@ type TransactionLog struct {
 	BlockNumber     uint64    `json:"block_number" gorm:"index"`
 	Removed         bool      `json:"removed"`
 	
+	// ABI 解码结果，无匹配 ABI 时为空
+	EventName       string    `json:"event_name" gorm:"size:255;index"`
+	Decoded         string    `json:"decoded" gorm:"type:text"` // JSON 对象
+	
 	// 关联关系
 	Transaction Transaction `json:"transaction,omitempty"`
 	Block       Block       `json:"block,omitempty"`
@@ -359,6 +366,33 @@ func (tl *TransactionLog) SetTopics(topics []string) error {
 	return nil
 }
 
+// GetDecoded 获取解析后的解码结果，未解码时返回 nil
+func (tl *TransactionLog) GetDecoded() (*ethereum.DecodedEvent, error) {
+	if tl.Decoded == "" {
+		return nil, nil
+	}
+	
+	var event ethereum.DecodedEvent
+	err := json.Unmarshal([]byte(tl.Decoded), &event)
+	return &event, err
+}
+
+// SetDecoded 设置解码结果，nil 表示未解码
+func (tl *TransactionLog) SetDecoded(event *ethereum.DecodedEvent) error {
+	if event == nil {
+		tl.EventName = ""
+		tl.Decoded = ""
+		return nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	tl.EventName = event.Name
+	tl.Decoded = string(data)
+	return nil
+}
+
 // ToJSON 序列化为 JSON
 func (tl *TransactionLog) ToJSON() ([]byte, error) {
 	return json.Marshal(tl)
@@ -398,43 +432,43 @@ func (q *TransactionQueryParams) BuildWhereClause() (string, []interface{}) {
 	
 	// 区块号过滤
 	if q.BlockNumber != nil {
-		conditions = append(conditions, "block_number = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "block_number = $"+strconv.Itoa(argIndex))
 		args = append(args, *q.BlockNumber)
 		argIndex++
 	}
 	if q.MinBlockNumber != nil {
-		conditions = append(conditions, "block_number >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "block_number >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MinBlockNumber)
 		argIndex++
 	}
 	if q.MaxBlockNumber != nil {
-		conditions = append(conditions, "block_number <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "block_number <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.MaxBlockNumber)
 		argIndex++
 	}
 	
 	// 地址过滤
 	if q.FromAddress != "" {
-		conditions = append(conditions, "from_address = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "from_address = $"+strconv.Itoa(argIndex))
 		args = append(args, q.FromAddress)
 		argIndex++
 	}
 	if q.ToAddress != "" {
-		conditions = append(conditions, "to_address = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "to_address = $"+strconv.Itoa(argIndex))
 		args = append(args, q.ToAddress)
 		argIndex++
 	}
 	
 	// 交易类型过滤
 	if q.TxType != "" {
-		conditions = append(conditions, "type = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "type = $"+strconv.Itoa(argIndex))
 		args = append(args, q.TxType)
 		argIndex++
 	}
 	
 	// 交易状态过滤
 	if q.TxStatus != "" {
-		conditions = append(conditions, "status = $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "status = $"+strconv.Itoa(argIndex))
 		args = append(args, q.TxStatus)
 		argIndex++
 	}
@@ -446,12 +480,12 @@ func (q *TransactionQueryParams) BuildWhereClause() (string, []interface{}) {
 	
 	// 时间范围
 	if q.StartTime != nil {
-		conditions = append(conditions, "timestamp >= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "timestamp >= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.StartTime)
 		argIndex++
 	}
 	if q.EndTime != nil {
-		conditions = append(conditions, "timestamp <= $"+string(rune(argIndex+'0')))
+		conditions = append(conditions, "timestamp <= $"+strconv.Itoa(argIndex))
 		args = append(args, *q.EndTime)
 		argIndex++
 	}
@@ -467,6 +501,76 @@ func (q *TransactionQueryParams) BuildWhereClause() (string, []interface{}) {
 	return whereClause, args
 }
 
+// 交易日志查询参数
+type TransactionLogQueryParams struct {
+	PaginationParams
+
+	// 交易日志特定过滤条件
+	TransactionHash string  `json:"transaction_hash"`
+	Address         string  `json:"address"`
+	Topic0          string  `json:"topic0"`
+	EventName       string  `json:"event_name"`
+	MinBlockNumber  *uint64 `json:"min_block_number"`
+	MaxBlockNumber  *uint64 `json:"max_block_number"`
+	IncludeRemoved  bool    `json:"include_removed"`
+}
+
+// BuildWhereClause 构建查询条件，默认排除已被重组移除的日志
+func (q *TransactionLogQueryParams) BuildWhereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if q.TransactionHash != "" {
+		conditions = append(conditions, "transaction_hash = $"+strconv.Itoa(argIndex))
+		args = append(args, q.TransactionHash)
+		argIndex++
+	}
+	if q.Address != "" {
+		conditions = append(conditions, "address = $"+strconv.Itoa(argIndex))
+		args = append(args, q.Address)
+		argIndex++
+	}
+
+	// 主题以 JSON 数组存储，事件签名为第一个元素
+	if q.Topic0 != "" {
+		conditions = append(conditions, "topics::jsonb->>0 = $"+strconv.Itoa(argIndex))
+		args = append(args, q.Topic0)
+		argIndex++
+	}
+	if q.EventName != "" {
+		conditions = append(conditions, "event_name = $"+strconv.Itoa(argIndex))
+		args = append(args, q.EventName)
+		argIndex++
+	}
+
+	// 区块号范围
+	if q.MinBlockNumber != nil {
+		conditions = append(conditions, "block_number >= $"+strconv.Itoa(argIndex))
+		args = append(args, *q.MinBlockNumber)
+		argIndex++
+	}
+	if q.MaxBlockNumber != nil {
+		conditions = append(conditions, "block_number <= $"+strconv.Itoa(argIndex))
+		args = append(args, *q.MaxBlockNumber)
+		argIndex++
+	}
+
+	if !q.IncludeRemoved {
+		conditions = append(conditions, "removed = FALSE")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + conditions[0]
+		for i := 1; i < len(conditions); i++ {
+			whereClause += " AND " + conditions[i]
+		}
+	}
+
+	return whereClause, args
+}
+
 // 交易创建请求结构
 type CreateTransactionRequest struct {
 	Hash                 string            `json:"hash" validate:"required,len=66"`
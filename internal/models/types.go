@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // TransactionType 交易类型枚举
 type TransactionType string
 
@@ -122,6 +124,26 @@ func (s AlertSeverity) GetPriority() int {
 	}
 }
 
+// Escalate 返回升高一级后的严重级别，不超过 max
+func (s AlertSeverity) Escalate(max AlertSeverity) AlertSeverity {
+	next := s
+	switch s {
+	case SeverityLow:
+		next = SeverityMedium
+	case SeverityMedium:
+		next = SeverityHigh
+	case SeverityHigh, SeverityCritical:
+		next = SeverityCritical
+	}
+	if max.IsValid() && next.GetPriority() > max.GetPriority() {
+		if s.GetPriority() > max.GetPriority() {
+			return s
+		}
+		return max
+	}
+	return next
+}
+
 // AlertStatus 告警状态
 type AlertStatus string
 
@@ -199,6 +221,16 @@ func (r UserRole) IsValid() bool {
 	}
 }
 
+// 权限定义
+const (
+	PermissionRead               = "read"                // 读取
+	PermissionWrite              = "write"               // 写入
+	PermissionAlertCreate        = "alert_create"        // 创建并管理自己的告警规则
+	PermissionAlertManage        = "alert_manage"        // 管理所有用户的告警规则
+	PermissionSubscriptionManage = "subscription_manage" // 管理订阅
+	PermissionUserManage         = "user_manage"         // 管理用户角色与状态，仅管理员拥有
+)
+
 // HasPermission 检查角色是否有指定权限
 func (r UserRole) HasPermission(permission string) bool {
 	switch r {
@@ -207,7 +239,7 @@ func (r UserRole) HasPermission(permission string) bool {
 	case RoleOperator:
 		// 操作员权限
 		switch permission {
-		case "read", "write", "alert_manage":
+		case PermissionRead, PermissionWrite, PermissionAlertManage:
 			return true
 		default:
 			return false
@@ -215,14 +247,14 @@ func (r UserRole) HasPermission(permission string) bool {
 	case RoleUser:
 		// 普通用户权限
 		switch permission {
-		case "read", "alert_create", "subscription_manage":
+		case PermissionRead, PermissionAlertCreate, PermissionSubscriptionManage:
 			return true
 		default:
 			return false
 		}
 	case RoleViewer:
 		// 只读用户权限
-		return permission == "read"
+		return permission == PermissionRead
 	default:
 		return false
 	}
@@ -396,14 +428,45 @@ func (l LogicalOperator) IsValid() bool {
 	}
 }
 
+// AggregationFunc 窗口聚合函数
+type AggregationFunc string
+
+const (
+	AggregationAvg   AggregationFunc = "avg"   // 平均值
+	AggregationSum   AggregationFunc = "sum"   // 求和
+	AggregationCount AggregationFunc = "count" // 事件数
+	AggregationRate  AggregationFunc = "rate"  // 每秒事件数
+)
+
+// String 返回字符串表示
+func (a AggregationFunc) String() string {
+	return string(a)
+}
+
+// IsValid 验证聚合函数是否有效
+func (a AggregationFunc) IsValid() bool {
+	switch a {
+	case AggregationAvg, AggregationSum, AggregationCount, AggregationRate:
+		return true
+	default:
+		return false
+	}
+}
+
+// NeedsField 检查聚合函数是否需要数值字段，count 与 rate 只统计事件数
+func (a AggregationFunc) NeedsField() bool {
+	return a == AggregationAvg || a == AggregationSum
+}
+
 // NotificationStatus 通知状态
 type NotificationStatus string
 
 const (
-	NotificationStatusPending NotificationStatus = "pending" // 待发送
-	NotificationStatusSent    NotificationStatus = "sent"    // 已发送
-	NotificationStatusFailed  NotificationStatus = "failed"  // 发送失败
-	NotificationStatusRetry   NotificationStatus = "retry"   // 重试中
+	NotificationStatusPending    NotificationStatus = "pending"    // 待发送
+	NotificationStatusSent       NotificationStatus = "sent"       // 已发送
+	NotificationStatusFailed     NotificationStatus = "failed"     // 发送失败
+	NotificationStatusRetry      NotificationStatus = "retry"      // 重试中
+	NotificationStatusSuppressed NotificationStatus = "suppressed" // 被限流抑制
 )
 
 // String 返回字符串表示
@@ -414,7 +477,8 @@ func (n NotificationStatus) String() string {
 // IsValid 验证通知状态是否有效
 func (n NotificationStatus) IsValid() bool {
 	switch n {
-	case NotificationStatusPending, NotificationStatusSent, NotificationStatusFailed, NotificationStatusRetry:
+	case NotificationStatusPending, NotificationStatusSent, NotificationStatusFailed, NotificationStatusRetry,
+		NotificationStatusSuppressed:
 		return true
 	default:
 		return false
@@ -423,7 +487,38 @@ func (n NotificationStatus) IsValid() bool {
 
 // IsFinal 检查是否为最终状态
 func (n NotificationStatus) IsFinal() bool {
-	return n == NotificationStatusSent || n == NotificationStatusFailed
+	return n == NotificationStatusSent || n == NotificationStatusFailed || n == NotificationStatusSuppressed
+}
+
+// DigestFrequency 摘要通知频率
+type DigestFrequency string
+
+const (
+	DigestHourly DigestFrequency = "hourly" // 每小时
+	DigestDaily  DigestFrequency = "daily"  // 每天
+)
+
+// String 返回字符串表示
+func (d DigestFrequency) String() string {
+	return string(d)
+}
+
+// IsValid 验证摘要频率是否有效
+func (d DigestFrequency) IsValid() bool {
+	switch d {
+	case DigestHourly, DigestDaily:
+		return true
+	default:
+		return false
+	}
+}
+
+// Period 返回摘要周期时长
+func (d DigestFrequency) Period() time.Duration {
+	if d == DigestDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
 }
 
 // 常用数值常量
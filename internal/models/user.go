@@ -79,6 +79,9 @@ type UserSession struct {
 	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
 	IsActive  bool      `json:"is_active" gorm:"default:true"`
 	
+	// 当前有效的刷新令牌标识，每次刷新时轮换
+	RefreshTokenID string `json:"-" gorm:"size:64"`
+	
 	// 关联关系
 	User User `json:"user,omitempty"`
 }
@@ -281,6 +284,11 @@ func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+// CanViewAllUsers 检查是否可以查看其他用户的告警规则与订阅（管理员与操作员）
+func (u *User) CanViewAllUsers() bool {
+	return u.Role == RoleAdmin || u.Role == RoleOperator
+}
+
 // ToJSON 序列化为 JSON（排除敏感信息）
 func (u *User) ToJSON() ([]byte, error) {
 	// 创建一个副本，排除密码等敏感信息
@@ -434,3 +442,52 @@ func (r *ChangePasswordRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
 }
+
+// 登录请求结构
+type LoginRequest struct {
+	Login    string `json:"login" validate:"required,max=255"` // 用户名或邮箱
+	Password string `json:"password" validate:"required"`
+}
+
+// Validate 验证登录请求
+func (r *LoginRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// 刷新令牌请求结构
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Validate 验证刷新令牌请求
+func (r *RefreshTokenRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// 用户角色修改请求结构
+type UpdateUserRoleRequest struct {
+	Role UserRole `json:"role" validate:"required"`
+}
+
+// Validate 验证角色修改请求
+func (r *UpdateUserRoleRequest) Validate() error {
+	if !r.Role.IsValid() {
+		return errors.New("invalid role")
+	}
+	return nil
+}
+
+// 用户状态修改请求结构
+type UpdateUserStatusRequest struct {
+	Status UserStatus `json:"status" validate:"required"`
+}
+
+// Validate 验证状态修改请求
+func (r *UpdateUserStatusRequest) Validate() error {
+	if !r.Status.IsValid() {
+		return errors.New("invalid status")
+	}
+	return nil
+}
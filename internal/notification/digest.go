@@ -0,0 +1,216 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// digestKeyPrefix 摘要发送去重的 Redis 键前缀
+const digestKeyPrefix = "alert_digest:"
+
+// digestAlertLimit 摘要中列出的告警数量上限
+const digestAlertLimit = 20
+
+// DigestRuleStore 摘要所需的规则查询
+type DigestRuleStore interface {
+	ListActive(ctx context.Context) ([]*models.AlertRule, error)
+}
+
+// DigestAlertStore 摘要所需的告警查询，*repository.AlertRepository 实现该接口
+type DigestAlertStore interface {
+	ListRecentByRule(ctx context.Context, ruleID uint64, start, end time.Time, limit int) ([]*models.Alert, error)
+	CountByRule(ctx context.Context, ruleID uint64, start, end time.Time) (int64, int64, error)
+}
+
+// DigestLock 摘要发送去重锁，*database.RedisManager 实现该接口
+type DigestLock interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+// Digest 规则在一个摘要周期内的告警汇总
+type Digest struct {
+	// 告警规则
+	Rule *models.AlertRule
+	// 周期起止时间，左闭右开
+	Start, End time.Time
+	// 最近的若干条告警
+	Alerts []*models.Alert
+	// 周期内告警记录数
+	AlertCount int64
+	// 周期内分组聚合后的触发次数
+	Occurrences int64
+}
+
+// DigestScheduler 摘要通知调度器
+// 每个整点（每日摘要为 UTC 零点）汇总上一周期的告警，向配置了摘要频率的渠道发送；
+// 告警从数据库查询，进程重启不会丢失周期内的告警，Redis 锁保证每个周期只发送一次
+type DigestScheduler struct {
+	dispatcher *Dispatcher
+	rules      DigestRuleStore
+	alerts     DigestAlertStore
+	lock       DigestLock
+	// 检查间隔
+	interval time.Duration
+	// 单次汇总发送超时时间
+	timeout time.Duration
+	logger  *logger.Logger
+
+	// 各频率最近处理的周期结束时间
+	processed map[models.DigestFrequency]time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDigestScheduler 创建摘要通知调度器
+func NewDigestScheduler(dispatcher *Dispatcher, rules DigestRuleStore, alerts DigestAlertStore, lock DigestLock, timeout time.Duration, log *logger.Logger) *DigestScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DigestScheduler{
+		dispatcher: dispatcher,
+		rules:      rules,
+		alerts:     alerts,
+		lock:       lock,
+		interval:   time.Minute,
+		timeout:    timeout,
+		logger:     log,
+		processed:  make(map[models.DigestFrequency]time.Time),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start 启动摘要调度
+func (s *DigestScheduler) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop 停止摘要调度
+func (s *DigestScheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run 定期检查是否有结束的摘要周期
+func (s *DigestScheduler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(time.Now().UTC())
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 对每个频率处理最近结束且尚未处理的周期
+func (s *DigestScheduler) tick(now time.Time) {
+	for _, frequency := range []models.DigestFrequency{models.DigestHourly, models.DigestDaily} {
+		end := now.Truncate(frequency.Period())
+		if !end.After(s.processed[frequency]) {
+			continue
+		}
+		if err := s.deliver(frequency, end.Add(-frequency.Period()), end); err != nil {
+			s.logger.WithError(err).WithField("frequency", frequency).Warn("Failed to deliver alert digests")
+			continue
+		}
+		s.processed[frequency] = end
+	}
+}
+
+// deliver 发送所有规则在 [start, end) 周期内指定频率的摘要
+func (s *DigestScheduler) deliver(frequency models.DigestFrequency, start, end time.Time) error {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	rules, err := s.rules.ListActive(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if err := s.deliverRule(rule, frequency, start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliverRule 向规则中指定频率的摘要渠道发送摘要，无告警时不发送
+func (s *DigestScheduler) deliverRule(rule *models.AlertRule, frequency models.DigestFrequency, start, end time.Time) error {
+	channels, err := rule.GetNotificationChannels()
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	var digest *Digest
+	for i, ch := range channels {
+		if !ch.Enabled || ch.Digest != frequency {
+			continue
+		}
+
+		if digest == nil {
+			if digest, err = s.collect(ctx, rule, start, end); err != nil {
+				return err
+			}
+		}
+		if digest.AlertCount == 0 {
+			return nil
+		}
+
+		key := fmt.Sprintf("%s%s:%d:%d:%d", digestKeyPrefix, frequency, end.Unix(), rule.ID, i)
+		acquired, err := s.lock.SetNX(ctx, key, 1, 2*frequency.Period())
+		if err != nil {
+			return fmt.Errorf("failed to acquire digest lock: %w", err)
+		}
+		if !acquired {
+			continue
+		}
+
+		if err := s.dispatcher.SendDigest(ctx, ch, digest); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"rule_id": rule.ID,
+				"channel": ch.Channel,
+			}).Warn("Failed to send alert digest")
+		}
+	}
+	return nil
+}
+
+// collect 汇总规则在周期内的告警
+func (s *DigestScheduler) collect(ctx context.Context, rule *models.AlertRule, start, end time.Time) (*Digest, error) {
+	alertCount, occurrences, err := s.alerts.CountByRule(ctx, rule.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		Rule:        rule,
+		Start:       start,
+		End:         end,
+		AlertCount:  alertCount,
+		Occurrences: occurrences,
+	}
+	if alertCount > 0 {
+		if digest.Alerts, err = s.alerts.ListRecentByRule(ctx, rule.ID, start, end, digestAlertLimit); err != nil {
+			return nil, err
+		}
+	}
+	return digest, nil
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// memoryDigestStore 内存规则与告警存储，告警数量与最近告警固定
+type memoryDigestStore struct {
+	rules       []*models.AlertRule
+	alerts      []*models.Alert
+	alertCount  int64
+	occurrences int64
+}
+
+func (s *memoryDigestStore) ListActive(context.Context) ([]*models.AlertRule, error) {
+	return s.rules, nil
+}
+
+func (s *memoryDigestStore) ListRecentByRule(_ context.Context, _ uint64, _, _ time.Time, limit int) ([]*models.Alert, error) {
+	return s.alerts[:min(limit, len(s.alerts))], nil
+}
+
+func (s *memoryDigestStore) CountByRule(context.Context, uint64, time.Time, time.Time) (int64, int64, error) {
+	return s.alertCount, s.occurrences, nil
+}
+
+// memoryDigestLock 内存去重锁，可在多个调度器之间共享
+type memoryDigestLock struct {
+	keys map[string]bool
+}
+
+func (l *memoryDigestLock) SetNX(_ context.Context, key string, _ interface{}, _ time.Duration) (bool, error) {
+	if l.keys[key] {
+		return false, nil
+	}
+	l.keys[key] = true
+	return true, nil
+}
+
+// digestRule 向 target 发送每小时摘要、实时通知另一渠道的规则
+func digestRule(t *testing.T, target string) *models.AlertRule {
+	t.Helper()
+	rule := &models.AlertRule{BaseModel: models.BaseModel{ID: 1}, Name: "whales", Severity: models.SeverityLow}
+	if err := rule.SetNotificationChannels([]models.NotificationConfig{
+		{Channel: models.ChannelWebhook, Target: target, Enabled: true, Digest: models.DigestHourly},
+		{Channel: models.ChannelSlack, Target: target, Enabled: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+func TestDigestSchedulerSendsOncePerPeriod(t *testing.T) {
+	server := newStubServer(t)
+	store := &memoryDigestStore{
+		rules: []*models.AlertRule{digestRule(t, server.URL)},
+		alerts: []*models.Alert{
+			{Title: "Large transfer (x3)", Severity: models.SeverityHigh},
+			{Title: "Large transfer", Severity: models.SeverityMedium},
+		},
+		alertCount:  3,
+		occurrences: 5,
+	}
+	lock := &memoryDigestLock{keys: map[string]bool{}}
+	newScheduler := func() *DigestScheduler {
+		return NewDigestScheduler(newTestDispatcher(server.Client(), 0), store, store, lock, time.Second, testLogger())
+	}
+
+	now := time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC)
+	scheduler := newScheduler()
+	scheduler.tick(now)
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected one digest on the digest channel only, got %d requests", len(requests))
+	}
+	body := requests[0].body
+	if body["title"] != "whales 每小时摘要" || body["severity"] != string(models.SeverityHigh) {
+		t.Fatalf("unexpected digest payload: %v", body)
+	}
+	message, _ := body["message"].(string)
+	if !strings.Contains(message, "2024-01-01T09:00:00Z 至 2024-01-01T10:00:00Z 期间共 3 条告警，累计触发 5 次") ||
+		!strings.Contains(message, "... 另有 1 条") {
+		t.Fatalf("unexpected digest message: %q", message)
+	}
+
+	// 同一周期内再次检查，或另一进程处理同一周期，都不会重复发送
+	scheduler.tick(now.Add(time.Minute))
+	newScheduler().tick(now.Add(time.Minute))
+	if got := len(server.received()); got != 1 {
+		t.Fatalf("expected the digest to be sent once per period, got %d requests", got)
+	}
+}
+
+func TestDigestSchedulerSkipsEmptyPeriods(t *testing.T) {
+	server := newStubServer(t)
+	store := &memoryDigestStore{rules: []*models.AlertRule{digestRule(t, server.URL)}}
+	scheduler := NewDigestScheduler(newTestDispatcher(server.Client(), 0), store, store,
+		&memoryDigestLock{keys: map[string]bool{}}, time.Second, testLogger())
+
+	scheduler.tick(time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC))
+	if got := len(server.received()); got != 0 {
+		t.Fatalf("expected no digest without alerts, got %d requests", got)
+	}
+}
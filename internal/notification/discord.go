@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// discordMaxContent Discord 单条消息最大长度
+const discordMaxContent = 2000
+
+// DiscordNotifier Discord 发送器，target 为频道 Webhook URL
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+// NewDiscordNotifier 创建 Discord 发送器
+func NewDiscordNotifier(client *http.Client) *DiscordNotifier {
+	return &DiscordNotifier{client: client}
+}
+
+// Channel 返回渠道类型
+func (n *DiscordNotifier) Channel() models.NotificationChannel {
+	return models.ChannelDiscord
+}
+
+// Send 推送消息到 Discord
+func (n *DiscordNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	content := fmt.Sprintf("**[%s] %s**\n%s", msg.Severity, msg.Title, msg.Body)
+	if runes := []rune(content); len(runes) > discordMaxContent {
+		content = string(runes[:discordMaxContent])
+	}
+
+	payload := map[string]interface{}{
+		"content": content,
+	}
+	if username, ok := config["username"].(string); ok {
+		payload["username"] = username
+	}
+
+	if _, err := postJSON(ctx, n.client, target, payload, nil); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiscordNotifierSend(t *testing.T) {
+	server := newStubServer(t)
+
+	config := map[string]interface{}{"username": "monitor"}
+	if err := NewDiscordNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), config); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	body := requests[0].body
+	if got, want := body["content"], "**[high] Large transfer**\n1000 ETH moved"; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if got := body["username"]; got != "monitor" {
+		t.Errorf("username = %v", got)
+	}
+}
+
+func TestDiscordNotifierTruncatesContent(t *testing.T) {
+	server := newStubServer(t)
+
+	msg := testMessage()
+	msg.Body = strings.Repeat("告", discordMaxContent)
+	if err := NewDiscordNotifier(server.Client()).Send(context.Background(), server.URL, msg, nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	content, _ := server.received()[0].body["content"].(string)
+	if got := utf8.RuneCountInString(content); got != discordMaxContent {
+		t.Errorf("content length = %d runes, want %d", got, discordMaxContent)
+	}
+}
+
+func TestDiscordNotifierNon2xx(t *testing.T) {
+	server := newStubServer(t, http.StatusTooManyRequests)
+
+	err := NewDiscordNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+}
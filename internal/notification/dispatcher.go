@@ -0,0 +1,329 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// AlertStore 告警状态存储
+type AlertStore interface {
+	// UpdateNotificationStatus 更新告警的通知状态
+	UpdateNotificationStatus(ctx context.Context, alert *models.Alert) error
+}
+
+// templateKeys 默认模板引用的上下文字段，缺失时以空字符串填充
+var templateKeys = []string{"From", "To", "Contract", "Event", "Address", "Activity", "Amount", "Token", "Component"}
+
+// Dispatcher 告警通知分发器
+// 按规则配置的渠道逐个发送，失败时按告警配置重试，并回写告警状态
+type Dispatcher struct {
+	// 渠道发送器
+	notifiers map[models.NotificationChannel]Notifier
+	// 告警状态存储，为空时不回写
+	store AlertStore
+	// 渠道限流器，为空时不限流
+	throttler *Throttler
+	// 重试次数
+	retryAttempts int
+	// 重试间隔
+	retryInterval time.Duration
+	// 日志记录器
+	logger *logger.Logger
+}
+
+// NewDispatcher 创建通知分发器
+func NewDispatcher(alertCfg config.AlertConfig, store AlertStore, log *logger.Logger, notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{
+		notifiers:     make(map[models.NotificationChannel]Notifier, len(notifiers)),
+		store:         store,
+		retryAttempts: alertCfg.RetryAttempts,
+		retryInterval: alertCfg.RetryInterval,
+		logger:        log,
+	}
+	for _, n := range notifiers {
+		d.Register(n)
+	}
+	return d
+}
+
+// NewDefaultNotifiers 根据应用配置创建全部渠道的发送器
+func NewDefaultNotifiers(cfg *config.Config) []Notifier {
+	// 运维配置的网关可位于内网；用户提供的回调 URL 只允许连接公网地址
+	client := &http.Client{Timeout: cfg.Notification.Timeout}
+	targetClient := NewTargetClient(cfg.Notification.Timeout)
+	return []Notifier{
+		NewTelegramNotifier(cfg.Telegram.BotToken, "", client),
+		NewEmailNotifier(
+			cfg.Notification.SMTPHost, cfg.Notification.SMTPPort,
+			cfg.Notification.SMTPUsername, cfg.Notification.SMTPPassword, cfg.Notification.SMTPFrom,
+		),
+		NewWebhookNotifier(targetClient),
+		NewSMSNotifier(cfg.Notification.SMSGatewayURL, cfg.Notification.SMSAPIKey, client),
+		NewSlackNotifier(targetClient),
+		NewDiscordNotifier(targetClient),
+	}
+}
+
+// SendTimeout 单次通知发送的超时时间，包含全部重试及其等待
+func SendTimeout(cfg *config.Config) time.Duration {
+	return cfg.Notification.Timeout*time.Duration(cfg.Alert.RetryAttempts+1) +
+		cfg.Alert.RetryInterval*time.Duration(cfg.Alert.RetryAttempts)
+}
+
+// Register 注册渠道发送器，同一渠道后注册的覆盖先注册的
+func (d *Dispatcher) Register(n Notifier) {
+	d.notifiers[n.Channel()] = n
+}
+
+// SetThrottler 设置渠道限流器，超出每小时配额的渠道将被跳过
+func (d *Dispatcher) SetThrottler(t *Throttler) {
+	d.throttler = t
+}
+
+// Dispatch 向规则配置的所有启用渠道发送告警，并更新告警状态
+// 配置了摘要频率的渠道由 DigestScheduler 汇总发送；所有实时渠道均被限流时，告警标记为已抑制
+func (d *Dispatcher) Dispatch(ctx context.Context, rule *models.AlertRule, alert *models.Alert) error {
+	channels, err := rule.GetNotificationChannels()
+	if err != nil {
+		return fmt.Errorf("failed to parse notification channels for rule %d: %w", rule.ID, err)
+	}
+
+	msg := &Message{
+		Title:    alert.Title,
+		Body:     d.render(rule, alert),
+		Severity: alert.Severity,
+		Alert:    alert,
+	}
+
+	var errs []error
+	sent, suppressed := 0, 0
+	for _, ch := range channels {
+		if !ch.Enabled || ch.Digest != "" {
+			continue
+		}
+		if d.throttler != nil && !d.throttler.AllowChannel(ctx, alert, ch.Channel) {
+			suppressed++
+			continue
+		}
+		if err := d.sendWithRetry(ctx, ch, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Channel, err))
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 && len(errs) == 0 && suppressed == 0 {
+		// 未配置任何启用的实时渠道，保持待发送状态
+		return nil
+	}
+
+	switch {
+	case sent == 0 && len(errs) == 0:
+		alert.MarkAsSuppressed(SuppressReasonChannelLimit)
+	case len(errs) > 0:
+		alert.MarkAsFailed(errors.Join(errs...).Error())
+	default:
+		alert.MarkAsSent()
+	}
+
+	if d.store != nil {
+		if err := d.store.UpdateNotificationStatus(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"alert_id":   alert.ID,
+		"rule_id":    rule.ID,
+		"sent":       sent,
+		"suppressed": suppressed,
+		"failed":     len(errs),
+	}).Debug("Alert notifications dispatched")
+
+	return errors.Join(errs...)
+}
+
+// DispatchResolved 向原告警的实时渠道发送解决通知，不受限流影响，也不回写告警状态
+func (d *Dispatcher) DispatchResolved(ctx context.Context, rule *models.AlertRule, alert *models.Alert) error {
+	channels, err := rule.GetNotificationChannels()
+	if err != nil {
+		return fmt.Errorf("failed to parse notification channels for rule %d: %w", rule.ID, err)
+	}
+
+	resolution := "自动解决"
+	if alert.ResolvedBy != nil {
+		resolution = fmt.Sprintf("由用户 %d 解决", *alert.ResolvedBy)
+	}
+	resolvedAt := time.Now()
+	if alert.ResolvedAt != nil {
+		resolvedAt = *alert.ResolvedAt
+	}
+
+	msg := &Message{
+		Title:    "[已解决] " + alert.Title,
+		Body:     fmt.Sprintf("告警规则 %s 于 %s 触发的告警已于 %s %s", rule.Name, alert.TriggerTime.Format(time.RFC3339), resolvedAt.Format(time.RFC3339), resolution),
+		Severity: alert.Severity,
+		Alert:    alert,
+	}
+
+	var errs []error
+	for _, ch := range channels {
+		if !ch.Enabled || ch.Digest != "" {
+			continue
+		}
+		if err := d.sendWithRetry(ctx, ch, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DispatchEscalation 向升级步骤的渠道发送升级通知，不受限流与摘要配置影响，也不回写告警状态
+// alert 的严重级别应已更新为升级后的级别，from 为升级前的级别
+func (d *Dispatcher) DispatchEscalation(ctx context.Context, rule *models.AlertRule, alert *models.Alert, step *models.EscalationStep, from models.AlertSeverity) error {
+	msg := &Message{
+		Title: "[升级] " + alert.Title,
+		Body: fmt.Sprintf("告警规则 %s 于 %s 触发的告警超过 %d 分钟未确认，严重级别由 %s 升级为 %s\n%s",
+			rule.Name, alert.TriggerTime.Format(time.RFC3339), step.After, from, alert.Severity, d.render(rule, alert)),
+		Severity: alert.Severity,
+		Alert:    alert,
+	}
+
+	var errs []error
+	for _, ch := range step.Channels {
+		if !ch.Enabled {
+			continue
+		}
+		if err := d.sendWithRetry(ctx, ch, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendDigest 向单个渠道发送告警摘要
+func (d *Dispatcher) SendDigest(ctx context.Context, ch models.NotificationConfig, digest *Digest) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s 至 %s 期间共 %d 条告警，累计触发 %d 次",
+		digest.Start.Format(time.RFC3339), digest.End.Format(time.RFC3339), digest.AlertCount, digest.Occurrences)
+
+	severity := digest.Rule.Severity
+	for _, alert := range digest.Alerts {
+		fmt.Fprintf(&body, "\n- %s %s", alert.TriggerTime.Format(time.RFC3339), alert.Title)
+		if alert.Severity.GetPriority() > severity.GetPriority() {
+			severity = alert.Severity
+		}
+	}
+	if int64(len(digest.Alerts)) < digest.AlertCount {
+		fmt.Fprintf(&body, "\n... 另有 %d 条", digest.AlertCount-int64(len(digest.Alerts)))
+	}
+
+	msg := &Message{
+		Title:    fmt.Sprintf("%s %s摘要", digest.Rule.Name, digestLabel(ch.Digest)),
+		Body:     body.String(),
+		Severity: severity,
+	}
+	return d.sendWithRetry(ctx, ch, msg)
+}
+
+// digestLabel 返回摘要频率的展示名称
+func digestLabel(frequency models.DigestFrequency) string {
+	if frequency == models.DigestDaily {
+		return "每日"
+	}
+	return "每小时"
+}
+
+// sendWithRetry 发送单个渠道，失败后按间隔重试
+func (d *Dispatcher) sendWithRetry(ctx context.Context, ch models.NotificationConfig, msg *Message) error {
+	notifier, ok := d.notifiers[ch.Channel]
+	if !ok {
+		return fmt.Errorf("unsupported notification channel")
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= d.retryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.retryInterval):
+			}
+		}
+
+		if lastErr = notifier.Send(ctx, ch.Target, msg, ch.Config); lastErr == nil {
+			return nil
+		}
+
+		d.logger.WithError(lastErr).WithFields(logrus.Fields{
+			"channel": ch.Channel,
+			"attempt": attempt + 1,
+		}).Warn("Failed to send notification")
+	}
+	return lastErr
+}
+
+// render 渲染通知模板，模板无效时退回告警消息
+func (d *Dispatcher) render(rule *models.AlertRule, alert *models.Alert) string {
+	tmpl, err := template.New("notification").Option("missingkey=zero").Parse(rule.GetTemplate())
+	if err != nil {
+		d.logger.WithError(err).WithField("rule_id", rule.ID).Warn("Invalid notification template")
+		return alert.Message
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData(rule, alert)); err != nil {
+		d.logger.WithError(err).WithField("rule_id", rule.ID).Warn("Failed to render notification template")
+		return alert.Message
+	}
+	return buf.String()
+}
+
+// templateData 构建模板数据，触发上下文字段以驼峰形式暴露（如 value_eth → ValueEth）
+func templateData(rule *models.AlertRule, alert *models.Alert) map[string]interface{} {
+	data := make(map[string]interface{}, len(templateKeys)+8)
+	for _, key := range templateKeys {
+		data[key] = ""
+	}
+
+	if trigger, err := alert.GetTriggerData(); err == nil && trigger != nil {
+		for k, v := range trigger.Context {
+			data[camelCase(k)] = v
+		}
+		data["SourceType"] = trigger.SourceType
+		data["SourceID"] = trigger.SourceID
+	}
+
+	data["Title"] = alert.Title
+	data["Message"] = alert.Message
+	data["Value"] = alert.TriggerValue
+	data["Threshold"] = rule.Threshold
+	data["Operator"] = rule.Operator
+	data["Severity"] = alert.Severity
+	data["Type"] = alert.Type
+	data["TriggerTime"] = alert.TriggerTime.Format(time.RFC3339)
+	return data
+}
+
+// camelCase 将下划线命名转换为驼峰命名
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
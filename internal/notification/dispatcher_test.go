@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// newTestDispatcher 创建使用本地替身客户端的分发器
+func newTestDispatcher(client *http.Client, retryAttempts int) *Dispatcher {
+	return NewDispatcher(
+		config.AlertConfig{RetryAttempts: retryAttempts, RetryInterval: time.Millisecond},
+		nil, testLogger(),
+		NewWebhookNotifier(client), NewSlackNotifier(client), NewDiscordNotifier(client),
+	)
+}
+
+func TestDispatcherRetriesFailedSends(t *testing.T) {
+	for _, channel := range []models.NotificationChannel{models.ChannelWebhook, models.ChannelSlack, models.ChannelDiscord} {
+		t.Run(string(channel), func(t *testing.T) {
+			server := newStubServer(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+
+			d := newTestDispatcher(server.Client(), 2)
+			ch := models.NotificationConfig{Channel: channel, Target: server.URL, Enabled: true}
+			if err := d.sendWithRetry(context.Background(), ch, testMessage()); err != nil {
+				t.Fatalf("expected success on third attempt, got %v", err)
+			}
+			if got := len(server.received()); got != 3 {
+				t.Errorf("expected 3 attempts, got %d", got)
+			}
+		})
+	}
+}
+
+func TestDispatcherGivesUpAfterRetries(t *testing.T) {
+	server := newStubServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+
+	d := newTestDispatcher(server.Client(), 1)
+	ch := models.NotificationConfig{Channel: models.ChannelWebhook, Target: server.URL, Enabled: true}
+	if err := d.sendWithRetry(context.Background(), ch, testMessage()); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if got := len(server.received()); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestDispatchMarksAlertStatus(t *testing.T) {
+	server := newStubServer(t)
+
+	rule := &models.AlertRule{Name: "whales"}
+	if err := rule.SetNotificationChannels([]models.NotificationConfig{
+		{Channel: models.ChannelWebhook, Target: server.URL, Enabled: true},
+		{Channel: models.ChannelSlack, Target: server.URL, Enabled: false},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	alert := &models.Alert{Title: "Large transfer", Message: "1000 ETH moved", Severity: models.SeverityHigh}
+
+	if err := newTestDispatcher(server.Client(), 0).Dispatch(context.Background(), rule, alert); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if got := len(server.received()); got != 1 {
+		t.Errorf("expected only the enabled channel to be sent, got %d requests", got)
+	}
+	if !alert.NotificationSent {
+		t.Error("alert should be marked as sent")
+	}
+}
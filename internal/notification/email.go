@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// EmailNotifier 邮件发送器，target 为收件人地址
+type EmailNotifier struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewEmailNotifier 创建邮件发送器
+func NewEmailNotifier(host string, port int, username, password, from string) *EmailNotifier {
+	return &EmailNotifier{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Channel 返回渠道类型
+func (n *EmailNotifier) Channel() models.NotificationChannel {
+	return models.ChannelEmail
+}
+
+// Send 通过 SMTP 发送邮件
+func (n *EmailNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	if n.host == "" {
+		return errors.New("email: SMTP host not configured")
+	}
+	if _, err := mail.ParseAddress(target); err != nil {
+		return fmt.Errorf("email: invalid recipient %q: %w", target, err)
+	}
+
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	addr := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	body := n.buildMessage(target, msg)
+
+	// smtp.SendMail 不支持 context，放入协程以便响应取消
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, n.from, []string{target}, body)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("email: %w", ctx.Err())
+	}
+}
+
+// buildMessage 构建 RFC 5322 邮件内容
+// 头部值去除 CR/LF 防止注入额外头部，主题按 RFC 2047 编码，正文使用 quoted-printable 编码
+func (n *EmailNotifier) buildMessage(to string, msg *Message) []byte {
+	var sb strings.Builder
+	sb.WriteString("From: " + headerValue(n.from) + "\r\n")
+	sb.WriteString("To: " + headerValue(to) + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", headerValue(fmt.Sprintf("[%s] %s", msg.Severity, msg.Title))) + "\r\n")
+	sb.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	sb.WriteString("\r\n")
+
+	// quoted-printable 将换行输出为 CRLF，并折行过长的行
+	qp := quotedprintable.NewWriter(&sb)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(msg.Body, "\r\n", "\n")))
+	_ = qp.Close()
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
+
+// headerValue 去除头部值中的换行，防止通过规则名等用户输入注入邮件头
+func headerValue(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}), " ")
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestEmailBuildMessageStripsHeaderLineBreaks(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "alerts@example.com\r\nBcc: from@evil.example")
+
+	// 规则名等用户输入中的换行不能注入额外的邮件头
+	msg := testMessage()
+	msg.Title = "Large transfer\r\nBcc: title@evil.example\nX-Injected: 1"
+	parsed, err := mail.ReadMessage(bytes.NewReader(notifier.buildMessage("ops@example.com\nCc: to@evil.example", msg)))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+
+	for _, name := range []string{"Bcc", "Cc", "X-Injected"} {
+		if got := parsed.Header.Get(name); got != "" {
+			t.Errorf("unexpected injected header %s: %q", name, got)
+		}
+	}
+	if got, want := parsed.Header.Get("From"), "alerts@example.com Bcc: from@evil.example"; got != want {
+		t.Errorf("From = %q, want %q", got, want)
+	}
+	if got, want := parsed.Header.Get("To"), "ops@example.com Cc: to@evil.example"; got != want {
+		t.Errorf("To = %q, want %q", got, want)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	if want := "[high] Large transfer Bcc: title@evil.example X-Injected: 1"; subject != want {
+		t.Errorf("Subject = %q, want %q", subject, want)
+	}
+}
+
+func TestEmailBuildMessageEncodesUTF8(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "alerts@example.com")
+
+	msg := testMessage()
+	msg.Title = "大额转账"
+	msg.Body = "地址 0xabc 转出 1000 ETH\r\n" + strings.Repeat("超长的一行", 40)
+	raw := notifier.buildMessage("ops@example.com", msg)
+
+	// 原始内容只包含 ASCII，中文经 RFC 2047 与 quoted-printable 编码
+	for i, b := range raw {
+		if b >= 0x80 {
+			t.Fatalf("unexpected non-ASCII byte %#x at offset %d", b, i)
+		}
+	}
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 998 {
+			t.Fatalf("line exceeds the RFC 5322 limit: %d bytes", len(line))
+		}
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	if got := parsed.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := parsed.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	if subject != "[high] 大额转账" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if got, want := strings.TrimRight(string(body), "\r\n"), msg.Body; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestEmailNotifierRejectsInvalidRecipient(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "alerts@example.com")
+
+	err := notifier.Send(context.Background(), "ops@example.com\r\nBcc: evil@example.com", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+		t.Fatalf("expected an invalid recipient error, got %v", err)
+	}
+}
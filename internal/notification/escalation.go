@@ -0,0 +1,189 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// EscalationAlertStore 升级所需的告警查询与更新，*repository.AlertRepository 实现该接口
+type EscalationAlertStore interface {
+	ListPendingEscalation(ctx context.Context, ruleID uint64, policy *models.EscalationPolicy, at time.Time) ([]*models.Alert, error)
+	Escalate(ctx context.Context, id uint64, fromLevel int32, severity models.AlertSeverity) (bool, error)
+}
+
+// EscalationRecorder 升级记录存储，*repository.AlertEscalationRepository 实现该接口
+type EscalationRecorder interface {
+	Create(ctx context.Context, escalation *models.AlertEscalation) error
+}
+
+// Escalator 告警升级调度器
+// 定期检查配置了升级策略的规则，告警在步骤时长内仍未确认时升高严重级别、通知该步骤的渠道并写入升级记录；
+// 升级步骤通过条件更新认领，多个进程同时运行时每个步骤只执行一次
+type Escalator struct {
+	dispatcher *Dispatcher
+	rules      DigestRuleStore
+	alerts     EscalationAlertStore
+	records    EscalationRecorder
+	// 检查间隔
+	interval time.Duration
+	// 单次升级发送超时时间
+	timeout time.Duration
+	logger  *logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEscalator 创建告警升级调度器
+func NewEscalator(dispatcher *Dispatcher, rules DigestRuleStore, alerts EscalationAlertStore, records EscalationRecorder, timeout time.Duration, log *logger.Logger) *Escalator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Escalator{
+		dispatcher: dispatcher,
+		rules:      rules,
+		alerts:     alerts,
+		records:    records,
+		interval:   30 * time.Second,
+		timeout:    timeout,
+		logger:     log,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start 启动升级调度
+func (e *Escalator) Start() {
+	e.wg.Add(1)
+	go e.run()
+}
+
+// Stop 停止升级调度，等待进行中的升级通知完成
+func (e *Escalator) Stop() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// run 定期检查待升级的告警
+func (e *Escalator) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.tick(time.Now()); err != nil {
+			e.logger.WithError(err).Warn("Failed to escalate alerts")
+		}
+
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 处理所有配置了升级策略的规则
+func (e *Escalator) tick(now time.Time) error {
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	rules, err := e.rules.ListActive(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		policy, err := rule.GetEscalationPolicy()
+		if err != nil || policy == nil {
+			continue
+		}
+		if err := e.escalateRule(rule, policy, now); err != nil {
+			e.logger.WithError(err).WithField("rule_id", rule.ID).Warn("Failed to escalate rule alerts")
+		}
+	}
+	return nil
+}
+
+// escalateRule 对规则下到期的告警执行下一个升级步骤，每次检查每条告警最多升级一步
+func (e *Escalator) escalateRule(rule *models.AlertRule, policy *models.EscalationPolicy, now time.Time) error {
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	alerts, err := e.alerts.ListPendingEscalation(ctx, rule.ID, policy, now)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		step, due := policy.NextStep(alert)
+		if step == nil || now.Before(due) {
+			continue
+		}
+		if err := e.escalate(rule, policy, alert, step); err != nil {
+			e.logger.WithError(err).WithField("alert_id", alert.ID).Warn("Failed to escalate alert")
+		}
+	}
+	return nil
+}
+
+// escalate 认领并执行告警的下一个升级步骤
+func (e *Escalator) escalate(rule *models.AlertRule, policy *models.EscalationPolicy, alert *models.Alert, step *models.EscalationStep) error {
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	defer cancel()
+
+	from := alert.Severity
+	to := from.Escalate(policy.GetMaxSeverity())
+	claimed, err := e.alerts.Escalate(ctx, alert.ID, alert.EscalationLevel, to)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// 已被确认、解决或由其他进程升级
+		return nil
+	}
+	alert.EscalationLevel++
+	alert.Severity = to
+
+	record := &models.AlertEscalation{
+		AlertID:      alert.ID,
+		RuleID:       rule.ID,
+		Level:        alert.EscalationLevel,
+		FromSeverity: from,
+		ToSeverity:   to,
+		Notified:     true,
+	}
+	// 仅记录渠道类型，避免在审计记录中保存渠道凭据
+	channels := make([]models.NotificationChannel, 0, len(step.Channels))
+	for _, ch := range step.Channels {
+		if ch.Enabled {
+			channels = append(channels, ch.Channel)
+		}
+	}
+	if data, err := json.Marshal(channels); err == nil {
+		record.Channels = string(data)
+	}
+	if err := e.dispatcher.DispatchEscalation(ctx, rule, alert, step, from); err != nil {
+		record.Notified = false
+		record.ErrorMessage = err.Error()
+	}
+
+	if err := e.records.Create(ctx, record); err != nil {
+		return err
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"alert_id": alert.ID,
+		"rule_id":  rule.ID,
+		"level":    record.Level,
+		"severity": to,
+		"notified": record.Notified,
+	}).Info("Alert escalated")
+	return nil
+}
@@ -0,0 +1,243 @@
+package notification
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// maxGroupKeyLength 分组键最大长度，与 alerts.group_key 列宽一致
+const maxGroupKeyLength = 255
+
+// maxGroupSources 分组摘要中列出的来源数量上限
+const maxGroupSources = 5
+
+// Grouper 告警分组器
+// 启用分组的规则在分组窗口内按分组键聚合告警，窗口结束时输出一条带数量与摘要的告警；
+// 未启用分组的告警直接输出。输出通道满时发送方阻塞等待消费方，不丢弃告警
+type Grouper struct {
+	// 进行中的分组
+	groups map[string]*alertGroup
+	// 已停止，不再接收新告警
+	stopped bool
+	mu      sync.Mutex
+	// 进行中的发送，Stop 等待其完成后关闭输出通道
+	sending sync.WaitGroup
+
+	out     chan *models.Alert
+	metrics *metrics.Metrics
+	logger  *logger.Logger
+}
+
+// alertGroup 分组窗口内聚合的告警
+type alertGroup struct {
+	// 窗口内第一条告警，作为输出告警的基础
+	alert *models.Alert
+	// 聚合的告警数量
+	count int32
+	// 触发值统计
+	minValue, maxValue, sumValue float64
+	// 最近一次触发时间
+	lastTime time.Time
+	// 前若干条告警的来源
+	sources []string
+	// 窗口结束计时器
+	timer *time.Timer
+}
+
+// NewGrouper 创建告警分组器
+func NewGrouper(bufferSize int, m *metrics.Metrics, log *logger.Logger) *Grouper {
+	return &Grouper{
+		groups:  make(map[string]*alertGroup),
+		out:     make(chan *models.Alert, bufferSize),
+		metrics: m,
+		logger:  log,
+	}
+}
+
+// Alerts 返回分组后的告警
+func (g *Grouper) Alerts() <-chan *models.Alert {
+	return g.out
+}
+
+// Add 加入一条告警，分组键相同的告警在窗口结束时合并输出
+func (g *Grouper) Add(alert *models.Alert) {
+	rule := &alert.Rule
+	if !rule.IsGrouped() {
+		g.emit(alert)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		g.drop(alert)
+		return
+	}
+
+	key := GroupKey(rule, alert)
+	alert.GroupKey = key
+
+	if group, ok := g.groups[key]; ok {
+		group.add(alert)
+		return
+	}
+
+	group := &alertGroup{
+		alert:    alert,
+		count:    1,
+		minValue: alert.TriggerValue,
+		maxValue: alert.TriggerValue,
+		sumValue: alert.TriggerValue,
+		lastTime: alert.TriggerTime,
+		sources:  []string{alertSource(alert)},
+	}
+	group.timer = time.AfterFunc(time.Duration(rule.GroupWindow)*time.Second, func() {
+		g.flush(key)
+	})
+	g.groups[key] = group
+}
+
+// Stop 立即输出所有进行中的分组，等待进行中的发送完成后关闭输出通道
+// 消费方需持续读取 Alerts 直到通道关闭；之后加入的告警被丢弃并计入抑制指标
+func (g *Grouper) Stop() {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+	g.stopped = true
+	groups := g.groups
+	g.groups = make(map[string]*alertGroup)
+	for _, group := range groups {
+		group.timer.Stop()
+	}
+	g.mu.Unlock()
+
+	for _, group := range groups {
+		g.out <- group.result()
+	}
+	g.sending.Wait()
+	close(g.out)
+}
+
+// flush 结束分组窗口并输出聚合后的告警
+func (g *Grouper) flush(key string) {
+	g.mu.Lock()
+	group, ok := g.groups[key]
+	if !ok {
+		g.mu.Unlock()
+		return
+	}
+	delete(g.groups, key)
+	g.sending.Add(1)
+	g.mu.Unlock()
+
+	g.send(group.result())
+}
+
+// emit 输出未分组的告警，已停止时丢弃
+func (g *Grouper) emit(alert *models.Alert) {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		g.drop(alert)
+		return
+	}
+	g.sending.Add(1)
+	g.mu.Unlock()
+
+	g.send(alert)
+}
+
+// send 在不持有 g.mu 的情况下发送告警，输出通道满时阻塞直到消费方读取
+// 调用方需在持有 g.mu 且未停止时调用 g.sending.Add(1)
+func (g *Grouper) send(alert *models.Alert) {
+	defer g.sending.Done()
+	g.out <- alert
+}
+
+// drop 丢弃停止后加入的告警，计入抑制指标
+func (g *Grouper) drop(alert *models.Alert) {
+	g.metrics.AlertsSuppressedTotal.WithLabelValues(string(alert.Type), SuppressReasonGrouperStopped).Inc()
+	g.logger.WithFields(logrus.Fields{
+		"rule_id":     alert.RuleID,
+		"group_count": alert.GroupCount,
+	}).Warn("Grouper stopped, dropping alert")
+}
+
+// add 将告警合并到分组
+func (ag *alertGroup) add(alert *models.Alert) {
+	ag.count++
+	ag.minValue = min(ag.minValue, alert.TriggerValue)
+	ag.maxValue = max(ag.maxValue, alert.TriggerValue)
+	ag.sumValue += alert.TriggerValue
+	if alert.TriggerTime.After(ag.lastTime) {
+		ag.lastTime = alert.TriggerTime
+	}
+	if len(ag.sources) < maxGroupSources {
+		ag.sources = append(ag.sources, alertSource(alert))
+	}
+}
+
+// result 生成分组输出的告警，多条告警时以摘要替换消息
+func (ag *alertGroup) result() *models.Alert {
+	alert := ag.alert
+	alert.GroupCount = ag.count
+	if ag.count <= 1 {
+		return alert
+	}
+
+	sources := strings.Join(ag.sources, ", ")
+	if int(ag.count) > len(ag.sources) {
+		sources += fmt.Sprintf(" 等 %d 个", ag.count)
+	}
+	alert.Title = fmt.Sprintf("%s (x%d)", alert.Title, ag.count)
+	alert.Message = fmt.Sprintf("告警规则 %s 在 %s 至 %s 期间触发 %d 次，触发值最小 %g、最大 %g、合计 %g，来源: %s",
+		alert.Rule.Name, alert.TriggerTime.Format(time.RFC3339), ag.lastTime.Format(time.RFC3339),
+		ag.count, ag.minValue, ag.maxValue, ag.sumValue, sources)
+	alert.TriggerValue = ag.maxValue
+	return alert
+}
+
+// GroupKey 计算告警的分组键：规则 ID 加上各分组字段在触发上下文中的取值
+// 超出列宽时使用哈希值
+func GroupKey(rule *models.AlertRule, alert *models.Alert) string {
+	parts := []string{fmt.Sprintf("%d", rule.ID)}
+
+	fields, _ := rule.GetGroupBy()
+	if len(fields) > 0 {
+		var context map[string]interface{}
+		if trigger, err := alert.GetTriggerData(); err == nil && trigger != nil {
+			context = trigger.Context
+		}
+		for _, field := range fields {
+			parts = append(parts, fmt.Sprintf("%s=%v", field, context[field]))
+		}
+	}
+
+	key := strings.Join(parts, "|")
+	if len(key) > maxGroupKeyLength {
+		sum := sha256.Sum256([]byte(key))
+		key = fmt.Sprintf("%d|%s", rule.ID, hex.EncodeToString(sum[:]))
+	}
+	return key
+}
+
+// alertSource 返回告警的来源描述
+func alertSource(alert *models.Alert) string {
+	if trigger, err := alert.GetTriggerData(); err == nil && trigger != nil && trigger.SourceID != "" {
+		return trigger.SourceID
+	}
+	return alert.TriggerTime.Format(time.RFC3339)
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// groupedAlert 按地址分组的规则产生的告警
+func groupedAlert(address string, value float64, window int32) *models.Alert {
+	alert := &models.Alert{
+		RuleID:       1,
+		Type:         models.AlertTypeLargeTransfer,
+		Title:        "Large transfer",
+		TriggerValue: value,
+		TriggerTime:  time.Now(),
+		Rule:         models.AlertRule{BaseModel: models.BaseModel{ID: 1}, Name: "whales", GroupWindow: window},
+	}
+	alert.Rule.SetGroupBy([]string{"address"})
+	alert.SetTriggerData(&models.AlertTriggerData{Context: map[string]interface{}{"address": address}})
+	return alert
+}
+
+// receiveAlert 在超时前读取一条分组后的告警
+func receiveAlert(t *testing.T, g *Grouper) *models.Alert {
+	t.Helper()
+	select {
+	case alert := <-g.Alerts():
+		return alert
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for a grouped alert")
+		return nil
+	}
+}
+
+// suppressedCount 返回指定原因的抑制计数
+func suppressedCount(t *testing.T, m *metrics.Metrics, alertType models.AlertType, reason string) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := m.AlertsSuppressedTotal.WithLabelValues(string(alertType), reason).Write(&metric); err != nil {
+		t.Fatalf("failed to read suppression counter: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestGrouperAggregatesByGroupKey(t *testing.T) {
+	g := NewGrouper(10, metrics.NewMetrics("test"), testLogger())
+
+	g.Add(groupedAlert("0xaaa", 10, 1))
+	g.Add(groupedAlert("0xaaa", 30, 1))
+	g.Add(groupedAlert("0xbbb", 20, 1))
+
+	// 窗口结束后每个分组输出一条告警
+	results := map[string]*models.Alert{}
+	for range 2 {
+		alert := receiveAlert(t, g)
+		results[alert.GroupKey] = alert
+	}
+
+	aaa := results["1|address=0xaaa"]
+	if aaa == nil || aaa.GroupCount != 2 || aaa.TriggerValue != 30 || aaa.Title != "Large transfer (x2)" {
+		t.Fatalf("unexpected aggregate for 0xaaa: %+v", aaa)
+	}
+	bbb := results["1|address=0xbbb"]
+	if bbb == nil || bbb.GroupCount != 1 || bbb.Title != "Large transfer" {
+		t.Fatalf("unexpected aggregate for 0xbbb: %+v", bbb)
+	}
+	g.Stop()
+}
+
+func TestGrouperStopFlushesOpenGroups(t *testing.T) {
+	g := NewGrouper(10, metrics.NewMetrics("test"), testLogger())
+
+	g.Add(groupedAlert("0xaaa", 10, 3600))
+	g.Add(groupedAlert("0xaaa", 30, 3600))
+	g.Stop()
+
+	alert := receiveAlert(t, g)
+	if alert.GroupCount != 2 {
+		t.Fatalf("expected the open group to be flushed with 2 alerts, got %d", alert.GroupCount)
+	}
+	if _, ok := <-g.Alerts(); ok {
+		t.Fatal("expected the output channel to be closed after Stop")
+	}
+}
+
+func TestGrouperBlocksWhenOutputIsFull(t *testing.T) {
+	g := NewGrouper(1, metrics.NewMetrics("test"), testLogger())
+
+	// 输出缓冲区容量为 1，后续告警等待消费方读取而不是被丢弃
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		for range 3 {
+			g.Add(&models.Alert{RuleID: 2, Type: models.AlertTypeGasPrice})
+		}
+	}()
+
+	select {
+	case <-added:
+		t.Fatal("expected Add to block while the output channel is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for range 3 {
+		receiveAlert(t, g)
+	}
+	<-added
+	g.Stop()
+}
+
+func TestGrouperCountsAlertsAddedAfterStop(t *testing.T) {
+	m := metrics.NewMetrics("test")
+	g := NewGrouper(10, m, testLogger())
+	g.Stop()
+
+	g.Add(&models.Alert{RuleID: 2, Type: models.AlertTypeGasPrice})
+	g.Add(groupedAlert("0xaaa", 10, 60))
+
+	if got := suppressedCount(t, m, models.AlertTypeGasPrice, SuppressReasonGrouperStopped); got != 1 {
+		t.Fatalf("expected 1 dropped gas price alert, got %v", got)
+	}
+	if got := suppressedCount(t, m, models.AlertTypeLargeTransfer, SuppressReasonGrouperStopped); got != 1 {
+		t.Fatalf("expected 1 dropped large transfer alert, got %v", got)
+	}
+}
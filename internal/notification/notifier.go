@@ -0,0 +1,101 @@
+// Package notification 实现告警通知的各渠道发送器与分发器
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// Message 渲染后的通知消息
+type Message struct {
+	// 标题
+	Title string
+	// 正文
+	Body string
+	// 严重级别
+	Severity models.AlertSeverity
+	// 原始告警
+	Alert *models.Alert
+}
+
+// Notifier 通知渠道发送器
+type Notifier interface {
+	// Channel 返回发送器对应的渠道
+	Channel() models.NotificationChannel
+	// Send 向目标发送消息，config 为规则中该渠道的额外配置
+	Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error
+}
+
+// Text 返回带严重级别前缀的纯文本消息
+func (m *Message) Text() string {
+	return fmt.Sprintf("[%s] %s\n%s", m.Severity, m.Title, m.Body)
+}
+
+// ErrPrivateTarget 回调地址解析到本机或内网地址
+var ErrPrivateTarget = errors.New("target resolves to a non-public address")
+
+// NewTargetClient 创建向用户提供的回调 URL 发送请求的 HTTP 客户端
+// 建立连接时检查解析后的地址，拒绝本机与内网地址，域名解析与重定向均无法绕过；
+// 直接连接目标，不使用环境变量中的代理
+func NewTargetClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicAddressOnly,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// publicAddressOnly 拒绝连接非公网地址
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !models.IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+	}
+	return nil
+}
+
+// postJSON 发送 JSON 请求，非 2xx 响应视为失败
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return respBody, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// recordedRequest 本地替身服务收到的请求
+type recordedRequest struct {
+	header http.Header
+	body   map[string]interface{}
+}
+
+// stubServer 记录请求并按顺序返回预设状态码的 httptest 服务，状态码用完后返回 200
+type stubServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+	statuses []int
+}
+
+// newStubServer 创建本地替身服务
+func newStubServer(t *testing.T, statuses ...int) *stubServer {
+	t.Helper()
+
+	s := &stubServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("request body is not JSON: %v", err)
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, recordedRequest{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(http.StatusText(status)))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received 返回已收到的请求
+func (s *stubServer) received() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest(nil), s.requests...)
+}
+
+// testMessage 测试用通知消息
+func testMessage() *Message {
+	return &Message{
+		Title:    "Large transfer",
+		Body:     "1000 ETH moved",
+		Severity: models.SeverityHigh,
+		Alert: &models.Alert{
+			RuleID:       7,
+			Type:         models.AlertTypeLargeTransfer,
+			TriggerValue: 1000,
+		},
+	}
+}
+
+// testLogger 丢弃输出的日志记录器
+func testLogger() *logger.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &logger.Logger{Logger: log}
+}
+
+func TestTargetClientRejectsPrivateAddresses(t *testing.T) {
+	server := newStubServer(t)
+
+	err := NewWebhookNotifier(NewTargetClient(0)).Send(context.Background(), server.URL, testMessage(), nil)
+	if !errors.Is(err, ErrPrivateTarget) {
+		t.Fatalf("expected ErrPrivateTarget, got %v", err)
+	}
+	if got := len(server.received()); got != 0 {
+		t.Fatalf("expected no request to reach the server, got %d", got)
+	}
+}
@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// SlackNotifier Slack 发送器，target 为 Incoming Webhook URL
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier 创建 Slack 发送器
+func NewSlackNotifier(client *http.Client) *SlackNotifier {
+	return &SlackNotifier{client: client}
+}
+
+// Channel 返回渠道类型
+func (n *SlackNotifier) Channel() models.NotificationChannel {
+	return models.ChannelSlack
+}
+
+// Send 推送消息到 Slack
+func (n *SlackNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"text": fmt.Sprintf("*[%s] %s*\n%s", msg.Severity, msg.Title, msg.Body),
+	}
+	if channel, ok := config["channel"].(string); ok {
+		payload["channel"] = channel
+	}
+
+	if _, err := postJSON(ctx, n.client, target, payload, nil); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSlackNotifierSend(t *testing.T) {
+	server := newStubServer(t)
+
+	config := map[string]interface{}{"channel": "#alerts"}
+	if err := NewSlackNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), config); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	body := requests[0].body
+	if got, want := body["text"], "*[high] Large transfer*\n1000 ETH moved"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got := body["channel"]; got != "#alerts" {
+		t.Errorf("channel = %v", got)
+	}
+	if got := requests[0].header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestSlackNotifierNon2xx(t *testing.T) {
+	server := newStubServer(t, http.StatusForbidden)
+
+	err := NewSlackNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected 403 error, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// SMSNotifier 短信发送器，通过 HTTP 短信网关发送，target 为手机号
+type SMSNotifier struct {
+	gatewayURL string
+	apiKey     string
+	client     *http.Client
+}
+
+// NewSMSNotifier 创建短信发送器
+func NewSMSNotifier(gatewayURL, apiKey string, client *http.Client) *SMSNotifier {
+	return &SMSNotifier{
+		gatewayURL: gatewayURL,
+		apiKey:     apiKey,
+		client:     client,
+	}
+}
+
+// Channel 返回渠道类型
+func (n *SMSNotifier) Channel() models.NotificationChannel {
+	return models.ChannelSMS
+}
+
+// Send 调用短信网关发送消息
+func (n *SMSNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	if n.gatewayURL == "" {
+		return errors.New("sms: gateway URL not configured")
+	}
+
+	payload := map[string]interface{}{
+		"to":      target,
+		"message": fmt.Sprintf("[%s] %s: %s", msg.Severity, msg.Title, msg.Body),
+	}
+
+	headers := map[string]string{}
+	if n.apiKey != "" {
+		headers["Authorization"] = "Bearer " + n.apiKey
+	}
+
+	if _, err := postJSON(ctx, n.client, n.gatewayURL, payload, headers); err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	return nil
+}
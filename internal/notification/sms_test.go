@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSMSNotifierSend(t *testing.T) {
+	server := newStubServer(t)
+
+	if err := NewSMSNotifier(server.URL, "secret", server.Client()).Send(context.Background(), "+8613800000000", testMessage(), nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	body := requests[0].body
+	if got := body["to"]; got != "+8613800000000" {
+		t.Errorf("to = %v", got)
+	}
+	if got, want := body["message"], "[high] Large transfer: 1000 ETH moved"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+	if got := requests[0].header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestSMSNotifierWithoutAPIKey(t *testing.T) {
+	server := newStubServer(t)
+
+	if err := NewSMSNotifier(server.URL, "", server.Client()).Send(context.Background(), "+8613800000000", testMessage(), nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := server.received()[0].header.Get("Authorization"); got != "" {
+		t.Errorf("expected no Authorization header, got %q", got)
+	}
+}
+
+func TestSMSNotifierNon2xx(t *testing.T) {
+	server := newStubServer(t, http.StatusBadGateway)
+
+	err := NewSMSNotifier(server.URL, "secret", server.Client()).Send(context.Background(), "+8613800000000", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("expected 502 error, got %v", err)
+	}
+}
+
+func TestSMSNotifierRequiresGateway(t *testing.T) {
+	err := NewSMSNotifier("", "secret", http.DefaultClient).Send(context.Background(), "+8613800000000", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "gateway URL not configured") {
+		t.Fatalf("expected a configuration error, got %v", err)
+	}
+}
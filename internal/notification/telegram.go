@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// defaultTelegramAPIURL Telegram Bot API 地址
+const defaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramNotifier Telegram 发送器，target 为 chat_id
+type TelegramNotifier struct {
+	botToken string
+	apiURL   string
+	client   *http.Client
+}
+
+// NewTelegramNotifier 创建 Telegram 发送器，apiURL 为空时使用官方地址
+func NewTelegramNotifier(botToken, apiURL string, client *http.Client) *TelegramNotifier {
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+	return &TelegramNotifier{
+		botToken: botToken,
+		apiURL:   strings.TrimRight(apiURL, "/"),
+		client:   client,
+	}
+}
+
+// Channel 返回渠道类型
+func (n *TelegramNotifier) Channel() models.NotificationChannel {
+	return models.ChannelTelegram
+}
+
+// Send 调用 sendMessage 发送消息
+func (n *TelegramNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"chat_id": target,
+		"text":    msg.Text(),
+	}
+	if parseMode, ok := config["parse_mode"].(string); ok {
+		payload["parse_mode"] = parseMode
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", n.apiURL, n.botToken)
+	body, err := postJSON(ctx, n.client, url, payload, nil)
+	if err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("telegram: invalid response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram: %s", result.Description)
+	}
+	return nil
+}
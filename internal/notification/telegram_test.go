@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTelegramServer 模拟 Bot API 的 httptest 服务，记录请求路径与请求体并返回 response
+func newTelegramServer(t *testing.T, response string) (*httptest.Server, *[]string, *[]map[string]interface{}) {
+	t.Helper()
+
+	var paths []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("request body is not JSON: %v", err)
+		}
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &paths, &bodies
+}
+
+func TestTelegramNotifierSend(t *testing.T) {
+	server, paths, bodies := newTelegramServer(t, `{"ok":true,"result":{}}`)
+
+	// 末尾的斜杠被去除
+	notifier := NewTelegramNotifier("123:token", server.URL+"/", server.Client())
+	config := map[string]interface{}{"parse_mode": "Markdown"}
+	if err := notifier.Send(context.Background(), "-1001", testMessage(), config); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if len(*paths) != 1 || (*paths)[0] != "/bot123:token/sendMessage" {
+		t.Fatalf("unexpected request paths: %v", *paths)
+	}
+	body := (*bodies)[0]
+	if got := body["chat_id"]; got != "-1001" {
+		t.Errorf("chat_id = %v", got)
+	}
+	if got, want := body["text"], "[high] Large transfer\n1000 ETH moved"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got := body["parse_mode"]; got != "Markdown" {
+		t.Errorf("parse_mode = %v", got)
+	}
+}
+
+func TestTelegramNotifierOmitsParseModeByDefault(t *testing.T) {
+	server, _, bodies := newTelegramServer(t, `{"ok":true}`)
+
+	if err := NewTelegramNotifier("token", server.URL, server.Client()).Send(context.Background(), "42", testMessage(), nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, ok := (*bodies)[0]["parse_mode"]; ok {
+		t.Errorf("expected no parse_mode, got %v", (*bodies)[0])
+	}
+}
+
+func TestTelegramNotifierAPIError(t *testing.T) {
+	// Bot API 以 ok=false 表示失败，HTTP 状态码可能仍为 200
+	server, _, _ := newTelegramServer(t, `{"ok":false,"description":"Bad Request: chat not found"}`)
+
+	err := NewTelegramNotifier("token", server.URL, server.Client()).Send(context.Background(), "42", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Fatalf("expected the API description in the error, got %v", err)
+	}
+}
+
+func TestTelegramNotifierInvalidResponse(t *testing.T) {
+	server, _, _ := newTelegramServer(t, `not json`)
+
+	err := NewTelegramNotifier("token", server.URL, server.Client()).Send(context.Background(), "42", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid response") {
+		t.Fatalf("expected an invalid response error, got %v", err)
+	}
+}
+
+func TestTelegramNotifierNon2xx(t *testing.T) {
+	server := newStubServer(t, http.StatusUnauthorized)
+
+	err := NewTelegramNotifier("token", server.URL, server.Client()).Send(context.Background(), "42", testMessage(), nil)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+}
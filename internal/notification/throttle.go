@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// throttleKeyPrefix 告警限流的 Redis 键前缀
+const throttleKeyPrefix = "alert_throttle:"
+
+// 告警抑制原因
+const (
+	// SuppressReasonCooldown 同一规则与分组的告警处于冷却期
+	SuppressReasonCooldown = "cooldown"
+	// SuppressReasonMaxPerHour 用户每小时告警数达到上限
+	SuppressReasonMaxPerHour = "max_per_hour"
+	// SuppressReasonChannelLimit 用户在渠道上每小时通知数达到上限
+	SuppressReasonChannelLimit = "channel_limit"
+	// SuppressReasonSnoozed 同一规则与分组的告警处于静默期
+	SuppressReasonSnoozed = "snoozed"
+	// SuppressReasonGrouperStopped 告警在分组器停止后到达，未被记录
+	SuppressReasonGrouperStopped = "grouper_stopped"
+)
+
+// ThrottleStore 限流计数存储，*database.RedisManager 实现该接口
+type ThrottleStore interface {
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (*database.RateLimitResult, error)
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+// Throttler 全局告警限流器
+// 按规则与分组键执行 AlertConfig.Cooldown，按用户执行 MaxPerHour，并限制每个渠道每小时的通知数
+// 计数保存在 Redis 中，多个采集进程共享；Redis 不可用时放行
+type Throttler struct {
+	store ThrottleStore
+	// 每个用户每小时最大告警数，同时作为每个渠道每小时最大通知数
+	maxPerHour int
+	// 同一规则同一分组两次告警的最小间隔，不同规则或分组互不影响
+	cooldown time.Duration
+	metrics  *metrics.Metrics
+	logger   *logger.Logger
+}
+
+// NewThrottler 创建告警限流器
+func NewThrottler(store ThrottleStore, alertCfg config.AlertConfig, m *metrics.Metrics, log *logger.Logger) *Throttler {
+	return &Throttler{
+		store:      store,
+		maxPerHour: alertCfg.MaxPerHour,
+		cooldown:   alertCfg.Cooldown,
+		metrics:    m,
+		logger:     log,
+	}
+}
+
+// Check 判断告警是否应被抑制，返回抑制原因，放行时返回空字符串
+// 先占用用户每小时配额，再占用规则分组的冷却期，被每小时上限抑制的告警不占用冷却期
+func (t *Throttler) Check(ctx context.Context, alert *models.Alert) string {
+	if t.maxPerHour > 0 {
+		key := fmt.Sprintf("%suser:%d", throttleKeyPrefix, alert.Rule.UserID)
+		result, err := t.store.AllowSlidingWindow(ctx, key, t.maxPerHour, time.Hour)
+		if err != nil {
+			t.logger.WithError(err).WithField("rule_id", alert.RuleID).Warn("Alert hourly limit check failed, allowing alert")
+		} else if !result.Allowed {
+			return t.suppress(alert, SuppressReasonMaxPerHour)
+		}
+	}
+
+	if t.cooldown > 0 {
+		key := fmt.Sprintf("%scooldown:group:%s", throttleKeyPrefix, cooldownGroup(alert))
+		ok, err := t.store.SetNX(ctx, key, alert.RuleID, t.cooldown)
+		if err != nil {
+			t.logger.WithError(err).WithField("rule_id", alert.RuleID).Warn("Alert cooldown check failed, allowing alert")
+		} else if !ok {
+			return t.suppress(alert, SuppressReasonCooldown)
+		}
+	}
+
+	return ""
+}
+
+// cooldownGroup 返回冷却期的作用范围：分组告警为分组键，未分组告警为规则 ID
+func cooldownGroup(alert *models.Alert) string {
+	if alert.GroupKey != "" {
+		return alert.GroupKey
+	}
+	return strconv.FormatUint(alert.RuleID, 10)
+}
+
+// AllowChannel 判断是否允许向用户的指定渠道发送通知
+func (t *Throttler) AllowChannel(ctx context.Context, alert *models.Alert, channel models.NotificationChannel) bool {
+	if t.maxPerHour <= 0 {
+		return true
+	}
+
+	key := fmt.Sprintf("%schannel:%d:%s", throttleKeyPrefix, alert.Rule.UserID, channel)
+	result, err := t.store.AllowSlidingWindow(ctx, key, t.maxPerHour, time.Hour)
+	if err != nil {
+		t.logger.WithError(err).WithField("channel", channel).Warn("Channel limit check failed, allowing notification")
+		return true
+	}
+	if !result.Allowed {
+		t.suppress(alert, SuppressReasonChannelLimit)
+		return false
+	}
+	return true
+}
+
+// suppress 记录抑制指标与日志
+func (t *Throttler) suppress(alert *models.Alert, reason string) string {
+	t.metrics.AlertsSuppressedTotal.WithLabelValues(string(alert.Type), reason).Inc()
+	t.logger.WithFields(logrus.Fields{
+		"rule_id": alert.RuleID,
+		"user_id": alert.Rule.UserID,
+		"type":    alert.Type,
+		"reason":  reason,
+	}).Info("Alert suppressed by throttle")
+	return reason
+}
@@ -0,0 +1,117 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// memoryThrottleStore 内存限流存储，滑动窗口只计数不过期
+type memoryThrottleStore struct {
+	keys   map[string]bool
+	counts map[string]int
+}
+
+func newMemoryThrottleStore() *memoryThrottleStore {
+	return &memoryThrottleStore{keys: map[string]bool{}, counts: map[string]int{}}
+}
+
+func (s *memoryThrottleStore) AllowSlidingWindow(_ context.Context, key string, limit int, _ time.Duration) (*database.RateLimitResult, error) {
+	s.counts[key]++
+	return &database.RateLimitResult{Allowed: s.counts[key] <= limit, Limit: limit}, nil
+}
+
+func (s *memoryThrottleStore) SetNX(_ context.Context, key string, _ interface{}, _ time.Duration) (bool, error) {
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+// throttleAlert 指定规则的测试告警，所有规则属于同一用户且类型相同
+func throttleAlert(ruleID uint64) *models.Alert {
+	return &models.Alert{
+		RuleID: ruleID,
+		Type:   models.AlertTypeLargeTransfer,
+		Rule:   models.AlertRule{UserID: 1},
+	}
+}
+
+func TestThrottlerCooldownIsPerRule(t *testing.T) {
+	throttler := NewThrottler(newMemoryThrottleStore(), config.AlertConfig{Cooldown: time.Minute, MaxPerHour: 10},
+		metrics.NewMetrics("test"), testLogger())
+	ctx := context.Background()
+
+	if reason := throttler.Check(ctx, throttleAlert(1)); reason != "" {
+		t.Fatalf("first alert of rule 1 suppressed: %s", reason)
+	}
+	// 同一用户同类型的另一条规则不受规则 1 冷却期影响
+	if reason := throttler.Check(ctx, throttleAlert(2)); reason != "" {
+		t.Fatalf("first alert of rule 2 suppressed: %s", reason)
+	}
+	if reason := throttler.Check(ctx, throttleAlert(1)); reason != SuppressReasonCooldown {
+		t.Fatalf("expected repeated alert of rule 1 to be in cooldown, got %q", reason)
+	}
+}
+
+func TestThrottlerCooldownIsPerGroup(t *testing.T) {
+	throttler := NewThrottler(newMemoryThrottleStore(), config.AlertConfig{Cooldown: time.Minute, MaxPerHour: 10},
+		metrics.NewMetrics("test"), testLogger())
+	ctx := context.Background()
+
+	// 按地址分组的规则，每个分组窗口输出一条告警，不同分组互不占用冷却期
+	grouped := func(address string) *models.Alert {
+		alert := throttleAlert(1)
+		alert.GroupKey = "1|address=" + address
+		return alert
+	}
+	for _, address := range []string{"0xaaa", "0xbbb"} {
+		if reason := throttler.Check(ctx, grouped(address)); reason != "" {
+			t.Fatalf("first alert of group %s suppressed: %s", address, reason)
+		}
+	}
+	if reason := throttler.Check(ctx, grouped("0xaaa")); reason != SuppressReasonCooldown {
+		t.Fatalf("expected repeated alert of the same group to be in cooldown, got %q", reason)
+	}
+}
+
+func TestThrottlerHourlyLimitDoesNotConsumeCooldown(t *testing.T) {
+	store := newMemoryThrottleStore()
+	throttler := NewThrottler(store, config.AlertConfig{Cooldown: time.Minute, MaxPerHour: 1},
+		metrics.NewMetrics("test"), testLogger())
+	ctx := context.Background()
+
+	if reason := throttler.Check(ctx, throttleAlert(1)); reason != "" {
+		t.Fatalf("first alert suppressed: %s", reason)
+	}
+	if reason := throttler.Check(ctx, throttleAlert(2)); reason != SuppressReasonMaxPerHour {
+		t.Fatalf("expected hourly limit, got %q", reason)
+	}
+
+	// 滑动窗口过去后，被每小时上限抑制的规则 2 不处于冷却期
+	store.counts = map[string]int{}
+	if reason := throttler.Check(ctx, throttleAlert(2)); reason != "" {
+		t.Fatalf("alert rejected by the hourly limit consumed the cooldown: %s", reason)
+	}
+}
+
+func TestThrottlerMaxPerHourIsPerUser(t *testing.T) {
+	throttler := NewThrottler(newMemoryThrottleStore(), config.AlertConfig{MaxPerHour: 2},
+		metrics.NewMetrics("test"), testLogger())
+	ctx := context.Background()
+
+	for ruleID := uint64(1); ruleID <= 2; ruleID++ {
+		if reason := throttler.Check(ctx, throttleAlert(ruleID)); reason != "" {
+			t.Fatalf("alert of rule %d suppressed: %s", ruleID, reason)
+		}
+	}
+	if reason := throttler.Check(ctx, throttleAlert(3)); reason != SuppressReasonMaxPerHour {
+		t.Fatalf("expected hourly limit across rules, got %q", reason)
+	}
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// WebhookNotifier 通用 Webhook 发送器，target 为回调 URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建 Webhook 发送器
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+// Channel 返回渠道类型
+func (n *WebhookNotifier) Channel() models.NotificationChannel {
+	return models.ChannelWebhook
+}
+
+// Send 以 JSON 形式推送告警，config["headers"] 可指定额外请求头
+func (n *WebhookNotifier) Send(ctx context.Context, target string, msg *Message, config map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"title":    msg.Title,
+		"message":  msg.Body,
+		"severity": msg.Severity,
+		"sent_at":  time.Now().UTC(),
+	}
+	if a := msg.Alert; a != nil {
+		payload["alert"] = map[string]interface{}{
+			"id":            a.ID,
+			"rule_id":       a.RuleID,
+			"type":          a.Type,
+			"trigger_value": a.TriggerValue,
+			"trigger_data":  a.TriggerData,
+			"trigger_time":  a.TriggerTime,
+			"group_key":     a.GroupKey,
+			"group_count":   a.GroupCount,
+		}
+	}
+
+	headers := map[string]string{}
+	if raw, ok := config["headers"].(map[string]interface{}); ok {
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				headers[k] = s
+			}
+		}
+	}
+
+	if _, err := postJSON(ctx, n.client, target, payload, headers); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWebhookNotifierSend(t *testing.T) {
+	server := newStubServer(t)
+
+	config := map[string]interface{}{
+		"headers": map[string]interface{}{
+			"Authorization": "Bearer secret",
+			"X-Team":        "ops",
+			"X-Ignored":     42,
+		},
+	}
+	if err := NewWebhookNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), config); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	req := requests[0]
+
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := req.header.Get("X-Team"); got != "ops" {
+		t.Errorf("X-Team = %q", got)
+	}
+	if got := req.header.Get("X-Ignored"); got != "" {
+		t.Errorf("non-string header should be skipped, got %q", got)
+	}
+
+	for key, want := range map[string]interface{}{
+		"title":    "Large transfer",
+		"message":  "1000 ETH moved",
+		"severity": "high",
+	} {
+		if got := req.body[key]; got != want {
+			t.Errorf("payload[%q] = %v, want %v", key, got, want)
+		}
+	}
+	if _, ok := req.body["sent_at"].(string); !ok {
+		t.Errorf("payload sent_at missing: %v", req.body["sent_at"])
+	}
+	alert, ok := req.body["alert"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("payload alert missing: %v", req.body)
+	}
+	if alert["rule_id"] != float64(7) || alert["type"] != "large_transfer" || alert["trigger_value"] != float64(1000) {
+		t.Errorf("unexpected alert payload: %v", alert)
+	}
+}
+
+func TestWebhookNotifierNon2xx(t *testing.T) {
+	server := newStubServer(t, http.StatusBadGateway)
+
+	err := NewWebhookNotifier(server.Client()).Send(context.Background(), server.URL, testMessage(), nil)
+	if err == nil {
+		t.Fatal("expected error for 502 response")
+	}
+	if !strings.Contains(err.Error(), "webhook") || !strings.Contains(err.Error(), "502") {
+		t.Errorf("error should name the channel and status: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// alertColumns alerts 表可写入的列
+var alertColumns = []string{
+	"rule_id", "type", "severity", "title", "message", "trigger_value", "trigger_data",
+	"trigger_time", "group_key", "group_count", "status", "notification_sent", "sent_at", "error_message", "retry_count",
+	"acknowledged_by", "acknowledged_at", "resolved_by", "resolved_at", "snoozed_until", "escalation_level",
+}
+
+// alertSelect 告警记录查询列，可空文本列统一转换为空字符串
+const alertSelect = `SELECT id, created_at, updated_at, rule_id, type, severity, title, message,
+	COALESCE(trigger_value, 0) AS trigger_value, COALESCE(trigger_data, '') AS trigger_data,
+	COALESCE(trigger_time, created_at) AS trigger_time, COALESCE(group_key, '') AS group_key,
+	COALESCE(group_count, 1) AS group_count, COALESCE(status, 'pending') AS status,
+	COALESCE(notification_sent, FALSE) AS notification_sent, sent_at,
+	COALESCE(error_message, '') AS error_message, COALESCE(retry_count, 0) AS retry_count,
+	acknowledged_by, acknowledged_at, resolved_by, resolved_at, snoozed_until,
+	COALESCE(escalation_level, 0) AS escalation_level
+	FROM alerts`
+
+// alertOrderColumns 允许排序的列
+var alertOrderColumns = []string{"id", "rule_id", "severity", "status", "trigger_time", "trigger_value", "created_at"}
+
+// AlertRepository 告警记录数据访问
+type AlertRepository struct {
+	db Executor
+}
+
+// NewAlertRepository 创建告警记录数据访问对象
+func NewAlertRepository(db Executor) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *AlertRepository) WithTx(tx Executor) *AlertRepository {
+	return &AlertRepository{db: tx}
+}
+
+// Create 写入告警记录
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	query := fmt.Sprintf(
+		"INSERT INTO alerts (%s) VALUES %s RETURNING id, created_at, updated_at",
+		strings.Join(alertColumns, ", "),
+		buildBulkInsert(1, len(alertColumns)),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, alertValues(alert)...)
+	if err := row.Scan(&alert.ID, &alert.CreatedAt, &alert.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create alert for rule %d: %w", alert.RuleID, err)
+	}
+	return nil
+}
+
+// BulkCreate 批量写入告警记录
+func (r *AlertRepository) BulkCreate(ctx context.Context, alerts []*models.Alert) error {
+	chunkSize := maxQueryParams / len(alertColumns)
+
+	for start := 0; start < len(alerts); start += chunkSize {
+		end := min(start+chunkSize, len(alerts))
+		chunk := alerts[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*len(alertColumns))
+		for _, a := range chunk {
+			args = append(args, alertValues(a)...)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO alerts (%s) VALUES %s",
+			strings.Join(alertColumns, ", "),
+			buildBulkInsert(len(chunk), len(alertColumns)),
+		)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert %d alerts: %w", len(chunk), err)
+		}
+	}
+	return nil
+}
+
+// GetByID 按主键查询告警记录
+func (r *AlertRepository) GetByID(ctx context.Context, id uint64) (*models.Alert, error) {
+	var alert models.Alert
+	if err := getOne(ctx, r.db, &alert, alertSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get alert %d", id)
+	}
+	return &alert, nil
+}
+
+// List 按条件分页查询告警记录，返回当前页数据与总数
+func (r *AlertRepository) List(ctx context.Context, params *models.AlertQueryParams) ([]*models.Alert, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM alerts "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+
+	alerts := []*models.Alert{}
+	query := alertSelect + " " + where + buildOrderClause(&params.PaginationParams, alertOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &alerts, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	return alerts, total, nil
+}
+
+// Update 按主键覆盖告警记录的全部字段
+func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error {
+	args := append([]interface{}{alert.ID}, alertValues(alert)...)
+	row := r.db.QueryRowxContext(ctx, buildUpdateByID("alerts", alertColumns), args...)
+	if err := row.Scan(&alert.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update alert %d", alert.ID)
+	}
+	return nil
+}
+
+// UpdateNotificationStatus 更新告警的通知发送状态
+func (r *AlertRepository) UpdateNotificationStatus(ctx context.Context, alert *models.Alert) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE alerts SET status = $2, notification_sent = $3, sent_at = $4, error_message = $5,
+			retry_count = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		alert.ID, alert.Status, alert.NotificationSent, alert.SentAt, alert.ErrorMessage, alert.RetryCount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification status of alert %d: %w", alert.ID, err)
+	}
+	return nil
+}
+
+// UpdateLifecycle 更新告警的确认、解决与静默状态
+func (r *AlertRepository) UpdateLifecycle(ctx context.Context, alert *models.Alert) error {
+	row := r.db.QueryRowxContext(ctx,
+		`UPDATE alerts SET acknowledged_by = $2, acknowledged_at = $3, resolved_by = $4, resolved_at = $5,
+			snoozed_until = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at`,
+		alert.ID, alert.AcknowledgedBy, alert.AcknowledgedAt, alert.ResolvedBy, alert.ResolvedAt, alert.SnoozedUntil,
+	)
+	if err := row.Scan(&alert.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update lifecycle of alert %d", alert.ID)
+	}
+	return nil
+}
+
+// ListUnresolvedByRule 查询规则下所有未解决的告警
+func (r *AlertRepository) ListUnresolvedByRule(ctx context.Context, ruleID uint64) ([]*models.Alert, error) {
+	alerts := []*models.Alert{}
+	query := alertSelect + " WHERE rule_id = $1 AND resolved_at IS NULL ORDER BY id"
+	if err := sqlx.SelectContext(ctx, r.db, &alerts, query, ruleID); err != nil {
+		return nil, fmt.Errorf("failed to list unresolved alerts of rule %d: %w", ruleID, err)
+	}
+	return alerts, nil
+}
+
+// ListPendingEscalation 查询规则下未确认、未解决、未静默且未被抑制，且下一个升级步骤在 at 之前到期的告警
+// 已执行完所有步骤的告警不再返回
+func (r *AlertRepository) ListPendingEscalation(ctx context.Context, ruleID uint64, policy *models.EscalationPolicy, at time.Time) ([]*models.Alert, error) {
+	// 各步骤自触发起的分钟数，按升级等级取下一步骤，数组下标从 1 开始
+	after := make([]int64, len(policy.Steps))
+	for i, step := range policy.Steps {
+		after[i] = int64(step.After)
+	}
+
+	alerts := []*models.Alert{}
+	query := alertSelect + ` WHERE rule_id = $1 AND acknowledged_at IS NULL AND resolved_at IS NULL
+		AND (snoozed_until IS NULL OR snoozed_until <= $2) AND status <> $3
+		AND COALESCE(escalation_level, 0) < cardinality($4::int[])
+		AND COALESCE(trigger_time, created_at) + make_interval(mins => ($4::int[])[COALESCE(escalation_level, 0) + 1]) <= $2
+		ORDER BY id`
+	if err := sqlx.SelectContext(ctx, r.db, &alerts, query, ruleID, at, models.NotificationStatusSuppressed, pq.Array(after)); err != nil {
+		return nil, fmt.Errorf("failed to list escalation candidates of rule %d: %w", ruleID, err)
+	}
+	return alerts, nil
+}
+
+// Escalate 将告警从 fromLevel 升级到下一步并更新严重级别
+// 仅在告警仍未确认、未解决且升级步骤未被其他进程执行时生效，返回是否升级成功
+func (r *AlertRepository) Escalate(ctx context.Context, id uint64, fromLevel int32, severity models.AlertSeverity) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE alerts SET escalation_level = $3, severity = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND COALESCE(escalation_level, 0) = $2 AND acknowledged_at IS NULL AND resolved_at IS NULL`,
+		id, fromLevel, fromLevel+1, severity,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to escalate alert %d: %w", id, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to escalate alert %d: %w", id, err)
+	}
+	return affected > 0, nil
+}
+
+// IsSnoozed 检查规则在指定分组下是否存在仍处于静默期的未解决告警
+func (r *AlertRepository) IsSnoozed(ctx context.Context, ruleID uint64, groupKey string, at time.Time) (bool, error) {
+	var snoozed bool
+	err := sqlx.GetContext(ctx, r.db, &snoozed,
+		`SELECT EXISTS (SELECT 1 FROM alerts WHERE rule_id = $1 AND COALESCE(group_key, '') = $2
+			AND resolved_at IS NULL AND snoozed_until > $3)`,
+		ruleID, groupKey, at,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to check snooze of rule %d: %w", ruleID, err)
+	}
+	return snoozed, nil
+}
+
+// ListRecentByRule 查询规则在 [start, end) 期间最近的告警，不含被抑制的告警
+func (r *AlertRepository) ListRecentByRule(ctx context.Context, ruleID uint64, start, end time.Time, limit int) ([]*models.Alert, error) {
+	alerts := []*models.Alert{}
+	query := alertSelect + ` WHERE rule_id = $1 AND trigger_time >= $2 AND trigger_time < $3 AND status <> $4
+		ORDER BY trigger_time DESC LIMIT $5`
+	if err := sqlx.SelectContext(ctx, r.db, &alerts, query, ruleID, start, end, models.NotificationStatusSuppressed, limit); err != nil {
+		return nil, fmt.Errorf("failed to list recent alerts of rule %d: %w", ruleID, err)
+	}
+	return alerts, nil
+}
+
+// CountByRule 统计规则在 [start, end) 期间的告警数与分组聚合后的触发次数，不含被抑制的告警
+func (r *AlertRepository) CountByRule(ctx context.Context, ruleID uint64, start, end time.Time) (int64, int64, error) {
+	var counts struct {
+		Alerts      int64 `json:"alerts"`
+		Occurrences int64 `json:"occurrences"`
+	}
+	err := sqlx.GetContext(ctx, r.db, &counts,
+		`SELECT COUNT(*) AS alerts, COALESCE(SUM(COALESCE(group_count, 1)), 0) AS occurrences
+		FROM alerts WHERE rule_id = $1 AND trigger_time >= $2 AND trigger_time < $3 AND status <> $4`,
+		ruleID, start, end, models.NotificationStatusSuppressed,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count alerts of rule %d: %w", ruleID, err)
+	}
+	return counts.Alerts, counts.Occurrences, nil
+}
+
+// Delete 按主键删除告警记录
+func (r *AlertRepository) Delete(ctx context.Context, id uint64) error {
+	return deleteByID(ctx, r.db, "alerts", id)
+}
+
+// alertValues 按 alertColumns 顺序返回告警字段值
+func alertValues(a *models.Alert) []interface{} {
+	return []interface{}{
+		a.RuleID, a.Type, a.Severity, a.Title, a.Message, a.TriggerValue, a.TriggerData,
+		a.TriggerTime, a.GroupKey, a.GroupCount, a.Status, a.NotificationSent, a.SentAt, a.ErrorMessage, a.RetryCount,
+		a.AcknowledgedBy, a.AcknowledgedAt, a.ResolvedBy, a.ResolvedAt, a.SnoozedUntil, a.EscalationLevel,
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// alertEscalationSelect 告警升级记录查询列，可空文本列统一转换为空字符串
+const alertEscalationSelect = `SELECT id, created_at, updated_at, alert_id, rule_id, level, from_severity, to_severity,
+	COALESCE(channels, '') AS channels, COALESCE(notified, FALSE) AS notified,
+	COALESCE(error_message, '') AS error_message
+	FROM alert_escalations`
+
+// AlertEscalationRepository 告警升级记录数据访问
+type AlertEscalationRepository struct {
+	db Executor
+}
+
+// NewAlertEscalationRepository 创建告警升级记录数据访问对象
+func NewAlertEscalationRepository(db Executor) *AlertEscalationRepository {
+	return &AlertEscalationRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *AlertEscalationRepository) WithTx(tx Executor) *AlertEscalationRepository {
+	return &AlertEscalationRepository{db: tx}
+}
+
+// Create 写入告警升级记录
+func (r *AlertEscalationRepository) Create(ctx context.Context, escalation *models.AlertEscalation) error {
+	row := r.db.QueryRowxContext(ctx,
+		`INSERT INTO alert_escalations (alert_id, rule_id, level, from_severity, to_severity, channels, notified, error_message)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`,
+		escalation.AlertID, escalation.RuleID, escalation.Level, escalation.FromSeverity, escalation.ToSeverity,
+		escalation.Channels, escalation.Notified, escalation.ErrorMessage,
+	)
+	if err := row.Scan(&escalation.ID, &escalation.CreatedAt, &escalation.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create escalation for alert %d: %w", escalation.AlertID, err)
+	}
+	return nil
+}
+
+// ListByAlert 按升级顺序查询告警的全部升级记录
+func (r *AlertEscalationRepository) ListByAlert(ctx context.Context, alertID uint64) ([]*models.AlertEscalation, error) {
+	escalations := []*models.AlertEscalation{}
+	query := alertEscalationSelect + " WHERE alert_id = $1 ORDER BY level, id"
+	if err := sqlx.SelectContext(ctx, r.db, &escalations, query, alertID); err != nil {
+		return nil, fmt.Errorf("failed to list escalations of alert %d: %w", alertID, err)
+	}
+	return escalations, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// alertRuleSelect 告警规则查询列，可空文本列统一转换为空字符串
+const alertRuleSelect = `SELECT id, created_at, updated_at, name, COALESCE(description, '') AS description,
+	type, severity, status, conditions, COALESCE(threshold, 0) AS threshold, operator,
+	COALESCE(time_window, 60) AS time_window, COALESCE(cooldown, 300) AS cooldown,
+	COALESCE(aggregation, '') AS aggregation, COALESCE(aggregation_field, '') AS aggregation_field,
+	COALESCE(group_by, '') AS group_by, COALESCE(group_window, 0) AS group_window,
+	COALESCE(escalation_policy, '') AS escalation_policy,
+	COALESCE(notification_channels, '') AS notification_channels,
+	COALESCE(notification_template, '') AS notification_template,
+	user_id, COALESCE(trigger_count, 0) AS trigger_count, last_triggered, last_checked
+	FROM alert_rules`
+
+// AlertRuleRepository 告警规则数据访问
+type AlertRuleRepository struct {
+	db Executor
+}
+
+// NewAlertRuleRepository 创建告警规则数据访问对象
+func NewAlertRuleRepository(db Executor) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *AlertRuleRepository) WithTx(tx Executor) *AlertRuleRepository {
+	return &AlertRuleRepository{db: tx}
+}
+
+// alertRuleOrderColumns 允许排序的列
+var alertRuleOrderColumns = []string{"id", "name", "type", "severity", "status", "created_at", "updated_at", "last_triggered"}
+
+// Create 写入告警规则
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	row := r.db.QueryRowxContext(ctx,
+		`INSERT INTO alert_rules (name, description, type, severity, status, conditions, threshold, operator,
+			time_window, cooldown, aggregation, aggregation_field, group_by, group_window, escalation_policy,
+			notification_channels, notification_template, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at`,
+		rule.Name, rule.Description, rule.Type, rule.Severity, rule.Status, rule.Conditions, rule.Threshold,
+		rule.Operator, rule.TimeWindow, rule.Cooldown, rule.Aggregation, rule.AggregationField, rule.GroupBy, rule.GroupWindow, rule.EscalationPolicy,
+		rule.NotificationChannels, rule.NotificationTemplate, rule.UserID,
+	)
+	if err := row.Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return nil
+}
+
+// GetByID 按 ID 查询告警规则，不存在时返回 models.ErrRecordNotFound
+func (r *AlertRuleRepository) GetByID(ctx context.Context, id uint64) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	if err := getOne(ctx, r.db, &rule, alertRuleSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get alert rule %d", id)
+	}
+	return &rule, nil
+}
+
+// List 按条件分页查询告警规则，返回当前页数据与总数
+func (r *AlertRuleRepository) List(ctx context.Context, params *models.AlertRuleQueryParams) ([]*models.AlertRule, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM alert_rules "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count alert rules: %w", err)
+	}
+
+	rules := []*models.AlertRule{}
+	query := alertRuleSelect + " " + where + buildOrderClause(&params.PaginationParams, alertRuleOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &rules, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return rules, total, nil
+}
+
+// Update 更新告警规则的可编辑字段
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *models.AlertRule) error {
+	row := r.db.QueryRowxContext(ctx,
+		`UPDATE alert_rules SET name = $2, description = $3, severity = $4, status = $5, conditions = $6,
+			threshold = $7, operator = $8, time_window = $9, cooldown = $10, aggregation = $11,
+			aggregation_field = $12, group_by = $13, group_window = $14, escalation_policy = $15,
+			notification_channels = $16, notification_template = $17
+		WHERE id = $1
+		RETURNING updated_at`,
+		rule.ID, rule.Name, rule.Description, rule.Severity, rule.Status, rule.Conditions, rule.Threshold,
+		rule.Operator, rule.TimeWindow, rule.Cooldown, rule.Aggregation, rule.AggregationField, rule.GroupBy, rule.GroupWindow, rule.EscalationPolicy,
+		rule.NotificationChannels, rule.NotificationTemplate,
+	)
+	if err := row.Scan(&rule.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update alert rule %d", rule.ID)
+	}
+	return nil
+}
+
+// UpdateStatus 更新告警规则状态
+func (r *AlertRuleRepository) UpdateStatus(ctx context.Context, id uint64, status models.AlertStatus) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE alert_rules SET status = $2 WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update status of alert rule %d: %w", id, err)
+	}
+	return checkAffected(res)
+}
+
+// ListActive 获取所有激活状态的告警规则
+func (r *AlertRuleRepository) ListActive(ctx context.Context) ([]*models.AlertRule, error) {
+	var rules []*models.AlertRule
+	query := alertRuleSelect + " WHERE status = $1 ORDER BY id"
+	if err := sqlx.SelectContext(ctx, r.db, &rules, query, models.AlertStatusActive); err != nil {
+		return nil, fmt.Errorf("failed to list active alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateTriggerStats 更新规则的触发统计
+func (r *AlertRuleRepository) UpdateTriggerStats(ctx context.Context, id, triggerCount uint64, lastTriggered time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE alert_rules SET trigger_count = $1, last_triggered = $2, last_checked = $2 WHERE id = $3`,
+		triggerCount, lastTriggered, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trigger stats of alert rule %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// testRule 测试用告警规则
+func testRule(userID uint64, name string) *models.AlertRule {
+	return &models.AlertRule{
+		Name:                 name,
+		Description:          "gas above threshold",
+		Type:                 models.AlertTypeGasPrice,
+		Severity:             models.SeverityHigh,
+		Status:               models.AlertStatusActive,
+		Conditions:           `[]`,
+		Threshold:            100,
+		Operator:             models.OpGreaterThan,
+		TimeWindow:           120,
+		Cooldown:             600,
+		NotificationChannels: `[]`,
+		UserID:               userID,
+	}
+}
+
+func TestAlertRuleRepositoryCreateAndUpdate(t *testing.T) {
+	truncate(t, "alert_rules", "users")
+	ctx := context.Background()
+	repo := NewAlertRuleRepository(testDB)
+
+	rule := testRule(insertUser(t, "alice"), "high gas")
+	if err := repo.Create(ctx, rule); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if rule.ID == 0 || rule.CreatedAt.IsZero() {
+		t.Fatalf("Create did not populate id and timestamps: %+v", rule.BaseModel)
+	}
+
+	rule.Name = "very high gas"
+	rule.Threshold = 250
+	rule.Severity = models.SeverityCritical
+	if err := repo.Update(ctx, rule); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, rule.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Name != "very high gas" || got.Threshold != 250 || got.Severity != models.SeverityCritical {
+		t.Fatalf("Update not persisted: name=%q threshold=%v severity=%q", got.Name, got.Threshold, got.Severity)
+	}
+	if got.TimeWindow != 120 || got.Cooldown != 600 || got.Description != "gas above threshold" {
+		t.Fatalf("unexpected columns after round trip: %+v", got)
+	}
+
+	if err := repo.UpdateStatus(ctx, rule.ID, models.AlertStatusPaused); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if got, err = repo.GetByID(ctx, rule.ID); err != nil || got.Status != models.AlertStatusPaused {
+		t.Fatalf("UpdateStatus not persisted: status=%v err=%v", got.Status, err)
+	}
+}
+
+func TestAlertRuleRepositoryList(t *testing.T) {
+	truncate(t, "alert_rules", "users")
+	ctx := context.Background()
+	repo := NewAlertRuleRepository(testDB)
+
+	alice, bob := insertUser(t, "alice"), insertUser(t, "bob")
+	for _, rule := range []*models.AlertRule{
+		testRule(alice, "gas one"),
+		testRule(alice, "gas two"),
+		testRule(alice, "gas deleted"),
+		testRule(bob, "gas bob"),
+	} {
+		if err := repo.Create(ctx, rule); err != nil {
+			t.Fatalf("Create %q: %v", rule.Name, err)
+		}
+		if rule.Name == "gas deleted" {
+			if err := repo.UpdateStatus(ctx, rule.ID, models.AlertStatusDeleted); err != nil {
+				t.Fatalf("UpdateStatus: %v", err)
+			}
+		}
+	}
+
+	params := &models.AlertRuleQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 1, OrderBy: "name", Order: "asc"},
+		UserID:           &alice,
+	}
+	rules, total, err := repo.List(ctx, params)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	// 已删除的规则与其他用户的规则不计入总数
+	if total != 2 {
+		t.Fatalf("expected total 2, got %d", total)
+	}
+	if len(rules) != 1 || rules[0].Name != "gas one" {
+		t.Fatalf("expected first page to hold %q, got %+v", "gas one", rules)
+	}
+
+	params.Page = 2
+	if rules, _, err = repo.List(ctx, params); err != nil || len(rules) != 1 || rules[0].Name != "gas two" {
+		t.Fatalf("expected second page to hold %q, got %+v (err %v)", "gas two", rules, err)
+	}
+}
+
+func TestAlertRuleRepositoryNotFound(t *testing.T) {
+	truncate(t, "alert_rules", "users")
+	ctx := context.Background()
+	repo := NewAlertRuleRepository(testDB)
+
+	if _, err := repo.GetByID(ctx, 42); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+
+	missing := testRule(1, "missing")
+	missing.ID = 42
+	if err := repo.Update(ctx, missing); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("Update: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, 42, models.AlertStatusPaused); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("UpdateStatus: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestAlertRuleRepositoryNullableColumns(t *testing.T) {
+	truncate(t, "alert_rules", "users")
+	ctx := context.Background()
+	repo := NewAlertRuleRepository(testDB)
+
+	// 早期版本写入的规则中可空列为 NULL
+	var id uint64
+	err := testDB.QueryRowxContext(ctx,
+		`INSERT INTO alert_rules (name, type, severity, conditions, operator, user_id, threshold, time_window, cooldown,
+			group_window, trigger_count)
+		VALUES ('legacy', 'gas_price', 'low', '[]', 'gt', $1, NULL, NULL, NULL, NULL, NULL)
+		RETURNING id`,
+		insertUser(t, "alice"),
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to insert legacy rule: %v", err)
+	}
+
+	rule, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if rule.Description != "" || rule.Aggregation != "" || rule.AggregationField != "" || rule.GroupBy != "" ||
+		rule.EscalationPolicy != "" || rule.NotificationChannels != "" || rule.NotificationTemplate != "" {
+		t.Fatalf("expected empty strings for NULL text columns: %+v", rule)
+	}
+	if rule.Threshold != 0 || rule.GroupWindow != 0 || rule.TriggerCount != 0 {
+		t.Fatalf("expected zero values for NULL numeric columns: %+v", rule)
+	}
+	if rule.TimeWindow != 60 || rule.Cooldown != 300 {
+		t.Fatalf("expected default time_window 60 and cooldown 300, got %d and %d", rule.TimeWindow, rule.Cooldown)
+	}
+	if rule.LastTriggered != nil || rule.LastChecked != nil {
+		t.Fatalf("expected nil timestamps, got %v and %v", rule.LastTriggered, rule.LastChecked)
+	}
+
+	rules, err := repo.ListActive(ctx)
+	if err != nil || len(rules) != 1 || rules[0].ID != id {
+		t.Fatalf("ListActive: expected the legacy rule, got %+v (err %v)", rules, err)
+	}
+}
@@ -0,0 +1,186 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// testAlert 指定规则的测试告警记录
+func testAlert(ruleID uint64, severity models.AlertSeverity, triggerValue float64) *models.Alert {
+	return &models.Alert{
+		RuleID:       ruleID,
+		Type:         models.AlertTypeGasPrice,
+		Severity:     severity,
+		Title:        "gas price above threshold",
+		Message:      "gas price above threshold",
+		TriggerValue: triggerValue,
+		TriggerTime:  time.Now(),
+		GroupCount:   1,
+		Status:       models.NotificationStatusPending,
+	}
+}
+
+func TestAlertRepositoryCRUD(t *testing.T) {
+	truncate(t, "alerts")
+	ctx := context.Background()
+	repo := NewAlertRepository(testDB)
+
+	alert := testAlert(1, models.SeverityHigh, 120.5)
+	if err := repo.Create(ctx, alert); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.RuleID != 1 || got.TriggerValue != 120.5 || got.Status != models.NotificationStatusPending || got.SentAt != nil {
+		t.Fatalf("unexpected alert: %+v", got)
+	}
+
+	sentAt := time.Now()
+	alert.Status = models.NotificationStatusSent
+	alert.NotificationSent = true
+	alert.SentAt = &sentAt
+	if err := repo.UpdateNotificationStatus(ctx, alert); err != nil {
+		t.Fatalf("UpdateNotificationStatus: %v", err)
+	}
+	acknowledgedBy := uint64(7)
+	alert.AcknowledgedBy = &acknowledgedBy
+	alert.AcknowledgedAt = &sentAt
+	if err := repo.UpdateLifecycle(ctx, alert); err != nil {
+		t.Fatalf("UpdateLifecycle: %v", err)
+	}
+	got, err = repo.GetByID(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !got.NotificationSent || got.SentAt == nil || got.AcknowledgedBy == nil || *got.AcknowledgedBy != 7 {
+		t.Fatalf("expected sent and acknowledged alert: %+v", got)
+	}
+
+	alert.Title = "gas price back to normal"
+	if err := repo.Update(ctx, alert); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, err = repo.GetByID(ctx, alert.ID); err != nil || got.Title != alert.Title {
+		t.Fatalf("expected updated title, got %+v (err %v)", got, err)
+	}
+
+	if err := repo.Delete(ctx, alert.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, alert.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.Update(ctx, alert); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("Update: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestAlertRepositoryList(t *testing.T) {
+	truncate(t, "alerts", "alert_rules", "users")
+	ctx := context.Background()
+	repo := NewAlertRepository(testDB)
+	rules := NewAlertRuleRepository(testDB)
+
+	alice, bob := testRule(insertUser(t, "alice"), "alice gas"), testRule(insertUser(t, "bob"), "bob gas")
+	for _, rule := range []*models.AlertRule{alice, bob} {
+		if err := rules.Create(ctx, rule); err != nil {
+			t.Fatalf("Create rule: %v", err)
+		}
+	}
+
+	acknowledged := time.Now()
+	alerts := []*models.Alert{
+		testAlert(alice.ID, models.SeverityHigh, 150),
+		testAlert(alice.ID, models.SeverityHigh, 90),
+		testAlert(alice.ID, models.SeverityLow, 200),
+		testAlert(bob.ID, models.SeverityHigh, 300),
+	}
+	alerts[0].AcknowledgedAt = &acknowledged
+	if err := repo.BulkCreate(ctx, alerts); err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+
+	// 指定用户时只返回该用户规则产生的告警
+	minValue := 100.0
+	list, total, err := repo.List(ctx, &models.AlertQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10, OrderBy: "trigger_value", Order: "asc"},
+		FilterParams:     models.FilterParams{UserID: &alice.UserID},
+		Severity:         models.SeverityHigh,
+		MinTriggerValue:  &minValue,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].TriggerValue != 150 {
+		t.Fatalf("expected alice's high alert above 100, got total %d and %+v", total, list)
+	}
+
+	unacknowledged := false
+	list, total, err = repo.List(ctx, &models.AlertQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 2, OrderBy: "trigger_value", Order: "desc"},
+		RuleID:           &alice.ID,
+		Acknowledged:     &unacknowledged,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 2 || len(list) != 2 || list[0].TriggerValue != 200 || list[1].TriggerValue != 90 {
+		t.Fatalf("expected alice's unacknowledged alerts by value, got total %d and %+v", total, list)
+	}
+
+	// 无过滤条件时返回全部告警
+	if _, total, err = repo.List(ctx, &models.AlertQueryParams{}); err != nil || total != 4 {
+		t.Fatalf("expected 4 alerts without filters, got %d (err %v)", total, err)
+	}
+}
+
+func TestAlertRepositoryListPendingEscalation(t *testing.T) {
+	truncate(t, "alerts")
+	ctx := context.Background()
+	repo := NewAlertRepository(testDB)
+
+	now := time.Now()
+	// 两个升级步骤：触发 10 分钟后与 30 分钟后
+	policy := &models.EscalationPolicy{Steps: []models.EscalationStep{{After: 10}, {After: 30}}}
+
+	create := func(title string, triggered time.Duration, level int32) uint64 {
+		alert := &models.Alert{
+			RuleID:          1,
+			Type:            models.AlertTypeGasPrice,
+			Severity:        models.SeverityMedium,
+			Title:           title,
+			Message:         title,
+			TriggerTime:     now.Add(-triggered),
+			GroupCount:      1,
+			Status:          models.NotificationStatusSent,
+			EscalationLevel: level,
+		}
+		if err := repo.Create(ctx, alert); err != nil {
+			t.Fatalf("Create %q: %v", title, err)
+		}
+		return alert.ID
+	}
+
+	dueFirst := create("due for step 1", 15*time.Minute, 0)
+	create("not yet due for step 1", 5*time.Minute, 0)
+	dueSecond := create("due for step 2", 45*time.Minute, 1)
+	create("not yet due for step 2", 20*time.Minute, 1)
+	create("all steps executed", 2*time.Hour, 2)
+
+	alerts, err := repo.ListPendingEscalation(ctx, 1, policy, now)
+	if err != nil {
+		t.Fatalf("ListPendingEscalation: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].ID != dueFirst || alerts[1].ID != dueSecond {
+		t.Fatalf("expected alerts %d and %d, got %+v", dueFirst, dueSecond, alerts)
+	}
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// blockColumns blocks 表可写入的列
+var blockColumns = []string{
+	"number", "hash", "parent_hash", "timestamp", "miner", "difficulty", "total_difficulty",
+	"size", "gas_limit", "gas_used", "transaction_count", "state_root", "receipts_root",
+	"transactions_root", "extra_data", "mix_hash", "nonce", "logs_bloom", "base_fee_per_gas",
+}
+
+// blockSelect 区块查询列，可空文本列统一转换为空字符串
+const blockSelect = `SELECT id, created_at, updated_at, number, hash, parent_hash, timestamp, miner, difficulty,
+	COALESCE(total_difficulty, '') AS total_difficulty, COALESCE(size, 0) AS size, gas_limit, gas_used,
+	COALESCE(transaction_count, 0) AS transaction_count, COALESCE(state_root, '') AS state_root,
+	COALESCE(receipts_root, '') AS receipts_root, COALESCE(transactions_root, '') AS transactions_root,
+	COALESCE(extra_data, '') AS extra_data, COALESCE(mix_hash, '') AS mix_hash, COALESCE(nonce, '') AS nonce,
+	COALESCE(logs_bloom, '') AS logs_bloom, base_fee_per_gas
+	FROM blocks`
+
+// blockOrderColumns 允许排序的列
+var blockOrderColumns = []string{"id", "number", "timestamp", "gas_used", "transaction_count", "size"}
+
+// BlockRepository 区块数据访问
+type BlockRepository struct {
+	db Executor
+}
+
+// NewBlockRepository 创建区块数据访问对象
+func NewBlockRepository(db Executor) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *BlockRepository) WithTx(tx Executor) *BlockRepository {
+	return &BlockRepository{db: tx}
+}
+
+// Create 写入新区块，区块高度或哈希已存在时返回错误
+func (r *BlockRepository) Create(ctx context.Context, block *models.Block) error {
+	query := fmt.Sprintf(
+		"INSERT INTO blocks (%s) VALUES %s RETURNING id, created_at, updated_at",
+		strings.Join(blockColumns, ", "),
+		buildBulkInsert(1, len(blockColumns)),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, blockValues(block)...)
+	if err := row.Scan(&block.ID, &block.CreatedAt, &block.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create block %d: %w", block.Number, err)
+	}
+	return nil
+}
+
+// GetByID 按主键查询区块
+func (r *BlockRepository) GetByID(ctx context.Context, id uint64) (*models.Block, error) {
+	var block models.Block
+	if err := getOne(ctx, r.db, &block, blockSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get block by id %d", id)
+	}
+	return &block, nil
+}
+
+// GetByNumber 按区块高度查询区块
+func (r *BlockRepository) GetByNumber(ctx context.Context, number uint64) (*models.Block, error) {
+	var block models.Block
+	if err := getOne(ctx, r.db, &block, blockSelect+" WHERE number = $1", number); err != nil {
+		return nil, wrapNotFound(err, "failed to get block %d", number)
+	}
+	return &block, nil
+}
+
+// GetByHash 按区块哈希查询区块
+func (r *BlockRepository) GetByHash(ctx context.Context, hash string) (*models.Block, error) {
+	var block models.Block
+	if err := getOne(ctx, r.db, &block, blockSelect+" WHERE hash = $1", hash); err != nil {
+		return nil, wrapNotFound(err, "failed to get block %s", hash)
+	}
+	return &block, nil
+}
+
+// List 按条件分页查询区块，返回当前页数据与总数
+func (r *BlockRepository) List(ctx context.Context, params *models.BlockQueryParams) ([]*models.Block, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM blocks "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count blocks: %w", err)
+	}
+
+	blocks := []*models.Block{}
+	query := blockSelect + " " + where + buildOrderClause(&params.PaginationParams, blockOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &blocks, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	return blocks, total, nil
+}
+
+// Update 按主键覆盖区块的全部字段
+func (r *BlockRepository) Update(ctx context.Context, block *models.Block) error {
+	query := buildUpdateByID("blocks", blockColumns)
+
+	args := append([]interface{}{block.ID}, blockValues(block)...)
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&block.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update block %d", block.ID)
+	}
+	return nil
+}
+
+// Delete 按主键删除区块
+func (r *BlockRepository) Delete(ctx context.Context, id uint64) error {
+	return deleteByID(ctx, r.db, "blocks", id)
+}
+
+// Upsert 按区块高度写入区块，已存在时覆盖为新数据
+func (r *BlockRepository) Upsert(ctx context.Context, block *models.Block) error {
+	query := fmt.Sprintf(
+		`INSERT INTO blocks (%s) VALUES %s
+		ON CONFLICT (number) DO UPDATE SET %s
+		RETURNING id, created_at, updated_at`,
+		strings.Join(blockColumns, ", "),
+		buildBulkInsert(1, len(blockColumns)),
+		buildUpdateSet(blockColumns),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, blockValues(block)...)
+	if err := row.Scan(&block.ID, &block.CreatedAt, &block.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert block %d: %w", block.Number, err)
+	}
+	return nil
+}
+
+// BulkUpsert 批量写入区块，已存在的区块高度会被覆盖
+func (r *BlockRepository) BulkUpsert(ctx context.Context, blocks []*models.Block) error {
+	chunkSize := maxQueryParams / len(blockColumns)
+
+	for start := 0; start < len(blocks); start += chunkSize {
+		end := start + chunkSize
+		if end > len(blocks) {
+			end = len(blocks)
+		}
+		chunk := blocks[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*len(blockColumns))
+		for _, b := range chunk {
+			args = append(args, blockValues(b)...)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO blocks (%s) VALUES %s ON CONFLICT (number) DO UPDATE SET %s",
+			strings.Join(blockColumns, ", "),
+			buildBulkInsert(len(chunk), len(blockColumns)),
+			buildUpdateSet(blockColumns),
+		)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert %d blocks: %w", len(chunk), err)
+		}
+	}
+	return nil
+}
+
+// LatestNumber 返回已写入的最大区块高度，表为空时 ok 为 false
+func (r *BlockRepository) LatestNumber(ctx context.Context) (number uint64, ok bool, err error) {
+	var latest sql.NullInt64
+	if err := r.db.QueryRowxContext(ctx, `SELECT MAX(number) FROM blocks`).Scan(&latest); err != nil {
+		return 0, false, fmt.Errorf("failed to query latest block number: %w", err)
+	}
+	if !latest.Valid {
+		return 0, false, nil
+	}
+	return uint64(latest.Int64), true, nil
+}
+
+// DeleteByHashes 删除指定哈希的区块，返回删除的行数
+func (r *BlockRepository) DeleteByHashes(ctx context.Context, hashes []string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM blocks WHERE hash = ANY($1)`, pq.Array(hashes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %d blocks: %w", len(hashes), err)
+	}
+	return res.RowsAffected()
+}
+
+// blockValues 按 blockColumns 顺序返回区块字段值
+func blockValues(b *models.Block) []interface{} {
+	return []interface{}{
+		b.Number, b.Hash, b.ParentHash, b.Timestamp, b.Miner, b.Difficulty, b.TotalDifficulty,
+		b.Size, b.GasLimit, b.GasUsed, b.TransactionCount, b.StateRoot, b.ReceiptsRoot,
+		b.TransactionsRoot, b.ExtraData, b.MixHash, b.Nonce, b.LogsBloom, b.BaseFeePerGas,
+	}
+}
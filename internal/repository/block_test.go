@@ -0,0 +1,154 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// testBlock 指定高度的测试区块，哈希由高度与 fork 生成
+func testBlock(number uint64, fork string, txCount uint32) *models.Block {
+	return &models.Block{
+		Number:           number,
+		Hash:             fmt.Sprintf("0x%s%063x", fork, number),
+		ParentHash:       fmt.Sprintf("0x%064x", number-1),
+		Timestamp:        time.Unix(1_700_000_000+int64(number)*12, 0).UTC(),
+		Miner:            "0x0000000000000000000000000000000000000001",
+		Difficulty:       "0",
+		GasLimit:         30_000_000,
+		GasUsed:          uint64(txCount) * 21_000,
+		TransactionCount: txCount,
+	}
+}
+
+func TestBlockRepositoryCRUD(t *testing.T) {
+	truncate(t, "blocks")
+	ctx := context.Background()
+	repo := NewBlockRepository(testDB)
+
+	block := testBlock(100, "a", 2)
+	if err := repo.Create(ctx, block); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// 区块高度唯一
+	if err := repo.Create(ctx, testBlock(100, "b", 0)); err == nil {
+		t.Fatal("expected duplicate block number to be rejected")
+	}
+
+	got, err := repo.GetByHash(ctx, block.Hash)
+	if err != nil {
+		t.Fatalf("GetByHash: %v", err)
+	}
+	if got.ID != block.ID || got.Number != 100 || got.TransactionCount != 2 || !got.Timestamp.Equal(block.Timestamp) {
+		t.Fatalf("unexpected block: %+v", got)
+	}
+	if got.TotalDifficulty != "" || got.StateRoot != "" || got.BaseFeePerGas != nil {
+		t.Fatalf("expected empty values for NULL columns: %+v", got)
+	}
+
+	baseFee := "1000000000"
+	block.GasUsed = 42_000
+	block.BaseFeePerGas = &baseFee
+	if err := repo.Update(ctx, block); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = repo.GetByNumber(ctx, 100)
+	if err != nil {
+		t.Fatalf("GetByNumber: %v", err)
+	}
+	if got.GasUsed != 42_000 || got.BaseFeePerGas == nil || *got.BaseFeePerGas != baseFee {
+		t.Fatalf("expected updated gas used and base fee: %+v", got)
+	}
+
+	if err := repo.Delete(ctx, block.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, block.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.Delete(ctx, block.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("Delete: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.Update(ctx, block); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("Update: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestBlockRepositoryList(t *testing.T) {
+	truncate(t, "blocks")
+	ctx := context.Background()
+	repo := NewBlockRepository(testDB)
+
+	for number := uint64(1); number <= 5; number++ {
+		if err := repo.Create(ctx, testBlock(number, "a", uint32(number%2))); err != nil {
+			t.Fatalf("Create %d: %v", number, err)
+		}
+	}
+
+	minNumber, maxNumber := uint64(2), uint64(5)
+	empty := false
+	blocks, total, err := repo.List(ctx, &models.BlockQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 1, OrderBy: "number", Order: "asc"},
+		MinNumber:        &minNumber,
+		MaxNumber:        &maxNumber,
+		EmptyBlocks:      &empty,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	// 2..5 中非空区块为 3 与 5，按高度升序分页
+	if total != 2 || len(blocks) != 1 || blocks[0].Number != 3 {
+		t.Fatalf("expected block 3 of 2 matches, got total %d and %+v", total, blocks)
+	}
+
+	endTime := testBlock(2, "a", 0).Timestamp
+	blocks, total, err = repo.List(ctx, &models.BlockQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10},
+		FilterParams:     models.FilterParams{EndTime: &endTime},
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 2 || len(blocks) != 2 || blocks[0].Number != 2 {
+		t.Fatalf("expected blocks 2 and 1 in id order, got total %d and %+v", total, blocks)
+	}
+}
+
+func TestBlockRepositoryBulkUpsert(t *testing.T) {
+	truncate(t, "blocks")
+	ctx := context.Background()
+	repo := NewBlockRepository(testDB)
+
+	if err := repo.BulkUpsert(ctx, []*models.Block{testBlock(1, "a", 0), testBlock(2, "a", 0)}); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+
+	// 重组后同一高度写入新区块，覆盖原有数据
+	if err := repo.BulkUpsert(ctx, []*models.Block{testBlock(2, "b", 3), testBlock(3, "b", 0)}); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+
+	got, err := repo.GetByNumber(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetByNumber: %v", err)
+	}
+	if got.Hash != testBlock(2, "b", 0).Hash || got.TransactionCount != 3 {
+		t.Fatalf("expected block 2 to be replaced, got %+v", got)
+	}
+
+	latest, ok, err := repo.LatestNumber(ctx)
+	if err != nil || !ok || latest != 3 {
+		t.Fatalf("LatestNumber: expected 3, got %d (ok %v, err %v)", latest, ok, err)
+	}
+
+	deleted, err := repo.DeleteByHashes(ctx, []string{got.Hash, testBlock(3, "b", 0).Hash})
+	if err != nil || deleted != 2 {
+		t.Fatalf("DeleteByHashes: expected 2 deleted, got %d (err %v)", deleted, err)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// CheckpointRepository 回填进度数据访问
+type CheckpointRepository struct {
+	db Executor
+}
+
+// NewCheckpointRepository 创建回填进度数据访问对象
+func NewCheckpointRepository(db Executor) *CheckpointRepository {
+	return &CheckpointRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *CheckpointRepository) WithTx(tx Executor) *CheckpointRepository {
+	return &CheckpointRepository{db: tx}
+}
+
+// Get 按名称查询回填进度，不存在时返回 nil
+func (r *CheckpointRepository) Get(ctx context.Context, name string) (*models.BackfillCheckpoint, error) {
+	var checkpoint models.BackfillCheckpoint
+	err := sqlx.GetContext(ctx, r.db, &checkpoint,
+		`SELECT id, created_at, updated_at, name, start_block, end_block, last_block, status
+		FROM backfill_checkpoints WHERE name = $1`,
+		name,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill checkpoint %q: %w", name, err)
+	}
+	return &checkpoint, nil
+}
+
+// Create 创建回填进度记录
+func (r *CheckpointRepository) Create(ctx context.Context, checkpoint *models.BackfillCheckpoint) error {
+	row := r.db.QueryRowxContext(ctx,
+		`INSERT INTO backfill_checkpoints (name, start_block, end_block, last_block, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		checkpoint.Name, checkpoint.StartBlock, checkpoint.EndBlock, checkpoint.LastBlock, checkpoint.Status,
+	)
+	if err := row.Scan(&checkpoint.ID, &checkpoint.CreatedAt, &checkpoint.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create backfill checkpoint %q: %w", checkpoint.Name, err)
+	}
+	return nil
+}
+
+// Update 更新回填进度与状态
+func (r *CheckpointRepository) Update(ctx context.Context, checkpoint *models.BackfillCheckpoint) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE backfill_checkpoints SET end_block = $2, last_block = $3, status = $4
+		WHERE id = $1`,
+		checkpoint.ID, checkpoint.EndBlock, checkpoint.LastBlock, checkpoint.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update backfill checkpoint %q: %w", checkpoint.Name, err)
+	}
+	return nil
+}
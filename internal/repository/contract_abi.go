@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// contractABISelect 合约 ABI 查询列，可空文本列统一转换为空字符串
+const contractABISelect = `SELECT id, created_at, updated_at, address, COALESCE(name, '') AS name, abi,
+	COALESCE(user_id, 0) AS user_id
+	FROM contract_abis`
+
+// ContractABIRepository 合约 ABI 数据访问
+type ContractABIRepository struct {
+	db Executor
+}
+
+// NewContractABIRepository 创建合约 ABI 数据访问对象
+func NewContractABIRepository(db Executor) *ContractABIRepository {
+	return &ContractABIRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *ContractABIRepository) WithTx(tx Executor) *ContractABIRepository {
+	return &ContractABIRepository{db: tx}
+}
+
+// Upsert 写入合约 ABI，同一地址已存在时覆盖
+func (r *ContractABIRepository) Upsert(ctx context.Context, contract *models.ContractABI) error {
+	row := r.db.QueryRowxContext(ctx,
+		`INSERT INTO contract_abis (address, name, abi, user_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (address) DO UPDATE SET name = EXCLUDED.name, abi = EXCLUDED.abi, user_id = EXCLUDED.user_id
+		RETURNING id, created_at, updated_at`,
+		contract.Address, contract.Name, contract.ABI, contract.UserID,
+	)
+	if err := row.Scan(&contract.ID, &contract.CreatedAt, &contract.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert ABI of contract %s: %w", contract.Address, err)
+	}
+	return nil
+}
+
+// GetByAddress 按合约地址查询 ABI
+func (r *ContractABIRepository) GetByAddress(ctx context.Context, address string) (*models.ContractABI, error) {
+	var contract models.ContractABI
+	if err := getOne(ctx, r.db, &contract, contractABISelect+" WHERE address = $1", address); err != nil {
+		return nil, wrapNotFound(err, "failed to get ABI of contract %s", address)
+	}
+	return &contract, nil
+}
+
+// ListAll 按地址顺序返回全部合约 ABI
+func (r *ContractABIRepository) ListAll(ctx context.Context) ([]*models.ContractABI, error) {
+	contracts := []*models.ContractABI{}
+	if err := sqlx.SelectContext(ctx, r.db, &contracts, contractABISelect+" ORDER BY address"); err != nil {
+		return nil, fmt.Errorf("failed to list contract ABIs: %w", err)
+	}
+	return contracts, nil
+}
+
+// DeleteByAddress 删除合约 ABI，记录不存在时返回 models.ErrRecordNotFound
+func (r *ContractABIRepository) DeleteByAddress(ctx context.Context, address string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM contract_abis WHERE address = $1", address)
+	if err != nil {
+		return fmt.Errorf("failed to delete ABI of contract %s: %w", address, err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,141 @@
+// Package repository 提供基于 sqlx 的数据访问层
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// Executor 数据库执行器，*sqlx.DB 与 *sqlx.Tx 均实现该接口
+type Executor interface {
+	sqlx.ExtContext
+}
+
+// TxBeginner 可开启事务的对象，*database.PostgresManager 实现该接口
+type TxBeginner interface {
+	BeginTx(ctx context.Context) (*sqlx.Tx, error)
+}
+
+// RunInTx 在事务中执行 fn，fn 返回错误时回滚，否则提交
+func RunInTx(ctx context.Context, db TxBeginner, fn func(tx Executor) error) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// getOne 查询单行，无结果时返回 models.ErrRecordNotFound
+func getOne(ctx context.Context, db Executor, dest interface{}, query string, args ...interface{}) error {
+	return notFound(sqlx.GetContext(ctx, db, dest, query, args...))
+}
+
+// notFound 将 sql.ErrNoRows 转换为 models.ErrRecordNotFound
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrRecordNotFound
+	}
+	return err
+}
+
+// wrapNotFound 为错误附加上下文，models.ErrRecordNotFound 原样返回以便调用方判断
+func wrapNotFound(err error, format string, args ...interface{}) error {
+	if errors.Is(err, models.ErrRecordNotFound) {
+		return err
+	}
+	return fmt.Errorf(format+": %w", append(args, err)...)
+}
+
+// deleteByID 按主键删除记录，记录不存在时返回 models.ErrRecordNotFound
+func deleteByID(ctx context.Context, db Executor, table string, id uint64) error {
+	res, err := db.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %d: %w", table, id, err)
+	}
+	return checkAffected(res)
+}
+
+// checkAffected 未影响任何行时返回 models.ErrRecordNotFound
+func checkAffected(res sql.Result) error {
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return models.ErrRecordNotFound
+	}
+	return nil
+}
+
+// buildBulkInsert 构建多行 INSERT 语句的 VALUES 部分
+// 返回形如 ($1,$2),($3,$4) 的占位符字符串
+func buildBulkInsert(rows, columns int) string {
+	var sb strings.Builder
+	arg := 1
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("(")
+		for j := 0; j < columns; j++ {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(fmt.Sprintf("$%d", arg))
+			arg++
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// buildUpdateSet 构建 ON CONFLICT DO UPDATE 的 SET 子句
+func buildUpdateSet(columns []string) string {
+	sets := make([]string, 0, len(columns)+1)
+	for _, col := range columns {
+		sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	sets = append(sets, "updated_at = CURRENT_TIMESTAMP")
+	return strings.Join(sets, ", ")
+}
+
+// buildUpdateByID 构建按主键覆盖指定列的 UPDATE 语句，$1 为主键，其余参数按列顺序排列
+func buildUpdateByID(table string, columns []string) string {
+	sets := make([]string, len(columns))
+	for i, col := range columns {
+		sets[i] = fmt.Sprintf("%s = $%d", col, i+2)
+	}
+	return fmt.Sprintf(
+		"UPDATE %s SET %s, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING updated_at",
+		table, strings.Join(sets, ", "),
+	)
+}
+
+// buildOrderClause 构建 ORDER BY 与分页子句，排序列不在白名单内时使用 id
+func buildOrderClause(p *models.PaginationParams, allowed ...string) string {
+	column := "id"
+	for _, col := range allowed {
+		if p.OrderBy == col {
+			column = col
+			break
+		}
+	}
+
+	direction := "DESC"
+	if strings.EqualFold(p.Order, "asc") {
+		direction = "ASC"
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s LIMIT %d OFFSET %d", column, direction, p.GetLimit(), p.GetOffset())
+}
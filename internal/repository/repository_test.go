@@ -0,0 +1,123 @@
+//go:build embeddedpg
+
+// 数据访问层测试依赖嵌入式 Postgres，需下载 Postgres 二进制且不能以 root 运行：
+//
+//	go test -tags embeddedpg ./internal/repository/
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+)
+
+// testDB 所有测试共享的数据库连接，已执行全部迁移
+var testDB *sqlx.DB
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run 启动嵌入式 Postgres、执行迁移并运行测试
+func run(m *testing.M) int {
+	port, err := freePort()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find free port: %v\n", err)
+		return 1
+	}
+
+	runtimePath, err := os.MkdirTemp("", "repository-test-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create runtime path: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(runtimePath)
+
+	config := embeddedpostgres.DefaultConfig().
+		Port(port).
+		RuntimePath(runtimePath).
+		Logger(io.Discard)
+	pg := embeddedpostgres.NewDatabase(config)
+	if err := pg.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start embedded postgres: %v\n", err)
+		return 1
+	}
+	defer pg.Stop()
+
+	db, err := sqlx.Connect("postgres", config.GetConnectionURL()+"?sslmode=disable")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to embedded postgres: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+	// 与 database.NewPostgresManager 使用相同的列映射
+	db.Mapper = reflectx.NewMapperFunc("json", strings.ToLower)
+
+	migrator, err := database.NewMigrator(db.DB, "../../migrations", testLogger())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create migrator: %v\n", err)
+		return 1
+	}
+	if err := migrator.Up(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to apply migrations: %v\n", err)
+		return 1
+	}
+
+	testDB = db
+	return m.Run()
+}
+
+// freePort 返回一个当前空闲的本地端口
+func freePort() (uint32, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
+
+// testLogger 丢弃输出的日志记录器
+func testLogger() *logger.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &logger.Logger{Logger: log}
+}
+
+// truncate 清空测试涉及的表，保证用例之间互不影响
+func truncate(t *testing.T, tables ...string) {
+	t.Helper()
+	if _, err := testDB.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE"); err != nil {
+		t.Fatalf("failed to truncate %v: %v", tables, err)
+	}
+}
+
+// insertUser 以最少的列写入用户，其余可空列保持 NULL
+func insertUser(t *testing.T, username string) uint64 {
+	t.Helper()
+
+	var id uint64
+	err := testDB.QueryRowxContext(context.Background(),
+		`INSERT INTO users (username, email, password, timezone, language, failed_login_count, email_verified)
+		VALUES ($1, $2, 'hash', NULL, NULL, NULL, NULL)
+		RETURNING id`,
+		username, username+"@example.com",
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to insert user %q: %v", username, err)
+	}
+	return id
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// subscriptionColumns subscriptions 表可写入的列
+var subscriptionColumns = []string{
+	"user_id", "name", "description", "type", "status", "config", "filters", "notification_channels",
+	"notification_template", "max_notifications_per_hour", "notification_count", "last_notification_reset",
+	"total_notifications", "last_triggered", "last_checked", "expires_at",
+}
+
+// subscriptionSelect 订阅查询列，可空文本列统一转换为空字符串
+const subscriptionSelect = `SELECT id, created_at, updated_at, user_id, name, COALESCE(description, '') AS description,
+	type, status, config, COALESCE(filters, '') AS filters,
+	COALESCE(notification_channels, '') AS notification_channels,
+	COALESCE(notification_template, '') AS notification_template,
+	COALESCE(max_notifications_per_hour, 100) AS max_notifications_per_hour,
+	COALESCE(notification_count, 0) AS notification_count,
+	COALESCE(last_notification_reset, created_at) AS last_notification_reset,
+	COALESCE(total_notifications, 0) AS total_notifications, last_triggered, last_checked, expires_at
+	FROM subscriptions`
+
+// subscriptionOrderColumns 允许排序的列
+var subscriptionOrderColumns = []string{"id", "name", "type", "status", "created_at", "updated_at", "last_triggered", "expires_at"}
+
+// SubscriptionRepository 订阅数据访问
+type SubscriptionRepository struct {
+	db Executor
+}
+
+// NewSubscriptionRepository 创建订阅数据访问对象
+func NewSubscriptionRepository(db Executor) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *SubscriptionRepository) WithTx(tx Executor) *SubscriptionRepository {
+	return &SubscriptionRepository{db: tx}
+}
+
+// Create 写入订阅
+func (r *SubscriptionRepository) Create(ctx context.Context, sub *models.Subscription) error {
+	if sub.LastNotificationReset.IsZero() {
+		sub.LastNotificationReset = time.Now()
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO subscriptions (%s) VALUES %s RETURNING id, created_at, updated_at",
+		strings.Join(subscriptionColumns, ", "),
+		buildBulkInsert(1, len(subscriptionColumns)),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, subscriptionValues(sub)...)
+	if err := row.Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create subscription %q: %w", sub.Name, err)
+	}
+	return nil
+}
+
+// BulkCreate 批量写入订阅
+func (r *SubscriptionRepository) BulkCreate(ctx context.Context, subs []*models.Subscription) error {
+	chunkSize := maxQueryParams / len(subscriptionColumns)
+	now := time.Now()
+
+	for start := 0; start < len(subs); start += chunkSize {
+		end := min(start+chunkSize, len(subs))
+		chunk := subs[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*len(subscriptionColumns))
+		for _, s := range chunk {
+			if s.LastNotificationReset.IsZero() {
+				s.LastNotificationReset = now
+			}
+			args = append(args, subscriptionValues(s)...)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO subscriptions (%s) VALUES %s",
+			strings.Join(subscriptionColumns, ", "),
+			buildBulkInsert(len(chunk), len(subscriptionColumns)),
+		)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert %d subscriptions: %w", len(chunk), err)
+		}
+	}
+	return nil
+}
+
+// GetByID 按主键查询订阅
+func (r *SubscriptionRepository) GetByID(ctx context.Context, id uint64) (*models.Subscription, error) {
+	var sub models.Subscription
+	if err := getOne(ctx, r.db, &sub, subscriptionSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get subscription %d", id)
+	}
+	return &sub, nil
+}
+
+// List 按条件分页查询订阅，返回当前页数据与总数
+func (r *SubscriptionRepository) List(ctx context.Context, params *models.SubscriptionQueryParams) ([]*models.Subscription, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM subscriptions "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+
+	subs := []*models.Subscription{}
+	query := subscriptionSelect + " " + where + buildOrderClause(&params.PaginationParams, subscriptionOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &subs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	return subs, total, nil
+}
+
+// Update 按主键覆盖订阅的全部字段
+func (r *SubscriptionRepository) Update(ctx context.Context, sub *models.Subscription) error {
+	args := append([]interface{}{sub.ID}, subscriptionValues(sub)...)
+	row := r.db.QueryRowxContext(ctx, buildUpdateByID("subscriptions", subscriptionColumns), args...)
+	if err := row.Scan(&sub.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update subscription %d", sub.ID)
+	}
+	return nil
+}
+
+// UpdateStatus 更新订阅状态
+func (r *SubscriptionRepository) UpdateStatus(ctx context.Context, id uint64, status models.SubscriptionStatus) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE subscriptions SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update status of subscription %d: %w", id, err)
+	}
+	return checkAffected(res)
+}
+
+// Delete 按主键删除订阅
+func (r *SubscriptionRepository) Delete(ctx context.Context, id uint64) error {
+	return deleteByID(ctx, r.db, "subscriptions", id)
+}
+
+// subscriptionValues 按 subscriptionColumns 顺序返回订阅字段值
+func subscriptionValues(s *models.Subscription) []interface{} {
+	return []interface{}{
+		s.UserID, s.Name, s.Description, s.Type, s.Status, s.Config, s.Filters, s.NotificationChannels,
+		s.NotificationTemplate, s.MaxNotificationsPerHour, s.NotificationCount, s.LastNotificationReset,
+		s.TotalNotifications, s.LastTriggered, s.LastChecked, s.ExpiresAt,
+	}
+}
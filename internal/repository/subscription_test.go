@@ -0,0 +1,121 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// testSubscription 测试用订阅
+func testSubscription(userID uint64, name string, subType models.SubscriptionType) *models.Subscription {
+	return &models.Subscription{
+		UserID:                  userID,
+		Name:                    name,
+		Type:                    subType,
+		Status:                  models.SubStatusActive,
+		Config:                  `{}`,
+		MaxNotificationsPerHour: 10,
+	}
+}
+
+func TestSubscriptionRepositoryCRUD(t *testing.T) {
+	truncate(t, "subscriptions", "users")
+	ctx := context.Background()
+	repo := NewSubscriptionRepository(testDB)
+
+	sub := testSubscription(insertUser(t, "alice"), "whale watch", models.SubTypeAddress)
+	if err := repo.Create(ctx, sub); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if sub.LastNotificationReset.IsZero() {
+		t.Fatal("expected Create to initialize the notification reset time")
+	}
+
+	got, err := repo.GetByID(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Name != "whale watch" || got.Description != "" || got.Filters != "" || got.MaxNotificationsPerHour != 10 ||
+		got.LastTriggered != nil || got.ExpiresAt != nil {
+		t.Fatalf("unexpected subscription: %+v", got)
+	}
+
+	triggered := time.Now()
+	sub.Description = "large transfers"
+	sub.TotalNotifications = 3
+	sub.LastTriggered = &triggered
+	if err := repo.Update(ctx, sub); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, sub.ID, models.SubStatusPaused); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	got, err = repo.GetByID(ctx, sub.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Description != "large transfers" || got.TotalNotifications != 3 || got.LastTriggered == nil ||
+		got.Status != models.SubStatusPaused {
+		t.Fatalf("expected updated subscription: %+v", got)
+	}
+
+	if err := repo.Delete(ctx, sub.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, sub.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, sub.ID, models.SubStatusActive); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("UpdateStatus: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestSubscriptionRepositoryList(t *testing.T) {
+	truncate(t, "subscriptions", "users")
+	ctx := context.Background()
+	repo := NewSubscriptionRepository(testDB)
+
+	alice, bob := insertUser(t, "alice"), insertUser(t, "bob")
+	expired := time.Now().Add(-time.Hour)
+	subs := []*models.Subscription{
+		testSubscription(alice, "big Whale", models.SubTypeAddress),
+		testSubscription(alice, "whale contract", models.SubTypeContract),
+		testSubscription(alice, "gas", models.SubTypeGasPrice),
+		testSubscription(bob, "whale watch", models.SubTypeAddress),
+	}
+	subs[1].ExpiresAt = &expired
+	if err := repo.BulkCreate(ctx, subs); err != nil {
+		t.Fatalf("BulkCreate: %v", err)
+	}
+
+	// 名称不区分大小写模糊匹配，排除已过期订阅
+	notExpired := false
+	list, total, err := repo.List(ctx, &models.SubscriptionQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10},
+		Name:             "WHALE",
+		UserID:           &alice,
+		Expired:          &notExpired,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].Name != "big Whale" {
+		t.Fatalf("expected alice's active whale subscription, got total %d and %+v", total, list)
+	}
+
+	list, total, err = repo.List(ctx, &models.SubscriptionQueryParams{
+		PaginationParams: models.PaginationParams{Page: 2, PageSize: 2, OrderBy: "name", Order: "asc"},
+		Status:           models.SubStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 4 || len(list) != 2 || list[0].Name != "whale contract" {
+		t.Fatalf("expected the second page ordered by name, got total %d and %+v", total, list)
+	}
+}
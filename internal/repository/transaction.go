@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// maxQueryParams PostgreSQL 单条语句允许的最大参数数量
+const maxQueryParams = 65535
+
+// transactionColumns transactions 表可写入的列
+var transactionColumns = []string{
+	"hash", "block_number", "block_hash", "transaction_index", "from_address", "to_address",
+	"value", "input", "gas", "gas_used", "gas_price", "max_fee_per_gas", "max_priority_fee_per_gas",
+	"type", "status", "nonce", "v", "r", "s", "cumulative_gas_used", "effective_gas_price",
+	"contract_address", "logs_count", "logs_bloom", "timestamp",
+}
+
+// transactionSelect 交易查询列，可空文本列统一转换为空字符串
+const transactionSelect = `SELECT id, created_at, updated_at, hash, block_number, COALESCE(block_hash, '') AS block_hash,
+	COALESCE(transaction_index, 0) AS transaction_index, from_address, to_address, value,
+	COALESCE(input, '') AS input, gas, gas_used, gas_price, max_fee_per_gas, max_priority_fee_per_gas,
+	type, status, COALESCE(nonce, 0) AS nonce, COALESCE(v, '') AS v, COALESCE(r, '') AS r, COALESCE(s, '') AS s,
+	cumulative_gas_used, effective_gas_price, contract_address, COALESCE(logs_count, 0) AS logs_count,
+	COALESCE(logs_bloom, '') AS logs_bloom, COALESCE(timestamp, created_at) AS timestamp
+	FROM transactions`
+
+// transactionOrderColumns 允许排序的列
+var transactionOrderColumns = []string{"id", "block_number", "transaction_index", "timestamp", "gas", "gas_used", "nonce"}
+
+// TransactionRepository 交易数据访问
+type TransactionRepository struct {
+	db Executor
+}
+
+// NewTransactionRepository 创建交易数据访问对象
+func NewTransactionRepository(db Executor) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *TransactionRepository) WithTx(tx Executor) *TransactionRepository {
+	return &TransactionRepository{db: tx}
+}
+
+// Create 写入单笔交易，交易哈希已存在时返回错误
+func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
+	query := fmt.Sprintf(
+		"INSERT INTO transactions (%s) VALUES %s RETURNING id, created_at, updated_at",
+		strings.Join(transactionColumns, ", "),
+		buildBulkInsert(1, len(transactionColumns)),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, transactionValues(tx)...)
+	if err := row.Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create transaction %s: %w", tx.Hash, err)
+	}
+	return nil
+}
+
+// GetByID 按主键查询交易
+func (r *TransactionRepository) GetByID(ctx context.Context, id uint64) (*models.Transaction, error) {
+	var tx models.Transaction
+	if err := getOne(ctx, r.db, &tx, transactionSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get transaction by id %d", id)
+	}
+	return &tx, nil
+}
+
+// GetByHash 按交易哈希查询交易
+func (r *TransactionRepository) GetByHash(ctx context.Context, hash string) (*models.Transaction, error) {
+	var tx models.Transaction
+	if err := getOne(ctx, r.db, &tx, transactionSelect+" WHERE hash = $1", hash); err != nil {
+		return nil, wrapNotFound(err, "failed to get transaction %s", hash)
+	}
+	return &tx, nil
+}
+
+// List 按条件分页查询交易，返回当前页数据与总数
+func (r *TransactionRepository) List(ctx context.Context, params *models.TransactionQueryParams) ([]*models.Transaction, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM transactions "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	txs := []*models.Transaction{}
+	query := transactionSelect + " " + where + buildOrderClause(&params.PaginationParams, transactionOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &txs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return txs, total, nil
+}
+
+// Update 按主键覆盖交易的全部字段
+func (r *TransactionRepository) Update(ctx context.Context, tx *models.Transaction) error {
+	query := buildUpdateByID("transactions", transactionColumns)
+
+	args := append([]interface{}{tx.ID}, transactionValues(tx)...)
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&tx.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update transaction %d", tx.ID)
+	}
+	return nil
+}
+
+// Delete 按主键删除交易
+func (r *TransactionRepository) Delete(ctx context.Context, id uint64) error {
+	return deleteByID(ctx, r.db, "transactions", id)
+}
+
+// BulkUpsert 批量写入已打包交易，已存在的交易（如此前记录的待处理交易）会被更新
+func (r *TransactionRepository) BulkUpsert(ctx context.Context, txs []*models.Transaction) error {
+	return r.bulkInsert(ctx, txs, "ON CONFLICT (hash) DO UPDATE SET "+buildUpdateSet(transactionColumns))
+}
+
+// BulkInsertPending 批量写入待处理交易，已存在的交易保持不变
+func (r *TransactionRepository) BulkInsertPending(ctx context.Context, txs []*models.Transaction) error {
+	return r.bulkInsert(ctx, txs, "ON CONFLICT (hash) DO NOTHING")
+}
+
+// DeleteByBlockHashes 删除指定区块内的交易，返回删除的交易数
+// 交易日志需先通过 TransactionLogRepository.DeleteByBlockHashes 删除
+func (r *TransactionRepository) DeleteByBlockHashes(ctx context.Context, blockHashes []string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM transactions WHERE block_hash = ANY($1)`, pq.Array(blockHashes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete transactions of %d blocks: %w", len(blockHashes), err)
+	}
+	return res.RowsAffected()
+}
+
+// bulkInsert 按参数上限分块执行多行 INSERT
+func (r *TransactionRepository) bulkInsert(ctx context.Context, txs []*models.Transaction, conflict string) error {
+	chunkSize := maxQueryParams / len(transactionColumns)
+
+	for start := 0; start < len(txs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(txs) {
+			end = len(txs)
+		}
+		chunk := txs[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*len(transactionColumns))
+		for _, tx := range chunk {
+			args = append(args, transactionValues(tx)...)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO transactions (%s) VALUES %s %s",
+			strings.Join(transactionColumns, ", "),
+			buildBulkInsert(len(chunk), len(transactionColumns)),
+			conflict,
+		)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert %d transactions: %w", len(chunk), err)
+		}
+	}
+	return nil
+}
+
+// transactionValues 按 transactionColumns 顺序返回交易字段值
+func transactionValues(t *models.Transaction) []interface{} {
+	return []interface{}{
+		t.Hash, t.BlockNumber, t.BlockHash, t.Index, t.From, t.To,
+		t.Value, t.Input, t.Gas, t.GasUsed, t.GasPrice, t.MaxFeePerGas, t.MaxPriorityFeePerGas,
+		t.Type, t.Status, t.Nonce, t.V, t.R, t.S, t.CumulativeGasUsed, t.EffectiveGasPrice,
+		t.ContractAddress, t.LogsCount, t.LogsBloom, t.Timestamp,
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// transactionLogColumns transaction_logs 表可写入的列
+var transactionLogColumns = []string{
+	"transaction_hash", "log_index", "address", "topics", "data", "block_number", "removed",
+	"event_name", "decoded",
+}
+
+// transactionLogSelect 交易日志查询列，可空文本列统一转换为空字符串
+const transactionLogSelect = `SELECT id, created_at, updated_at, transaction_hash, log_index, address,
+	COALESCE(topics, '') AS topics, COALESCE(data, '') AS data, block_number, COALESCE(removed, FALSE) AS removed,
+	COALESCE(event_name, '') AS event_name, COALESCE(decoded, '') AS decoded
+	FROM transaction_logs`
+
+// transactionLogOrderColumns 允许排序的列
+var transactionLogOrderColumns = []string{"id", "block_number", "log_index"}
+
+// TransactionLogRepository 交易日志数据访问
+type TransactionLogRepository struct {
+	db Executor
+}
+
+// NewTransactionLogRepository 创建交易日志数据访问对象
+func NewTransactionLogRepository(db Executor) *TransactionLogRepository {
+	return &TransactionLogRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *TransactionLogRepository) WithTx(tx Executor) *TransactionLogRepository {
+	return &TransactionLogRepository{db: tx}
+}
+
+// Create 写入单条交易日志
+func (r *TransactionLogRepository) Create(ctx context.Context, log *models.TransactionLog) error {
+	query := fmt.Sprintf(
+		"INSERT INTO transaction_logs (%s) VALUES %s RETURNING id, created_at, updated_at",
+		strings.Join(transactionLogColumns, ", "),
+		buildBulkInsert(1, len(transactionLogColumns)),
+	)
+
+	row := r.db.QueryRowxContext(ctx, query, transactionLogValues(log)...)
+	if err := row.Scan(&log.ID, &log.CreatedAt, &log.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create log %d of transaction %s: %w", log.LogIndex, log.TransactionHash, err)
+	}
+	return nil
+}
+
+// GetByID 按主键查询交易日志
+func (r *TransactionLogRepository) GetByID(ctx context.Context, id uint64) (*models.TransactionLog, error) {
+	var log models.TransactionLog
+	if err := getOne(ctx, r.db, &log, transactionLogSelect+" WHERE id = $1", id); err != nil {
+		return nil, wrapNotFound(err, "failed to get transaction log %d", id)
+	}
+	return &log, nil
+}
+
+// ListByTransactionHash 按日志序号返回交易的全部日志
+func (r *TransactionLogRepository) ListByTransactionHash(ctx context.Context, hash string) ([]*models.TransactionLog, error) {
+	logs := []*models.TransactionLog{}
+	query := transactionLogSelect + " WHERE transaction_hash = $1 ORDER BY log_index"
+	if err := sqlx.SelectContext(ctx, r.db, &logs, query, hash); err != nil {
+		return nil, fmt.Errorf("failed to list logs of transaction %s: %w", hash, err)
+	}
+	return logs, nil
+}
+
+// List 按条件分页查询交易日志，返回当前页数据与总数
+func (r *TransactionLogRepository) List(ctx context.Context, params *models.TransactionLogQueryParams) ([]*models.TransactionLog, int64, error) {
+	where, args := params.BuildWhereClause()
+
+	var total int64
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM transaction_logs "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count transaction logs: %w", err)
+	}
+
+	logs := []*models.TransactionLog{}
+	query := transactionLogSelect + " " + where + buildOrderClause(&params.PaginationParams, transactionLogOrderColumns...)
+	if err := sqlx.SelectContext(ctx, r.db, &logs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list transaction logs: %w", err)
+	}
+	return logs, total, nil
+}
+
+// Update 按主键覆盖交易日志的全部字段
+func (r *TransactionLogRepository) Update(ctx context.Context, log *models.TransactionLog) error {
+	args := append([]interface{}{log.ID}, transactionLogValues(log)...)
+	row := r.db.QueryRowxContext(ctx, buildUpdateByID("transaction_logs", transactionLogColumns), args...)
+	if err := row.Scan(&log.UpdatedAt); err != nil {
+		return wrapNotFound(notFound(err), "failed to update transaction log %d", log.ID)
+	}
+	return nil
+}
+
+// Delete 按主键删除交易日志
+func (r *TransactionLogRepository) Delete(ctx context.Context, id uint64) error {
+	return deleteByID(ctx, r.db, "transaction_logs", id)
+}
+
+// BulkUpsert 批量写入交易日志，按交易哈希与日志序号去重
+func (r *TransactionLogRepository) BulkUpsert(ctx context.Context, logs []*models.TransactionLog) error {
+	chunkSize := maxQueryParams / len(transactionLogColumns)
+
+	for start := 0; start < len(logs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(logs) {
+			end = len(logs)
+		}
+		chunk := logs[start:end]
+
+		args := make([]interface{}, 0, len(chunk)*len(transactionLogColumns))
+		for _, l := range chunk {
+			args = append(args, transactionLogValues(l)...)
+		}
+
+		query := fmt.Sprintf(
+			"INSERT INTO transaction_logs (%s) VALUES %s ON CONFLICT (transaction_hash, log_index) DO UPDATE SET %s",
+			strings.Join(transactionLogColumns, ", "),
+			buildBulkInsert(len(chunk), len(transactionLogColumns)),
+			buildUpdateSet(transactionLogColumns),
+		)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert %d transaction logs: %w", len(chunk), err)
+		}
+	}
+	return nil
+}
+
+// DeleteByBlockHashes 删除指定区块内交易的日志（含解码结果），返回删除的日志数
+// 日志表不含区块哈希，通过交易表关联，须在删除交易之前调用
+func (r *TransactionLogRepository) DeleteByBlockHashes(ctx context.Context, blockHashes []string) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM transaction_logs
+		WHERE transaction_hash IN (SELECT hash FROM transactions WHERE block_hash = ANY($1))`,
+		pq.Array(blockHashes),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete transaction logs of %d blocks: %w", len(blockHashes), err)
+	}
+	return res.RowsAffected()
+}
+
+// transactionLogValues 按 transactionLogColumns 顺序返回日志字段值
+func transactionLogValues(l *models.TransactionLog) []interface{} {
+	return []interface{}{
+		l.TransactionHash, l.LogIndex, l.Address, l.Topics, l.Data, l.BlockNumber, l.Removed,
+		l.EventName, l.Decoded,
+	}
+}
@@ -0,0 +1,272 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// testTransaction 指定区块内的测试交易
+func testTransaction(n int, blockNumber uint64, status models.TransactionStatus) *models.Transaction {
+	to := "0x0000000000000000000000000000000000000002"
+	return &models.Transaction{
+		Hash:        fmt.Sprintf("0x%064x", n),
+		BlockNumber: blockNumber,
+		BlockHash:   testBlock(blockNumber, "a", 0).Hash,
+		Index:       uint32(n),
+		From:        "0x0000000000000000000000000000000000000001",
+		To:          &to,
+		Value:       "1000000000000000000",
+		Input:       "0x",
+		Gas:         21_000,
+		Type:        models.TxTypeDynamicFee,
+		Status:      status,
+		Nonce:       uint64(n),
+		Timestamp:   time.Unix(1_700_000_000+int64(blockNumber)*12, 0).UTC(),
+	}
+}
+
+// testLog 指定交易的测试日志
+func testLog(txHash string, index uint32, blockNumber uint64, eventName string) *models.TransactionLog {
+	return &models.TransactionLog{
+		TransactionHash: txHash,
+		LogIndex:        index,
+		Address:         "0x0000000000000000000000000000000000000003",
+		Topics:          fmt.Sprintf(`["0x%064x"]`, index),
+		Data:            "0x",
+		BlockNumber:     blockNumber,
+		EventName:       eventName,
+	}
+}
+
+func TestTransactionRepositoryCRUD(t *testing.T) {
+	truncate(t, "transactions")
+	ctx := context.Background()
+	repo := NewTransactionRepository(testDB)
+
+	tx := testTransaction(1, 100, models.TxStatusPending)
+	tx.To = nil
+	if err := repo.Create(ctx, tx); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, testTransaction(1, 100, models.TxStatusPending)); err == nil {
+		t.Fatal("expected duplicate transaction hash to be rejected")
+	}
+
+	got, err := repo.GetByHash(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("GetByHash: %v", err)
+	}
+	if got.ID != tx.ID || got.To != nil || got.GasUsed != nil || got.Value != tx.Value || got.Status != models.TxStatusPending {
+		t.Fatalf("unexpected transaction: %+v", got)
+	}
+
+	gasUsed := uint64(21_000)
+	tx.Status = models.TxStatusSuccess
+	tx.GasUsed = &gasUsed
+	if err := repo.Update(ctx, tx); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = repo.GetByID(ctx, tx.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.TxStatusSuccess || got.GasUsed == nil || *got.GasUsed != gasUsed {
+		t.Fatalf("expected updated status and gas used: %+v", got)
+	}
+
+	if err := repo.Delete(ctx, tx.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByHash(ctx, tx.Hash); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByHash: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTransactionRepositoryList(t *testing.T) {
+	truncate(t, "transactions")
+	ctx := context.Background()
+	repo := NewTransactionRepository(testDB)
+
+	txs := []*models.Transaction{
+		testTransaction(1, 10, models.TxStatusSuccess),
+		testTransaction(2, 11, models.TxStatusFailed),
+		testTransaction(3, 12, models.TxStatusSuccess),
+		testTransaction(4, 13, models.TxStatusSuccess),
+	}
+	other := "0x0000000000000000000000000000000000000009"
+	txs[3].From = other
+	if err := repo.BulkUpsert(ctx, txs); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+
+	minBlock := uint64(11)
+	list, total, err := repo.List(ctx, &models.TransactionQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10, OrderBy: "block_number", Order: "asc"},
+		MinBlockNumber:   &minBlock,
+		FromAddress:      txs[0].From,
+		TxStatus:         models.TxStatusSuccess,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].Hash != txs[2].Hash {
+		t.Fatalf("expected only transaction 3, got total %d and %+v", total, list)
+	}
+
+	// 第二页
+	list, total, err = repo.List(ctx, &models.TransactionQueryParams{
+		PaginationParams: models.PaginationParams{Page: 2, PageSize: 3, OrderBy: "block_number", Order: "asc"},
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 4 || len(list) != 1 || list[0].From != other {
+		t.Fatalf("expected the last transaction on page 2, got total %d and %+v", total, list)
+	}
+}
+
+func TestTransactionRepositoryBulkInsertConflicts(t *testing.T) {
+	truncate(t, "transactions")
+	ctx := context.Background()
+	repo := NewTransactionRepository(testDB)
+
+	pending := testTransaction(1, 0, models.TxStatusPending)
+	if err := repo.BulkInsertPending(ctx, []*models.Transaction{pending}); err != nil {
+		t.Fatalf("BulkInsertPending: %v", err)
+	}
+
+	// 重复的待处理交易保持不变
+	again := testTransaction(1, 0, models.TxStatusPending)
+	again.Value = "2"
+	if err := repo.BulkInsertPending(ctx, []*models.Transaction{again}); err != nil {
+		t.Fatalf("BulkInsertPending: %v", err)
+	}
+	got, err := repo.GetByHash(ctx, pending.Hash)
+	if err != nil {
+		t.Fatalf("GetByHash: %v", err)
+	}
+	if got.Value != pending.Value {
+		t.Fatalf("expected pending transaction to be kept, got value %s", got.Value)
+	}
+
+	// 打包后的交易覆盖待处理记录
+	mined := testTransaction(1, 100, models.TxStatusSuccess)
+	if err := repo.BulkUpsert(ctx, []*models.Transaction{mined, testTransaction(2, 100, models.TxStatusSuccess)}); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+	got, err = repo.GetByHash(ctx, pending.Hash)
+	if err != nil {
+		t.Fatalf("GetByHash: %v", err)
+	}
+	if got.ID != pending.ID || got.BlockNumber != 100 || got.Status != models.TxStatusSuccess {
+		t.Fatalf("expected pending transaction to be replaced by the mined one, got %+v", got)
+	}
+
+	deleted, err := repo.DeleteByBlockHashes(ctx, []string{mined.BlockHash})
+	if err != nil || deleted != 2 {
+		t.Fatalf("DeleteByBlockHashes: expected 2 deleted, got %d (err %v)", deleted, err)
+	}
+}
+
+func TestTransactionLogRepositoryCRUD(t *testing.T) {
+	truncate(t, "transaction_logs")
+	ctx := context.Background()
+	repo := NewTransactionLogRepository(testDB)
+
+	log := testLog(testTransaction(1, 100, models.TxStatusSuccess).Hash, 0, 100, "")
+	if err := repo.Create(ctx, log); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, log.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.TransactionHash != log.TransactionHash || got.Topics != log.Topics || got.EventName != "" || got.Removed {
+		t.Fatalf("unexpected log: %+v", got)
+	}
+
+	log.EventName = "Transfer"
+	log.Decoded = `{"value":"1"}`
+	if err := repo.Update(ctx, log); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	logs, err := repo.ListByTransactionHash(ctx, log.TransactionHash)
+	if err != nil {
+		t.Fatalf("ListByTransactionHash: %v", err)
+	}
+	if len(logs) != 1 || logs[0].EventName != "Transfer" || logs[0].Decoded != log.Decoded {
+		t.Fatalf("expected the decoded log, got %+v", logs)
+	}
+
+	if err := repo.Delete(ctx, log.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, log.ID); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestTransactionLogRepositoryListAndBulkUpsert(t *testing.T) {
+	truncate(t, "transaction_logs", "transactions")
+	ctx := context.Background()
+	repo := NewTransactionLogRepository(testDB)
+
+	first := testTransaction(1, 100, models.TxStatusSuccess)
+	second := testTransaction(2, 101, models.TxStatusSuccess)
+	if err := NewTransactionRepository(testDB).BulkUpsert(ctx, []*models.Transaction{first, second}); err != nil {
+		t.Fatalf("BulkUpsert transactions: %v", err)
+	}
+
+	logs := []*models.TransactionLog{
+		testLog(first.Hash, 0, 100, "Transfer"),
+		testLog(first.Hash, 1, 100, "Approval"),
+		testLog(second.Hash, 0, 101, "Transfer"),
+	}
+	if err := repo.BulkUpsert(ctx, logs); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+
+	// 同一交易哈希与日志序号再次写入时覆盖，例如重组后标记为已移除
+	removed := testLog(second.Hash, 0, 101, "Transfer")
+	removed.Removed = true
+	if err := repo.BulkUpsert(ctx, []*models.TransactionLog{removed}); err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+
+	list, total, err := repo.List(ctx, &models.TransactionLogQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10},
+		EventName:        "Transfer",
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].TransactionHash != first.Hash {
+		t.Fatalf("expected only the non-removed Transfer log, got total %d and %+v", total, list)
+	}
+
+	list, total, err = repo.List(ctx, &models.TransactionLogQueryParams{
+		PaginationParams: models.PaginationParams{Page: 1, PageSize: 10},
+		Topic0:           fmt.Sprintf("0x%064x", 1),
+		IncludeRemoved:   true,
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].EventName != "Approval" {
+		t.Fatalf("expected the Approval log by topic0, got total %d and %+v", total, list)
+	}
+
+	// 按区块哈希删除时通过交易表关联
+	deleted, err := repo.DeleteByBlockHashes(ctx, []string{first.BlockHash})
+	if err != nil || deleted != 2 {
+		t.Fatalf("DeleteByBlockHashes: expected 2 deleted, got %d (err %v)", deleted, err)
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// userSelect 用户查询列，可空文本列统一转换为空字符串
+const userSelect = `SELECT id, created_at, updated_at, username, email, password, COALESCE(full_name, '') AS full_name,
+	COALESCE(avatar, '') AS avatar, role, status, COALESCE(phone, '') AS phone,
+	COALESCE(telegram_id, '') AS telegram_id, COALESCE(preferences, '') AS preferences,
+	COALESCE(timezone, 'UTC') AS timezone, COALESCE(language, 'en') AS language, last_login_at,
+	COALESCE(last_login_ip, '') AS last_login_ip, COALESCE(failed_login_count, 0) AS failed_login_count,
+	locked_until, COALESCE(email_verified, FALSE) AS email_verified, email_verified_at,
+	COALESCE(api_key, '') AS api_key, api_key_created_at
+	FROM users`
+
+// userRow 用户查询结果，User.Password 的 json 标签为 "-" 无法被映射，需单独接收
+type userRow struct {
+	models.User
+	Password string `json:"password"`
+}
+
+// UserRepository 用户数据访问
+type UserRepository struct {
+	db Executor
+}
+
+// NewUserRepository 创建用户数据访问对象
+func NewUserRepository(db Executor) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *UserRepository) WithTx(tx Executor) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
+// GetByID 按主键查询用户
+func (r *UserRepository) GetByID(ctx context.Context, id uint64) (*models.User, error) {
+	user, err := r.getOne(ctx, " WHERE id = $1", id)
+	if err != nil {
+		return nil, wrapNotFound(err, "failed to get user %d", id)
+	}
+	return user, nil
+}
+
+// GetByLogin 按用户名或邮箱查询用户
+func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*models.User, error) {
+	user, err := r.getOne(ctx, " WHERE username = $1 OR LOWER(email) = LOWER($1)", login)
+	if err != nil {
+		return nil, wrapNotFound(err, "failed to get user %q", login)
+	}
+	return user, nil
+}
+
+// GetByAPIKey 按 API Key 查询用户
+func (r *UserRepository) GetByAPIKey(ctx context.Context, apiKey string) (*models.User, error) {
+	user, err := r.getOne(ctx, " WHERE api_key = $1", apiKey)
+	if err != nil {
+		return nil, wrapNotFound(err, "failed to get user by api key")
+	}
+	return user, nil
+}
+
+// UpdateLoginState 更新登录失败次数、锁定时间与最后登录信息
+func (r *UserRepository) UpdateLoginState(ctx context.Context, user *models.User) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET failed_login_count = $2, locked_until = $3, last_login_at = $4, last_login_ip = $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		user.ID, user.FailedLoginCount, user.LockedUntil, user.LastLoginAt, user.LastLoginIP,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update login state of user %d: %w", user.ID, err)
+	}
+	return checkAffected(res)
+}
+
+// UpdateRole 更新用户角色
+func (r *UserRepository) UpdateRole(ctx context.Context, id uint64, role models.UserRole) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, role)
+	if err != nil {
+		return fmt.Errorf("failed to update role of user %d: %w", id, err)
+	}
+	return checkAffected(res)
+}
+
+// UpdateStatus 更新用户状态
+func (r *UserRepository) UpdateStatus(ctx context.Context, id uint64, status models.UserStatus) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update status of user %d: %w", id, err)
+	}
+	return checkAffected(res)
+}
+
+// getOne 按条件查询单个用户并填充密码哈希
+func (r *UserRepository) getOne(ctx context.Context, where string, args ...interface{}) (*models.User, error) {
+	var row userRow
+	if err := getOne(ctx, r.db, &row, userSelect+where, args...); err != nil {
+		return nil, err
+	}
+	row.User.Password = row.Password
+	return &row.User, nil
+}
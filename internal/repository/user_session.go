@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// userSessionSelect 用户会话查询列，可空文本列统一转换为空字符串
+const userSessionSelect = `SELECT id, created_at, updated_at, user_id, session_id,
+	COALESCE(ip_address, '') AS ip_address, COALESCE(user_agent, '') AS user_agent, expires_at,
+	COALESCE(is_active, FALSE) AS is_active
+	FROM user_sessions`
+
+// UserSessionRepository 用户会话数据访问
+type UserSessionRepository struct {
+	db Executor
+}
+
+// NewUserSessionRepository 创建用户会话数据访问对象
+func NewUserSessionRepository(db Executor) *UserSessionRepository {
+	return &UserSessionRepository{db: db}
+}
+
+// WithTx 返回绑定到指定执行器（通常为事务）的副本
+func (r *UserSessionRepository) WithTx(tx Executor) *UserSessionRepository {
+	return &UserSessionRepository{db: tx}
+}
+
+// Create 写入会话
+func (r *UserSessionRepository) Create(ctx context.Context, session *models.UserSession) error {
+	row := r.db.QueryRowxContext(ctx,
+		`INSERT INTO user_sessions (user_id, session_id, ip_address, user_agent, expires_at, is_active, refresh_token_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`,
+		session.UserID, session.SessionID, session.IPAddress, session.UserAgent, session.ExpiresAt, session.IsActive,
+		session.RefreshTokenID,
+	)
+	if err := row.Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create session for user %d: %w", session.UserID, err)
+	}
+	return nil
+}
+
+// GetBySessionID 按会话标识查询会话
+func (r *UserSessionRepository) GetBySessionID(ctx context.Context, sessionID string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := getOne(ctx, r.db, &session, userSessionSelect+" WHERE session_id = $1", sessionID); err != nil {
+		return nil, wrapNotFound(err, "failed to get session")
+	}
+	return &session, nil
+}
+
+// RotateRefreshToken 将有效会话的刷新令牌标识由 oldID 替换为 newID 并延长过期时间
+// 会话已失效或 oldID 已被轮换（刷新令牌被重复使用）时返回 models.ErrRecordNotFound；
+// 轮换前创建的会话没有令牌标识，以空标识匹配一次
+func (r *UserSessionRepository) RotateRefreshToken(ctx context.Context, sessionID, oldID, newID string, expiresAt time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET refresh_token_id = $3, expires_at = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE session_id = $1 AND is_active = TRUE AND COALESCE(refresh_token_id, '') = $2`,
+		sessionID, oldID, newID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return checkAffected(res)
+}
+
+// Revoke 使会话失效
+func (r *UserSessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP WHERE session_id = $1`,
+		sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return checkAffected(res)
+}
+
+// RevokeAllForUser 使用户的全部会话失效，返回失效的会话数
+func (r *UserSessionRepository) RevokeAllForUser(ctx context.Context, userID uint64) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_sessions SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND is_active = TRUE`,
+		userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions of user %d: %w", userID, err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,76 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+func TestUserSessionRepositoryRotateRefreshToken(t *testing.T) {
+	truncate(t, "user_sessions", "users")
+	ctx := context.Background()
+	repo := NewUserSessionRepository(testDB)
+
+	session := &models.UserSession{
+		UserID:         insertUser(t, "alice"),
+		SessionID:      "session-1",
+		ExpiresAt:      time.Now().Add(time.Hour),
+		IsActive:       true,
+		RefreshTokenID: "first",
+	}
+	if err := repo.Create(ctx, session); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	if err := repo.RotateRefreshToken(ctx, "session-1", "first", "second", expiresAt); err != nil {
+		t.Fatalf("RotateRefreshToken: %v", err)
+	}
+	got, err := repo.GetBySessionID(ctx, "session-1")
+	if err != nil {
+		t.Fatalf("GetBySessionID: %v", err)
+	}
+	if !got.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected session to be extended to %v, got %v", expiresAt, got.ExpiresAt)
+	}
+
+	// 已轮换的令牌标识不能再次使用
+	if err := repo.RotateRefreshToken(ctx, "session-1", "first", "third", expiresAt); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("expected reused refresh token to be rejected, got %v", err)
+	}
+
+	if err := repo.Revoke(ctx, "session-1"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if err := repo.RotateRefreshToken(ctx, "session-1", "second", "third", expiresAt); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("expected revoked session to be rejected, got %v", err)
+	}
+}
+
+func TestUserSessionRepositoryRotateLegacySession(t *testing.T) {
+	truncate(t, "user_sessions", "users")
+	ctx := context.Background()
+	repo := NewUserSessionRepository(testDB)
+
+	// 轮换前创建的会话没有令牌标识，不带标识的旧刷新令牌只能使用一次
+	_, err := testDB.ExecContext(ctx,
+		`INSERT INTO user_sessions (user_id, session_id, expires_at, is_active) VALUES ($1, 'legacy', $2, TRUE)`,
+		insertUser(t, "alice"), time.Now().Add(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to insert legacy session: %v", err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := repo.RotateRefreshToken(ctx, "legacy", "", "first", expiresAt); err != nil {
+		t.Fatalf("RotateRefreshToken: %v", err)
+	}
+	if err := repo.RotateRefreshToken(ctx, "legacy", "", "second", expiresAt); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("expected the legacy refresh token to be single use, got %v", err)
+	}
+}
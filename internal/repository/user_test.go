@@ -0,0 +1,87 @@
+//go:build embeddedpg
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+func TestUserRepositoryNullableColumns(t *testing.T) {
+	truncate(t, "users")
+	ctx := context.Background()
+	repo := NewUserRepository(testDB)
+
+	id := insertUser(t, "alice")
+
+	user, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if user.Password != "hash" {
+		t.Fatalf("expected password hash to be populated, got %q", user.Password)
+	}
+	if user.FullName != "" || user.Avatar != "" || user.Phone != "" || user.TelegramID != "" ||
+		user.Preferences != "" || user.LastLoginIP != "" || user.APIKey != "" {
+		t.Fatalf("expected empty strings for NULL text columns: %+v", user)
+	}
+	if user.Timezone != "UTC" || user.Language != "en" {
+		t.Fatalf("expected default timezone UTC and language en, got %q and %q", user.Timezone, user.Language)
+	}
+	if user.FailedLoginCount != 0 || user.EmailVerified {
+		t.Fatalf("expected zero login failures and unverified email: %+v", user)
+	}
+	if user.LastLoginAt != nil || user.LockedUntil != nil || user.APIKeyCreatedAt != nil {
+		t.Fatalf("expected nil timestamps: %+v", user)
+	}
+
+	// 邮箱登录不区分大小写
+	byLogin, err := repo.GetByLogin(ctx, "ALICE@example.com")
+	if err != nil || byLogin.ID != id {
+		t.Fatalf("GetByLogin: expected user %d, got %+v (err %v)", id, byLogin, err)
+	}
+}
+
+func TestUserRepositoryUpdateRoleAndStatus(t *testing.T) {
+	truncate(t, "users")
+	ctx := context.Background()
+	repo := NewUserRepository(testDB)
+
+	id := insertUser(t, "alice")
+	if err := repo.UpdateRole(ctx, id, models.RoleAdmin); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, id, models.UserStatusSuspended); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	user, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if user.Role != models.RoleAdmin || user.Status != models.UserStatusSuspended {
+		t.Fatalf("expected admin/suspended, got %q/%q", user.Role, user.Status)
+	}
+}
+
+func TestUserRepositoryNotFound(t *testing.T) {
+	truncate(t, "users")
+	ctx := context.Background()
+	repo := NewUserRepository(testDB)
+
+	if _, err := repo.GetByID(ctx, 42); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByID: expected ErrRecordNotFound, got %v", err)
+	}
+	if _, err := repo.GetByLogin(ctx, "nobody"); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("GetByLogin: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.UpdateRole(ctx, 42, models.RoleAdmin); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("UpdateRole: expected ErrRecordNotFound, got %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, 42, models.UserStatusSuspended); !errors.Is(err, models.ErrRecordNotFound) {
+		t.Fatalf("UpdateStatus: expected ErrRecordNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// adminRoutes 注册用户管理接口
+func (s *Server) adminRoutes() {
+	s.mux.Handle("PUT /api/v1/admin/users/{id}/role", s.authenticated(s.handleUpdateUserRole, models.PermissionUserManage))
+	s.mux.Handle("PUT /api/v1/admin/users/{id}/status", s.authenticated(s.handleUpdateUserStatus, models.PermissionUserManage))
+}
+
+// handleUpdateUserRole 修改用户角色，新角色对后续请求立即生效
+func (s *Server) handleUpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.loadManagedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.users.UpdateRole(r.Context(), target.ID, req.Role); err != nil {
+		s.internalError(w, err)
+		return
+	}
+	s.auditAdminAction(r, target, "role", string(target.Role), string(req.Role))
+	target.Role = req.Role
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(target, "user role updated"))
+}
+
+// handleUpdateUserStatus 修改用户状态，非激活状态会注销该用户的全部会话
+func (s *Server) handleUpdateUserStatus(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.loadManagedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserStatusRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.users.UpdateStatus(r.Context(), target.ID, req.Status); err != nil {
+		s.internalError(w, err)
+		return
+	}
+	if !req.Status.CanLogin() {
+		if _, err := s.auth.RevokeUserSessions(r.Context(), target.ID); err != nil {
+			s.internalError(w, err)
+			return
+		}
+	}
+	s.auditAdminAction(r, target, "status", string(target.Status), string(req.Status))
+	target.Status = req.Status
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(target, "user status updated"))
+}
+
+// loadManagedUser 读取路径中的用户，管理员不能修改自己的角色或状态以免失去管理权限
+func (s *Server) loadManagedUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	admin, _ := middleware.UserFromContext(r.Context())
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return nil, false
+	}
+	if id == admin.ID {
+		writeError(w, http.StatusBadRequest, "cannot change your own role or status")
+		return nil, false
+	}
+
+	user, err := s.users.GetByID(r.Context(), id)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "user not found")
+		return nil, false
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return nil, false
+	}
+
+	// 响应中不返回凭证
+	user.Password = ""
+	user.APIKey = ""
+	return user, true
+}
+
+// auditAdminAction 记录管理操作
+func (s *Server) auditAdminAction(r *http.Request, target *models.User, field, from, to string) {
+	admin, _ := middleware.UserFromContext(r.Context())
+	s.logger.WithFields(logrus.Fields{
+		"admin_id": admin.ID,
+		"user_id":  target.ID,
+		"field":    field,
+		"from":     from,
+		"to":       to,
+	}).Info("User updated by admin")
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// alertRuleRoutes 注册告警规则接口
+func (s *Server) alertRuleRoutes() {
+	read := []string{models.PermissionRead}
+	write := []string{models.PermissionAlertCreate, models.PermissionAlertManage}
+
+	s.mux.Handle("GET /api/v1/alert-rules", s.authenticated(s.handleListAlertRules, read...))
+	s.mux.Handle("POST /api/v1/alert-rules", s.authenticated(s.handleCreateAlertRule, write...))
+	s.mux.Handle("GET /api/v1/alert-rules/{id}", s.authenticated(s.handleGetAlertRule, read...))
+	s.mux.Handle("PUT /api/v1/alert-rules/{id}", s.authenticated(s.handleUpdateAlertRule, write...))
+	s.mux.Handle("POST /api/v1/alert-rules/{id}/pause", s.authenticated(s.handleSetAlertRuleStatus(models.AlertStatusPaused), write...))
+	s.mux.Handle("POST /api/v1/alert-rules/{id}/resume", s.authenticated(s.handleSetAlertRuleStatus(models.AlertStatusActive), write...))
+	s.mux.Handle("DELETE /api/v1/alert-rules/{id}", s.authenticated(s.handleDeleteAlertRule, write...))
+}
+
+// handleListAlertRules 分页查询告警规则，管理员与操作员可通过 user_id 查看其他用户
+func (s *Server) handleListAlertRules(w http.ResponseWriter, r *http.Request) {
+	params, err := parseAlertRuleQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if params.UserID, err = scopedUserID(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rules, total, err := s.alertRules.List(r.Context(), params)
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(
+		models.NewPaginationResult(rules, total, &params.PaginationParams),
+	))
+}
+
+// handleGetAlertRule 查询单个告警规则
+func (s *Server) handleGetAlertRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.loadOwnedAlertRule(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(rule))
+}
+
+// handleCreateAlertRule 创建告警规则
+func (s *Server) handleCreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.UserFromContext(r.Context())
+
+	var req models.CreateAlertRuleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := req.ToAlertRule(user.ID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.alertRules.Create(r.Context(), rule); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, models.NewSuccessResponse(rule, "alert rule created"))
+}
+
+// handleUpdateAlertRule 更新告警规则
+func (s *Server) handleUpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.loadOwnedAlertRule(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAlertRuleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := models.ValidateStruct(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// 删除需通过 DELETE 接口
+	if req.Status != nil && *req.Status == models.AlertStatusDeleted {
+		writeError(w, http.StatusBadRequest, "use DELETE to remove an alert rule")
+		return
+	}
+
+	if err := req.ApplyToAlertRule(rule); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.alertRules.Update(r.Context(), rule); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(rule, "alert rule updated"))
+}
+
+// handleSetAlertRuleStatus 返回切换规则状态（暂停/恢复）的处理函数
+func (s *Server) handleSetAlertRuleStatus(status models.AlertStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := s.loadOwnedAlertRule(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.alertRules.UpdateStatus(r.Context(), rule.ID, status); err != nil {
+			s.internalError(w, err)
+			return
+		}
+		rule.Status = status
+
+		writeJSON(w, http.StatusOK, models.NewSuccessResponse(rule, "alert rule "+string(status)))
+	}
+}
+
+// handleDeleteAlertRule 软删除告警规则
+func (s *Server) handleDeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.loadOwnedAlertRule(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.alertRules.UpdateStatus(r.Context(), rule.ID, models.AlertStatusDeleted); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(nil, "alert rule deleted"))
+}
+
+// loadOwnedAlertRule 读取路径中的规则并校验归属，已删除或无权访问的规则按不存在处理
+func (s *Server) loadOwnedAlertRule(w http.ResponseWriter, r *http.Request) (*models.AlertRule, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid alert rule id")
+		return nil, false
+	}
+
+	rule, err := s.alertRules.GetByID(r.Context(), id)
+	if errors.Is(err, models.ErrRecordNotFound) ||
+		(err == nil && (!canAccess(r, rule.UserID) || rule.Status == models.AlertStatusDeleted)) {
+		writeError(w, http.StatusNotFound, "alert rule not found")
+		return nil, false
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return nil, false
+	}
+	return rule, true
+}
+
+// parseAlertRuleQuery 解析告警规则列表的查询参数
+func parseAlertRuleQuery(r *http.Request) (*models.AlertRuleQueryParams, error) {
+	q := r.URL.Query()
+
+	params := &models.AlertRuleQueryParams{
+		Name:      q.Get("name"),
+		AlertType: models.AlertType(q.Get("type")),
+		Severity:  models.AlertSeverity(q.Get("severity")),
+		Status:    models.AlertStatus(q.Get("status")),
+	}
+
+	pagination, err := parsePagination(r)
+	if err != nil {
+		return nil, err
+	}
+	params.PaginationParams = *pagination
+
+	if params.AlertType != "" && !params.AlertType.IsValid() {
+		return nil, errors.New("invalid type")
+	}
+	if params.Severity != "" && !params.Severity.IsValid() {
+		return nil, errors.New("invalid severity")
+	}
+	if params.Status != "" && !params.Status.IsValid() {
+		return nil, errors.New("invalid status")
+	}
+
+	if params.StartTime, err = parseTimeParam(q.Get("start_time")); err != nil {
+		return nil, errors.New("invalid start_time, expected RFC3339")
+	}
+	if params.EndTime, err = parseTimeParam(q.Get("end_time")); err != nil {
+		return nil, errors.New("invalid end_time, expected RFC3339")
+	}
+
+	return params, nil
+}
+
+// parsePagination 解析分页参数
+func parsePagination(r *http.Request) (*models.PaginationParams, error) {
+	q := r.URL.Query()
+
+	params := &models.PaginationParams{
+		Page:     1,
+		PageSize: 20,
+		OrderBy:  q.Get("order_by"),
+		Order:    q.Get("order"),
+	}
+
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, errors.New("invalid page")
+		}
+		params.Page = page
+	}
+	if v := q.Get("page_size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 || size > 100 {
+			return nil, errors.New("invalid page_size")
+		}
+		params.PageSize = size
+	}
+	if params.Order != "" && params.Order != "asc" && params.Order != "desc" {
+		return nil, errors.New("invalid order, expected asc or desc")
+	}
+
+	return params, nil
+}
+
+// parseTimeParam 解析 RFC3339 时间参数，空字符串返回 nil
+func parseTimeParam(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// decodeJSON 解析请求体，拒绝未知字段
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return errors.New("invalid request body: " + err.Error())
+	}
+	return nil
+}
+
+// canAccess 检查当前用户能否访问指定用户的资源，管理员与操作员可访问所有用户
+func canAccess(r *http.Request, ownerID uint64) bool {
+	user, ok := middleware.UserFromContext(r.Context())
+	return ok && (user.ID == ownerID || user.CanViewAllUsers())
+}
+
+// scopedUserID 返回列表查询的用户范围：普通用户固定为自己，
+// 管理员与操作员默认查看所有用户，可通过 user_id 参数指定
+func scopedUserID(r *http.Request) (*uint64, error) {
+	user, _ := middleware.UserFromContext(r.Context())
+	if !user.CanViewAllUsers() {
+		return &user.ID, nil
+	}
+
+	v := r.URL.Query().Get("user_id")
+	if v == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid user_id")
+	}
+	return &id, nil
+}
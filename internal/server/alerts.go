@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/notification"
+)
+
+// alertRoutes 注册告警记录接口
+func (s *Server) alertRoutes() {
+	read := []string{models.PermissionRead}
+	write := []string{models.PermissionAlertCreate, models.PermissionAlertManage}
+
+	s.mux.Handle("GET /api/v1/alerts", s.authenticated(s.handleListAlerts, read...))
+	s.mux.Handle("GET /api/v1/alerts/{id}", s.authenticated(s.handleGetAlert, read...))
+	s.mux.Handle("GET /api/v1/alerts/{id}/escalations", s.authenticated(s.handleListAlertEscalations, read...))
+	s.mux.Handle("POST /api/v1/alerts/{id}/acknowledge", s.authenticated(s.handleAcknowledgeAlert, write...))
+	s.mux.Handle("POST /api/v1/alerts/{id}/resolve", s.authenticated(s.handleResolveAlert, write...))
+	s.mux.Handle("POST /api/v1/alerts/{id}/snooze", s.authenticated(s.handleSnoozeAlert, write...))
+}
+
+// handleListAlerts 分页查询告警记录，普通用户只能看到自己规则产生的告警
+func (s *Server) handleListAlerts(w http.ResponseWriter, r *http.Request) {
+	params, err := parseAlertQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if params.UserID, err = scopedUserID(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	alerts, total, err := s.alerts.List(r.Context(), params)
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(
+		models.NewPaginationResult(alerts, total, &params.PaginationParams),
+	))
+}
+
+// handleGetAlert 查询单个告警记录
+func (s *Server) handleGetAlert(w http.ResponseWriter, r *http.Request) {
+	alert, ok := s.loadOwnedAlert(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(alert))
+}
+
+// handleListAlertEscalations 查询告警的升级记录
+func (s *Server) handleListAlertEscalations(w http.ResponseWriter, r *http.Request) {
+	alert, ok := s.loadOwnedAlert(w, r)
+	if !ok {
+		return
+	}
+
+	escalations, err := s.escalations.ListByAlert(r.Context(), alert.ID)
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(escalations))
+}
+
+// handleAcknowledgeAlert 确认告警
+func (s *Server) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	alert, ok := s.loadOwnedAlert(w, r)
+	if !ok {
+		return
+	}
+	if alert.IsResolved() {
+		writeError(w, http.StatusConflict, "alert already resolved")
+		return
+	}
+	if alert.IsAcknowledged() {
+		writeError(w, http.StatusConflict, "alert already acknowledged")
+		return
+	}
+
+	user, _ := middleware.UserFromContext(r.Context())
+	alert.Acknowledge(user.ID)
+	if err := s.alerts.UpdateLifecycle(r.Context(), alert); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(alert, "alert acknowledged"))
+}
+
+// handleResolveAlert 手动解决告警，已发送过通知的告警向原渠道发送解决通知
+func (s *Server) handleResolveAlert(w http.ResponseWriter, r *http.Request) {
+	alert, ok := s.loadOwnedAlert(w, r)
+	if !ok {
+		return
+	}
+	if alert.IsResolved() {
+		writeError(w, http.StatusConflict, "alert already resolved")
+		return
+	}
+
+	user, _ := middleware.UserFromContext(r.Context())
+	alert.Resolve(&user.ID, time.Now())
+	if err := s.alerts.UpdateLifecycle(r.Context(), alert); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	if alert.NotificationSent {
+		s.notifyResolved(alert)
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(alert, "alert resolved"))
+}
+
+// handleSnoozeAlert 静默告警，静默期内同一规则与分组的新告警不发送通知
+func (s *Server) handleSnoozeAlert(w http.ResponseWriter, r *http.Request) {
+	alert, ok := s.loadOwnedAlert(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SnoozeAlertRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := models.ValidateStruct(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if alert.IsResolved() {
+		writeError(w, http.StatusConflict, "alert already resolved")
+		return
+	}
+
+	alert.Snooze(time.Now().Add(time.Duration(req.Duration) * time.Second))
+	if err := s.alerts.UpdateLifecycle(r.Context(), alert); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(alert, "alert snoozed"))
+}
+
+// notifyResolved 在后台发送解决通知，服务器关闭时等待发送完成
+func (s *Server) notifyResolved(alert *models.Alert) {
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), notification.SendTimeout(s.config))
+		defer cancel()
+
+		if err := s.dispatcher.DispatchResolved(ctx, &alert.Rule, alert); err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"alert_id": alert.ID,
+				"rule_id":  alert.RuleID,
+			}).Error("Failed to deliver alert resolution notifications")
+		}
+	}()
+}
+
+// loadOwnedAlert 读取路径中的告警及其规则并校验归属，无权访问的告警按不存在处理
+func (s *Server) loadOwnedAlert(w http.ResponseWriter, r *http.Request) (*models.Alert, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid alert id")
+		return nil, false
+	}
+
+	alert, err := s.alerts.GetByID(r.Context(), id)
+	if err == nil {
+		var rule *models.AlertRule
+		if rule, err = s.alertRules.GetByID(r.Context(), alert.RuleID); err == nil {
+			alert.Rule = *rule
+		}
+	}
+	if errors.Is(err, models.ErrRecordNotFound) || (err == nil && !canAccess(r, alert.Rule.UserID)) {
+		writeError(w, http.StatusNotFound, "alert not found")
+		return nil, false
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return nil, false
+	}
+	return alert, true
+}
+
+// parseAlertQuery 解析告警记录列表的查询参数
+func parseAlertQuery(r *http.Request) (*models.AlertQueryParams, error) {
+	q := r.URL.Query()
+
+	params := &models.AlertQueryParams{
+		AlertType: models.AlertType(q.Get("type")),
+		Severity:  models.AlertSeverity(q.Get("severity")),
+		Status:    models.NotificationStatus(q.Get("status")),
+	}
+
+	pagination, err := parsePagination(r)
+	if err != nil {
+		return nil, err
+	}
+	params.PaginationParams = *pagination
+
+	if params.AlertType != "" && !params.AlertType.IsValid() {
+		return nil, errors.New("invalid type")
+	}
+	if params.Severity != "" && !params.Severity.IsValid() {
+		return nil, errors.New("invalid severity")
+	}
+	if params.Status != "" && !params.Status.IsValid() {
+		return nil, errors.New("invalid status")
+	}
+
+	if v := q.Get("rule_id"); v != "" {
+		ruleID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid rule_id")
+		}
+		params.RuleID = &ruleID
+	}
+	if params.Acknowledged, err = parseBoolParam(q.Get("acknowledged")); err != nil {
+		return nil, errors.New("invalid acknowledged")
+	}
+	if params.Resolved, err = parseBoolParam(q.Get("resolved")); err != nil {
+		return nil, errors.New("invalid resolved")
+	}
+
+	if params.StartTime, err = parseTimeParam(q.Get("start_time")); err != nil {
+		return nil, errors.New("invalid start_time, expected RFC3339")
+	}
+	if params.EndTime, err = parseTimeParam(q.Get("end_time")); err != nil {
+		return nil, errors.New("invalid end_time, expected RFC3339")
+	}
+
+	return params, nil
+}
+
+// parseBoolParam 解析布尔参数，空字符串返回 nil
+func parseBoolParam(v string) (*bool, error) {
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
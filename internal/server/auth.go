@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/auth"
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// authRoutes 注册认证接口
+func (s *Server) authRoutes() {
+	s.mux.HandleFunc("POST /api/v1/auth/login", s.handleLogin)
+	s.mux.HandleFunc("POST /api/v1/auth/refresh", s.handleRefresh)
+	s.mux.Handle("POST /api/v1/auth/logout", s.authenticated(s.handleLogout))
+}
+
+// handleLogin 用户名/邮箱与密码登录
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pair, err := s.auth.Login(r.Context(), req.Login, req.Password, s.clientIP.ClientIP(r), r.UserAgent())
+	if err != nil {
+		s.authError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(pair, "login successful"))
+}
+
+// handleRefresh 使用刷新令牌换取新令牌
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pair, err := s.auth.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		s.authError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(pair))
+}
+
+// handleLogout 注销当前会话
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := middleware.SessionIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusBadRequest, "logout requires a bearer token session")
+		return
+	}
+
+	if err := s.auth.Logout(r.Context(), sessionID); err != nil {
+		s.authError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(nil, "logged out"))
+}
+
+// authError 将认证错误映射为 HTTP 状态码
+func (s *Server) authError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrAccountLocked):
+		writeError(w, http.StatusLocked, err.Error())
+	case errors.Is(err, auth.ErrAccountInactive):
+		writeError(w, http.StatusForbidden, err.Error())
+	case auth.IsAuthError(err):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	default:
+		s.internalError(w, err)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// contractRoutes 注册合约 ABI 接口
+// ABI 对所有用户的日志解码生效，因此上传与删除需要告警管理权限
+func (s *Server) contractRoutes() {
+	s.mux.Handle("GET /api/v1/contracts/abis", s.authenticated(s.handleListContractABIs, models.PermissionRead))
+	s.mux.Handle("POST /api/v1/contracts/abis", s.authenticated(s.handleUploadContractABI, models.PermissionAlertManage))
+	s.mux.Handle("GET /api/v1/contracts/abis/{address}", s.authenticated(s.handleGetContractABI, models.PermissionRead))
+	s.mux.Handle("DELETE /api/v1/contracts/abis/{address}", s.authenticated(s.handleDeleteContractABI, models.PermissionAlertManage))
+}
+
+// handleListContractABIs 查询全部已上传的合约 ABI
+func (s *Server) handleListContractABIs(w http.ResponseWriter, r *http.Request) {
+	contracts, err := s.contractABIs.ListAll(r.Context())
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(contracts))
+}
+
+// handleGetContractABI 查询单个合约的 ABI
+func (s *Server) handleGetContractABI(w http.ResponseWriter, r *http.Request) {
+	address, ok := contractAddress(w, r)
+	if !ok {
+		return
+	}
+
+	contract, err := s.contractABIs.GetByAddress(r.Context(), address)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "contract ABI not found")
+		return
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(contract))
+}
+
+// handleUploadContractABI 上传合约 ABI，同一地址已存在时覆盖
+// 采集进程定期重新加载 ABI，新日志在下次加载后按新 ABI 解码
+func (s *Server) handleUploadContractABI(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.UserFromContext(r.Context())
+
+	var req models.UploadContractABIRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contract := req.ToContractABI(user.ID)
+	if err := s.contractABIs.Upsert(r.Context(), contract); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(contract, "contract ABI saved"))
+}
+
+// handleDeleteContractABI 删除合约 ABI，之后该合约的日志仅按内置 ABI 解码
+func (s *Server) handleDeleteContractABI(w http.ResponseWriter, r *http.Request) {
+	address, ok := contractAddress(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.contractABIs.DeleteByAddress(r.Context(), address)
+	if errors.Is(err, models.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "contract ABI not found")
+		return
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(nil, "contract ABI deleted"))
+}
+
+// contractAddress 读取路径中的合约地址并转换为校验和格式
+func contractAddress(w http.ResponseWriter, r *http.Request) (string, bool) {
+	address := r.PathValue("address")
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, "invalid contract address")
+		return "", false
+	}
+	return common.HexToAddress(address).Hex(), true
+}
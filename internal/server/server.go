@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/auth"
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/notification"
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// Server HTTP API 服务器
+type Server struct {
+	// 应用配置
+	config *config.Config
+	// 日志记录器
+	logger *logger.Logger
+	// PostgreSQL 管理器
+	postgres *database.PostgresManager
+	// Redis 管理器
+	redis *database.RedisManager
+	// Prometheus 指标
+	metrics *metrics.Metrics
+	// 数据库健康检查器
+	health *database.HealthChecker
+	// 认证服务
+	auth *auth.Service
+	// 告警规则仓库
+	alertRules *repository.AlertRuleRepository
+	// 告警记录仓库
+	alerts *repository.AlertRepository
+	// 告警升级记录仓库
+	escalations *repository.AlertEscalationRepository
+	// 通知分发器，用于发送解决通知
+	dispatcher *notification.Dispatcher
+	// 后台通知任务
+	background sync.WaitGroup
+	// 订阅仓库
+	subscriptions *repository.SubscriptionRepository
+	// 合约 ABI 仓库
+	contractABIs *repository.ContractABIRepository
+	// 用户仓库
+	users *repository.UserRepository
+	// 客户端IP解析器
+	clientIP *middleware.ClientIPResolver
+	// 路由
+	mux *http.ServeMux
+	// 底层 HTTP 服务器
+	httpServer *http.Server
+	// 启动时间
+	startedAt time.Time
+	// 关闭时关闭，通知后台协程退出
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// New 创建 HTTP API 服务器
+func New(cfg *config.Config, log *logger.Logger, postgres *database.PostgresManager, redis *database.RedisManager, m *metrics.Metrics) *Server {
+	s := &Server{
+		config:        cfg,
+		logger:        log,
+		postgres:      postgres,
+		redis:         redis,
+		metrics:       m,
+		health:        database.NewHealthChecker(postgres, redis, log),
+		auth:          auth.NewService(postgres.GetDB(), cfg.Security, log),
+		alertRules:    repository.NewAlertRuleRepository(postgres.GetDB()),
+		alerts:        repository.NewAlertRepository(postgres.GetDB()),
+		escalations:   repository.NewAlertEscalationRepository(postgres.GetDB()),
+		dispatcher:    notification.NewDispatcher(cfg.Alert, nil, log, notification.NewDefaultNotifiers(cfg)...),
+		subscriptions: repository.NewSubscriptionRepository(postgres.GetDB()),
+		contractABIs:  repository.NewContractABIRepository(postgres.GetDB()),
+		users:         repository.NewUserRepository(postgres.GetDB()),
+		mux:           http.NewServeMux(),
+		startedAt:     time.Now(),
+		done:          make(chan struct{}),
+	}
+
+	trustedProxies, err := cfg.Security.TrustedProxyNetworks()
+	if err != nil {
+		log.WithError(err).Warn("Ignoring invalid trusted proxies")
+		trustedProxies = nil
+	}
+	s.clientIP = middleware.NewClientIPResolver(trustedProxies)
+
+	s.routes()
+
+	// 中间件链: CORS -> 日志 -> 指标 -> IP限流 -> 认证 -> 限流 -> 路由
+	rateLimit := middleware.NewRateLimitMiddleware(redis, cfg.RateLimit, s.clientIP, log)
+	var handler http.Handler = s.mux
+	handler = rateLimit.Middleware(handler)
+	handler = middleware.NewAuthMiddleware(s.auth, log).Middleware(handler)
+	handler = rateLimit.PreAuthMiddleware(handler)
+	handler = middleware.NewMetricsMiddleware(m).Middleware(handler)
+	handler = middleware.NewLoggingMiddleware(log).Middleware(handler)
+	handler = middleware.NewCORSMiddleware(cfg.Security.CORSAllowedOrigins).Middleware(handler)
+
+	s.httpServer = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.App.Host, cfg.App.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+
+	return s
+}
+
+// routes 注册路由
+func (s *Server) routes() {
+	metricsPath := s.config.Monitor.MetricsPath
+	if metricsPath == "" {
+		metricsPath = "/metrics"
+	}
+
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.Handle("GET "+metricsPath, s.metrics.Handler())
+
+	s.authRoutes()
+	s.alertRuleRoutes()
+	s.alertRoutes()
+	s.subscriptionRoutes()
+	s.contractRoutes()
+	s.adminRoutes()
+}
+
+// Start 启动服务器，阻塞直到服务器关闭
+func (s *Server) Start() error {
+	s.health.Start()
+
+	s.metrics.ApplicationInfo.WithLabelValues(s.config.App.Version, s.config.App.Environment).Set(1)
+	go s.trackUptime()
+
+	s.logger.WithField("addr", s.httpServer.Addr).Info("HTTP server listening")
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("http server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown 优雅关闭服务器
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("Shutting down HTTP server")
+	s.health.Stop()
+	s.doneOnce.Do(func() { close(s.done) })
+	err := s.httpServer.Shutdown(ctx)
+	s.background.Wait()
+	return err
+}
+
+// trackUptime 定期更新运行时长指标，直到服务器关闭
+func (s *Server) trackUptime() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.metrics.ApplicationUptime.Set(time.Since(s.startedAt).Seconds())
+		}
+	}
+}
+
+// handleHealth 健康检查接口
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := s.health.GetHealth()
+
+	if !health.Overall.Healthy {
+		resp := models.NewErrorResponse("service unhealthy", http.StatusServiceUnavailable)
+		resp.Data = health
+		writeJSON(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(health))
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError 写入错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, models.NewErrorResponse(message, status))
+}
+
+// internalError 记录内部错误并返回 500，不向客户端暴露细节
+func (s *Server) internalError(w http.ResponseWriter, err error) {
+	s.logger.WithError(err).Error("Request failed")
+	writeError(w, http.StatusInternalServerError, "internal server error")
+}
+
+// authenticated 包装需要登录的接口，用户由认证中间件写入上下文
+// 指定权限时用户须拥有其中任一权限
+func (s *Server) authenticated(h http.HandlerFunc, permissions ...string) http.Handler {
+	var handler http.Handler = h
+	if len(permissions) > 0 {
+		handler = middleware.RequirePermission(permissions...)(handler)
+	}
+	return middleware.RequireUser(handler)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/middleware"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+)
+
+// subscriptionRoutes 注册订阅接口
+func (s *Server) subscriptionRoutes() {
+	s.mux.Handle("GET /api/v1/subscriptions", s.authenticated(s.handleListSubscriptions, models.PermissionRead))
+	s.mux.Handle("POST /api/v1/subscriptions", s.authenticated(s.handleCreateSubscription, models.PermissionSubscriptionManage))
+	s.mux.Handle("GET /api/v1/subscriptions/{id}", s.authenticated(s.handleGetSubscription, models.PermissionRead))
+	s.mux.Handle("DELETE /api/v1/subscriptions/{id}", s.authenticated(s.handleDeleteSubscription, models.PermissionSubscriptionManage))
+}
+
+// handleListSubscriptions 分页查询订阅，管理员与操作员可通过 user_id 查看其他用户
+func (s *Server) handleListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	pagination, err := parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	params := &models.SubscriptionQueryParams{
+		PaginationParams: *pagination,
+		Name:             q.Get("name"),
+		Type:             models.SubscriptionType(q.Get("type")),
+		Status:           models.SubscriptionStatus(q.Get("status")),
+	}
+	if params.Type != "" && !params.Type.IsValid() {
+		writeError(w, http.StatusBadRequest, "invalid type")
+		return
+	}
+	if params.Status != "" && !params.Status.IsValid() {
+		writeError(w, http.StatusBadRequest, "invalid status")
+		return
+	}
+	if params.UserID, err = scopedUserID(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subs, total, err := s.subscriptions.List(r.Context(), params)
+	if err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(
+		models.NewPaginationResult(subs, total, &params.PaginationParams),
+	))
+}
+
+// handleGetSubscription 查询单个订阅
+func (s *Server) handleGetSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.loadOwnedSubscription(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(sub))
+}
+
+// handleCreateSubscription 创建订阅
+func (s *Server) handleCreateSubscription(w http.ResponseWriter, r *http.Request) {
+	user, _ := middleware.UserFromContext(r.Context())
+
+	var req models.CreateSubscriptionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sub, err := req.ToSubscription(user.ID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := sub.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.subscriptions.Create(r.Context(), sub); err != nil {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, models.NewSuccessResponse(sub, "subscription created"))
+}
+
+// handleDeleteSubscription 删除订阅
+func (s *Server) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.loadOwnedSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.subscriptions.Delete(r.Context(), sub.ID); err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		s.internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.NewSuccessResponse(nil, "subscription deleted"))
+}
+
+// loadOwnedSubscription 读取路径中的订阅并校验归属，无权访问的订阅按不存在处理
+func (s *Server) loadOwnedSubscription(w http.ResponseWriter, r *http.Request) (*models.Subscription, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid subscription id")
+		return nil, false
+	}
+
+	sub, err := s.subscriptions.GetByID(r.Context(), id)
+	if errors.Is(err, models.ErrRecordNotFound) || (err == nil && !canAccess(r, sub.UserID)) {
+		writeError(w, http.StatusNotFound, "subscription not found")
+		return nil, false
+	}
+	if err != nil {
+		s.internalError(w, err)
+		return nil, false
+	}
+	return sub, true
+}
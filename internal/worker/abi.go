@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+)
+
+// LoadContractABIs 从数据库加载全部合约 ABI 并替换注册表内容
+// 查询失败时保留注册表原有内容；无法解析的 ABI 被跳过并在返回的错误中列出
+func LoadContractABIs(ctx context.Context, db repository.Executor, registry *ethereum.ABIRegistry) error {
+	contracts, err := repository.NewContractABIRepository(db).ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	definitions := make(map[common.Address]string, len(contracts))
+	for _, contract := range contracts {
+		definitions[common.HexToAddress(contract.Address)] = contract.ABI
+	}
+	return registry.Replace(definitions)
+}
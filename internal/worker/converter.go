@@ -0,0 +1,200 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+)
+
+// BlockData 一个区块及其交易、日志的数据模型
+type BlockData struct {
+	Block        *models.Block
+	Transactions []*models.Transaction
+	Logs         []*models.TransactionLog
+}
+
+// BuildBlockData 转换区块、交易与日志，receipts 需与区块交易一一对应
+// abis 用于解码日志，为空时不解码
+func BuildBlockData(block *types.Block, receipts []*types.Receipt, signer types.Signer, abis *ethereum.ABIRegistry) (*BlockData, error) {
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return nil, fmt.Errorf("receipt count mismatch for block %d: got %d, want %d", block.NumberU64(), len(receipts), len(txs))
+	}
+
+	data := &BlockData{
+		Block:        ConvertBlock(block),
+		Transactions: make([]*models.Transaction, 0, len(txs)),
+	}
+	for i, tx := range txs {
+		txModel, err := ConvertTransaction(tx, signer, block, i, receipts[i])
+		if err != nil {
+			return nil, err
+		}
+		data.Transactions = append(data.Transactions, txModel)
+		data.Logs = append(data.Logs, ConvertLogs(receipts[i], abis)...)
+	}
+	return data, nil
+}
+
+// ConvertBlock 将链上区块转换为区块数据模型
+func ConvertBlock(block *types.Block) *models.Block {
+	header := block.Header()
+
+	b := &models.Block{
+		Number:           header.Number.Uint64(),
+		Hash:             block.Hash().Hex(),
+		ParentHash:       header.ParentHash.Hex(),
+		Timestamp:        time.Unix(int64(header.Time), 0).UTC(),
+		Miner:            header.Coinbase.Hex(),
+		Difficulty:       "0",
+		Size:             block.Size(),
+		GasLimit:         header.GasLimit,
+		GasUsed:          header.GasUsed,
+		TransactionCount: uint32(len(block.Transactions())),
+		StateRoot:        header.Root.Hex(),
+		ReceiptsRoot:     header.ReceiptHash.Hex(),
+		TransactionsRoot: header.TxHash.Hex(),
+		ExtraData:        hexutil.Encode(header.Extra),
+		MixHash:          header.MixDigest.Hex(),
+		Nonce:            hexutil.Encode(header.Nonce[:]),
+		LogsBloom:        hexutil.Encode(header.Bloom.Bytes()),
+	}
+
+	if header.Difficulty != nil {
+		b.Difficulty = header.Difficulty.String()
+	}
+	if header.BaseFee != nil {
+		baseFee := header.BaseFee.String()
+		b.BaseFeePerGas = &baseFee
+	}
+
+	return b
+}
+
+// ConvertTransaction 将链上交易转换为交易数据模型
+// block 与 receipt 为空时视为待处理交易
+func ConvertTransaction(tx *types.Transaction, signer types.Signer, block *types.Block, index int, receipt *types.Receipt) (*models.Transaction, error) {
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender of %s: %w", tx.Hash().Hex(), err)
+	}
+
+	v, r, s := tx.RawSignatureValues()
+
+	t := &models.Transaction{
+		Hash:      tx.Hash().Hex(),
+		Index:     uint32(index),
+		From:      from.Hex(),
+		Value:     tx.Value().String(),
+		Input:     hexutil.Encode(tx.Data()),
+		Gas:       tx.Gas(),
+		Type:      convertTxType(tx.Type()),
+		Status:    models.TxStatusPending,
+		Nonce:     tx.Nonce(),
+		V:         hexutil.EncodeBig(v),
+		R:         fmt.Sprintf("0x%064x", r),
+		S:         fmt.Sprintf("0x%064x", s),
+		Timestamp: time.Now().UTC(),
+	}
+
+	if to := tx.To(); to != nil {
+		toAddr := to.Hex()
+		t.To = &toAddr
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		gasPrice := tx.GasPrice().String()
+		t.GasPrice = &gasPrice
+	default:
+		maxFee := tx.GasFeeCap().String()
+		maxPriority := tx.GasTipCap().String()
+		t.MaxFeePerGas = &maxFee
+		t.MaxPriorityFeePerGas = &maxPriority
+	}
+
+	if block != nil {
+		t.BlockNumber = block.NumberU64()
+		t.BlockHash = block.Hash().Hex()
+		t.Timestamp = time.Unix(int64(block.Time()), 0).UTC()
+	}
+
+	if receipt != nil {
+		applyReceipt(t, receipt)
+	}
+
+	return t, nil
+}
+
+// ConvertLogs 将交易收据中的日志转换为日志数据模型，abis 不为空时附带解码结果
+func ConvertLogs(receipt *types.Receipt, abis *ethereum.ABIRegistry) []*models.TransactionLog {
+	logs := make([]*models.TransactionLog, 0, len(receipt.Logs))
+	for _, l := range receipt.Logs {
+		topics := make([]string, len(l.Topics))
+		for i, topic := range l.Topics {
+			topics[i] = topic.Hex()
+		}
+		topicsJSON, _ := json.Marshal(topics)
+
+		log := &models.TransactionLog{
+			TransactionHash: l.TxHash.Hex(),
+			LogIndex:        uint32(l.Index),
+			Address:         l.Address.Hex(),
+			Topics:          string(topicsJSON),
+			Data:            hexutil.Encode(l.Data),
+			BlockNumber:     l.BlockNumber,
+			Removed:         l.Removed,
+		}
+		if abis != nil {
+			// 解码结果只含基本类型，编码不会失败
+			_ = log.SetDecoded(abis.DecodeLog(l))
+		}
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+// applyReceipt 将交易收据中的执行结果写入交易模型
+func applyReceipt(t *models.Transaction, receipt *types.Receipt) {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		t.Status = models.TxStatusSuccess
+	} else {
+		t.Status = models.TxStatusFailed
+	}
+
+	gasUsed := receipt.GasUsed
+	cumulative := receipt.CumulativeGasUsed
+	t.GasUsed = &gasUsed
+	t.CumulativeGasUsed = &cumulative
+
+	if receipt.EffectiveGasPrice != nil {
+		effective := receipt.EffectiveGasPrice.String()
+		t.EffectiveGasPrice = &effective
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		contract := receipt.ContractAddress.Hex()
+		t.ContractAddress = &contract
+	}
+
+	t.LogsCount = uint32(len(receipt.Logs))
+	t.LogsBloom = hexutil.Encode(receipt.Bloom.Bytes())
+}
+
+// convertTxType 映射交易类型，EIP-1559 之后的新类型按动态费用交易记录
+func convertTxType(txType uint8) models.TransactionType {
+	switch txType {
+	case types.LegacyTxType:
+		return models.TxTypeLegacy
+	case types.AccessListTxType:
+		return models.TxTypeAccessList
+	default:
+		return models.TxTypeDynamicFee
+	}
+}
@@ -0,0 +1,450 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// LogHandler 合约日志处理器，区块写入数据库后接收区块内的全部日志
+// *engine.Engine 实现该接口
+type LogHandler interface {
+	HandleLogs(logs []*types.Log)
+}
+
+// BlockPersister 区块持久化处理器，将新区块及其交易写入数据库
+type BlockPersister struct {
+	// PostgreSQL 管理器
+	postgres *database.PostgresManager
+	// 以太坊客户端池
+	pool *ethereum.ClientPool
+	// 区块服务
+	blockService *ethereum.BlockService
+	// 交易签名器，用于恢复发送方地址
+	signer types.Signer
+	// 合约 ABI 注册表，用于解码日志
+	abis *ethereum.ABIRegistry
+	// 日志处理器
+	logHandlers []LogHandler
+	// 网络名称
+	network string
+	// 单次写入的交易数量
+	batchSize int
+	// 处理超时时间
+	timeout time.Duration
+	// 是否以法定数量读取区块哈希与收据
+	quorumReads bool
+	// Prometheus 指标
+	metrics *metrics.Metrics
+	// 日志记录器
+	logger *logger.Logger
+}
+
+// NewBlockPersister 创建区块持久化处理器
+func NewBlockPersister(
+	postgres *database.PostgresManager,
+	pool *ethereum.ClientPool,
+	blockService *ethereum.BlockService,
+	signer types.Signer,
+	abis *ethereum.ABIRegistry,
+	network string,
+	batchSize int,
+	timeout time.Duration,
+	m *metrics.Metrics,
+	log *logger.Logger,
+) *BlockPersister {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &BlockPersister{
+		postgres:     postgres,
+		pool:         pool,
+		blockService: blockService,
+		signer:       signer,
+		abis:         abis,
+		network:      network,
+		batchSize:    batchSize,
+		timeout:      timeout,
+		metrics:      m,
+		logger:       log,
+	}
+}
+
+// AddLogHandler 添加日志处理器
+func (p *BlockPersister) AddLogHandler(handler LogHandler) {
+	p.logHandlers = append(p.logHandlers, handler)
+}
+
+// SetQuorumReads 设置是否以法定数量读取区块哈希与收据
+// 启用后只有多数节点认可的区块及收据才会写入数据库并参与告警评估
+func (p *BlockPersister) SetQuorumReads(enabled bool) {
+	p.quorumReads = enabled
+}
+
+// GetName 返回处理器名称
+func (p *BlockPersister) GetName() string {
+	return "block_persister"
+}
+
+// HandleError 处理订阅错误
+func (p *BlockPersister) HandleError(err error) {
+	p.logger.WithError(err).Warn("Block subscription error")
+}
+
+// HandleBlock 拉取完整区块与收据并写入数据库
+func (p *BlockPersister) HandleBlock(event *ethereum.BlockEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	hash := event.Header.Hash()
+
+	block, err := p.blockService.GetBlockByHash(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block %s: %w", hash.Hex(), err)
+	}
+
+	fetch := FetchReceipts
+	if p.quorumReads {
+		fetch = FetchQuorumReceipts
+	}
+	receipts, err := fetch(ctx, p.pool, block)
+	if err != nil {
+		return fmt.Errorf("failed to fetch receipts for block %d: %w", block.NumberU64(), err)
+	}
+
+	data, err := BuildBlockData(block, receipts, p.signer, p.abis)
+	if err != nil {
+		return err
+	}
+
+	if err := p.persist(ctx, data); err != nil {
+		return err
+	}
+
+	if len(p.logHandlers) > 0 {
+		var logs []*types.Log
+		for _, receipt := range receipts {
+			logs = append(logs, receipt.Logs...)
+		}
+		for _, handler := range p.logHandlers {
+			handler.HandleLogs(logs)
+		}
+	}
+
+	p.metrics.BlockchainBlocksProcessed.WithLabelValues(p.network).Inc()
+	p.metrics.BlockchainLatestBlock.Set(float64(data.Block.Number))
+
+	p.logger.WithFields(logrus.Fields{
+		"number":       data.Block.Number,
+		"hash":         data.Block.Hash,
+		"transactions": len(data.Transactions),
+		"logs":         len(data.Logs),
+	}).Info("Block persisted")
+
+	return nil
+}
+
+// HandleReorg 删除被孤立区块及其交易与日志，新链区块随后由 HandleBlock 写入
+func (p *BlockPersister) HandleReorg(event *ethereum.ReorgEvent) error {
+	if len(event.OrphanedBlocks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	hashes := make([]string, len(event.OrphanedBlocks))
+	for i, header := range event.OrphanedBlocks {
+		hashes[i] = header.Hash().Hex()
+	}
+
+	var logCount, txCount, blockCount int64
+	err := repository.RunInTx(ctx, p.postgres, func(tx repository.Executor) error {
+		var err error
+		if logCount, err = repository.NewTransactionLogRepository(tx).DeleteByBlockHashes(ctx, hashes); err != nil {
+			return err
+		}
+		if txCount, err = repository.NewTransactionRepository(tx).DeleteByBlockHashes(ctx, hashes); err != nil {
+			return err
+		}
+		blockCount, err = repository.NewBlockRepository(tx).DeleteByHashes(ctx, hashes)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"ancestor":     event.CommonAncestor.Number.Uint64(),
+		"depth":        event.Depth,
+		"blocks":       blockCount,
+		"transactions": txCount,
+		"logs":         logCount,
+	}).Warn("Orphaned blocks rolled back")
+
+	return nil
+}
+
+// FetchReceipts 获取区块内所有交易收据，按交易顺序返回
+func FetchReceipts(ctx context.Context, pool *ethereum.ClientPool, block *types.Block) ([]*types.Receipt, error) {
+	if len(block.Transactions()) == 0 {
+		return nil, nil
+	}
+
+	receipts, err := ethereum.ExecuteRead(ctx, pool, func(ctx context.Context, client *ethereum.Client) ([]*types.Receipt, error) {
+		return client.GetEthClient().BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipt count mismatch: got %d, want %d", len(receipts), len(block.Transactions()))
+	}
+	return receipts, nil
+}
+
+// FetchQuorumReceipts 以法定数量读取区块收据，按交易顺序返回
+// 先确认多数节点在该高度的区块哈希与 block 一致，结果不一致的节点由客户端池上报
+func FetchQuorumReceipts(ctx context.Context, pool *ethereum.ClientPool, block *types.Block) ([]*types.Receipt, error) {
+	hash, err := pool.QuorumBlockHash(ctx, block.Number())
+	if err != nil {
+		return nil, err
+	}
+	if hash != block.Hash() {
+		return nil, fmt.Errorf("block %d hash %s differs from quorum hash %s", block.NumberU64(), block.Hash().Hex(), hash.Hex())
+	}
+
+	if len(block.Transactions()) == 0 {
+		return nil, nil
+	}
+
+	receipts, err := pool.QuorumBlockReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) != len(block.Transactions()) {
+		return nil, fmt.Errorf("receipt count mismatch: got %d, want %d", len(receipts), len(block.Transactions()))
+	}
+	return receipts, nil
+}
+
+// persist 在同一事务中写入区块、交易与日志
+func (p *BlockPersister) persist(ctx context.Context, data *BlockData) error {
+	return repository.RunInTx(ctx, p.postgres, func(tx repository.Executor) error {
+		if err := repository.NewBlockRepository(tx).Upsert(ctx, data.Block); err != nil {
+			return err
+		}
+		return PersistTransactions(ctx, tx, []*BlockData{data}, p.batchSize)
+	})
+}
+
+// PersistBlockData 批量写入多个区块及其交易与日志，调用方负责事务
+func PersistBlockData(ctx context.Context, db repository.Executor, data []*BlockData, batchSize int) error {
+	blocks := make([]*models.Block, len(data))
+	for i, d := range data {
+		blocks[i] = d.Block
+	}
+	if err := repository.NewBlockRepository(db).BulkUpsert(ctx, blocks); err != nil {
+		return err
+	}
+	return PersistTransactions(ctx, db, data, batchSize)
+}
+
+// PersistTransactions 按批量大小写入区块内的交易与日志
+func PersistTransactions(ctx context.Context, db repository.Executor, data []*BlockData, batchSize int) error {
+	var (
+		txs  []*models.Transaction
+		logs []*models.TransactionLog
+	)
+	for _, d := range data {
+		txs = append(txs, d.Transactions...)
+		logs = append(logs, d.Logs...)
+	}
+
+	txRepo := repository.NewTransactionRepository(db)
+	for start := 0; start < len(txs); start += batchSize {
+		end := min(start+batchSize, len(txs))
+		if err := txRepo.BulkUpsert(ctx, txs[start:end]); err != nil {
+			return err
+		}
+	}
+
+	logRepo := repository.NewTransactionLogRepository(db)
+	for start := 0; start < len(logs); start += batchSize {
+		end := min(start+batchSize, len(logs))
+		if err := logRepo.BulkUpsert(ctx, logs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PendingTxPersister 待处理交易持久化处理器
+// 交易先进入有界队列，由固定数量的协程批量写入数据库
+type PendingTxPersister struct {
+	// 交易仓库
+	repo *repository.TransactionRepository
+	// 交易签名器
+	signer types.Signer
+	// 待写入队列
+	queue chan *models.Transaction
+	// 写入协程数量
+	poolSize int
+	// 批量写入大小
+	batchSize int
+	// 最大刷新间隔
+	flushInterval time.Duration
+	// 写入超时时间
+	timeout time.Duration
+	// 日志记录器
+	logger *logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPendingTxPersister 创建待处理交易持久化处理器
+func NewPendingTxPersister(
+	postgres *database.PostgresManager,
+	signer types.Signer,
+	poolSize, queueSize, batchSize int,
+	timeout time.Duration,
+	log *logger.Logger,
+) *PendingTxPersister {
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PendingTxPersister{
+		repo:          repository.NewTransactionRepository(postgres.GetDB()),
+		signer:        signer,
+		queue:         make(chan *models.Transaction, queueSize),
+		poolSize:      poolSize,
+		batchSize:     batchSize,
+		flushInterval: time.Second,
+		timeout:       timeout,
+		logger:        log,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Start 启动批量写入协程
+func (p *PendingTxPersister) Start() {
+	for i := 0; i < p.poolSize; i++ {
+		p.wg.Add(1)
+		go p.run()
+	}
+}
+
+// Stop 停止写入协程并刷新剩余交易
+func (p *PendingTxPersister) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// GetName 返回处理器名称
+func (p *PendingTxPersister) GetName() string {
+	return "pending_tx_persister"
+}
+
+// HandleError 处理订阅错误
+func (p *PendingTxPersister) HandleError(err error) {
+	p.logger.WithError(err).Warn("Transaction subscription error")
+}
+
+// HandleTransaction 转换交易并放入写入队列，队列已满时丢弃
+func (p *PendingTxPersister) HandleTransaction(event *ethereum.TxEvent) error {
+	if event.Transaction == nil {
+		return nil
+	}
+
+	txModel, err := ConvertTransaction(event.Transaction, p.signer, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case p.queue <- txModel:
+	default:
+		p.logger.WithField("hash", txModel.Hash).Warn("Pending transaction queue full, dropping transaction")
+	}
+	return nil
+}
+
+// run 从队列读取交易，达到批量大小或刷新间隔时写入
+func (p *PendingTxPersister) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.Transaction, 0, p.batchSize)
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			p.drain(&batch)
+			p.flush(batch)
+			return
+		case tx := <-p.queue:
+			batch = append(batch, tx)
+			if len(batch) >= p.batchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// drain 取出队列中剩余的交易
+func (p *PendingTxPersister) drain(batch *[]*models.Transaction) {
+	for {
+		select {
+		case tx := <-p.queue:
+			*batch = append(*batch, tx)
+		default:
+			return
+		}
+	}
+}
+
+// flush 批量写入交易
+func (p *PendingTxPersister) flush(batch []*models.Transaction) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	if err := p.repo.BulkInsertPending(ctx, batch); err != nil {
+		p.logger.WithError(err).WithField("count", len(batch)).Error("Failed to persist pending transactions")
+		return
+	}
+	p.logger.WithField("count", len(batch)).Debug("Pending transactions persisted")
+}
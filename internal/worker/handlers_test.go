@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/engine"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// memoryRuleStore 内存规则存储
+type memoryRuleStore struct {
+	rules []*models.AlertRule
+}
+
+func (s *memoryRuleStore) ListActive(context.Context) ([]*models.AlertRule, error) {
+	return s.rules, nil
+}
+
+func (s *memoryRuleStore) UpdateTriggerStats(context.Context, uint64, uint64, time.Time) error {
+	return nil
+}
+
+func testLogger() *logger.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &logger.Logger{Logger: log}
+}
+
+// testHeader 指定高度的空区块头，extra 不同的区块头哈希不同
+func testHeader(extra string) *types.Header {
+	return &types.Header{
+		Number:     big.NewInt(100),
+		Difficulty: big.NewInt(0),
+		GasLimit:   30_000_000,
+		TxHash:     types.EmptyTxsHash,
+		UncleHash:  types.EmptyUncleHash,
+		Extra:      []byte(extra),
+	}
+}
+
+// newRPCServer 对任意高度都返回 header 的 JSON-RPC 节点
+func newRPCServer(t *testing.T, header *types.Header) *httptest.Server {
+	t.Helper()
+
+	block, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("failed to encode header: %v", err)
+	}
+	// 以完整区块的形式返回，便于健康检查读取最新区块
+	block = append(block[:len(block)-1], `,"transactions":[],"uncles":[]}`...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result json.RawMessage
+		switch request.Method {
+		case "net_version":
+			result = json.RawMessage(`"1"`)
+		case "eth_blockNumber":
+			result = json.RawMessage(`"0x64"`)
+		case "eth_getBlockByNumber":
+			result = block
+		default:
+			result = json.RawMessage(`null`)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result":  result,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestPool 由节点 URL 创建客户端池
+func newTestPool(t *testing.T, urls ...string) *ethereum.ClientPool {
+	t.Helper()
+
+	clients := make([]*ethereum.ClientConfig, len(urls))
+	for i, url := range urls {
+		clients[i] = &ethereum.ClientConfig{URL: url, ChainID: big.NewInt(1), Priority: i}
+	}
+	pool, err := ethereum.NewClientPool(&ethereum.PoolConfig{
+		Clients:             clients,
+		LoadBalanceStrategy: ethereum.StrategyPriority,
+	}, testLogger().Logger)
+	if err != nil {
+		t.Fatalf("failed to create client pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestFetchQuorumReceiptsReportsDivergentEndpoint(t *testing.T) {
+	canonical := testHeader("canonical")
+	divergent := newRPCServer(t, testHeader("forked"))
+	pool := newTestPool(t,
+		newRPCServer(t, canonical).URL,
+		newRPCServer(t, canonical).URL,
+		divergent.URL,
+	)
+
+	alertEngine := engine.New(engine.DefaultConfig(), &memoryRuleStore{rules: []*models.AlertRule{{
+		BaseModel:  models.BaseModel{ID: 1},
+		Name:       "rpc divergence",
+		Type:       models.AlertTypeSystemHealth,
+		Severity:   models.SeverityHigh,
+		Status:     models.AlertStatusActive,
+		Conditions: `[]`,
+		Operator:   models.OpGreaterThan,
+		TimeWindow: 60,
+		Cooldown:   300,
+	}}}, metrics.NewMetrics("test"), testLogger())
+	if err := alertEngine.Start(); err != nil {
+		t.Fatalf("failed to start engine: %v", err)
+	}
+	defer alertEngine.Stop()
+	pool.AddQuorumHandler(alertEngine)
+
+	// 多数节点认可订阅到的区块，少数节点的分叉结果被上报
+	receipts, err := FetchQuorumReceipts(context.Background(), pool, types.NewBlockWithHeader(canonical))
+	if err != nil {
+		t.Fatalf("FetchQuorumReceipts: %v", err)
+	}
+	if len(receipts) != 0 {
+		t.Fatalf("expected no receipts for an empty block, got %d", len(receipts))
+	}
+
+	select {
+	case alert := <-alertEngine.Alerts():
+		if alert.Type != models.AlertTypeSystemHealth {
+			t.Fatalf("expected a system health alert, got %s", alert.Type)
+		}
+		data, err := alert.GetTriggerData()
+		if err != nil {
+			t.Fatalf("failed to decode trigger data: %v", err)
+		}
+		if data.Context[engine.FieldEndpoint] != strings.TrimPrefix(divergent.URL, "http://") {
+			t.Fatalf("expected the divergent endpoint %s, got %+v", divergent.URL, data.Context)
+		}
+	default:
+		t.Fatal("expected a system health alert for the divergent endpoint")
+	}
+}
+
+func TestFetchQuorumReceiptsRejectsBlockOutsideQuorum(t *testing.T) {
+	canonical := testHeader("canonical")
+	pool := newTestPool(t,
+		newRPCServer(t, canonical).URL,
+		newRPCServer(t, canonical).URL,
+		newRPCServer(t, testHeader("forked")).URL,
+	)
+
+	// 订阅源返回的区块只被少数节点认可，不参与告警评估
+	forked := types.NewBlockWithHeader(testHeader("forked"))
+	if _, err := FetchQuorumReceipts(context.Background(), pool, forked); err == nil {
+		t.Fatal("expected a block outside the quorum to be rejected")
+	}
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"math/big"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// NewClientPool 根据配置创建以太坊 HTTP 客户端池
+// 主端点优先级最高，备用端点按配置顺序排列；节点落后区块数导出到 m，启用时对慢读取请求进行对冲
+func NewClientPool(cfg *config.Config, log *logger.Logger, m *metrics.Metrics) (*ethereum.ClientPool, error) {
+	chainID := big.NewInt(cfg.Ethereum.ChainID)
+
+	urls := append([]string{cfg.Ethereum.HTTPURL}, cfg.Ethereum.HTTPFallbackURLs...)
+	clients := make([]*ethereum.ClientConfig, 0, len(urls))
+	for i, url := range urls {
+		clients = append(clients, &ethereum.ClientConfig{
+			URL:            url,
+			Timeout:        cfg.Ethereum.Timeout,
+			MaxConcurrency: cfg.Worker.PoolSize,
+			ChainID:        chainID,
+			NetworkName:    cfg.Ethereum.Network,
+			Priority:       i,
+		})
+	}
+
+	poolConfig := &ethereum.PoolConfig{
+		Clients:             clients,
+		LoadBalanceStrategy: ethereum.LoadBalanceStrategy(cfg.Ethereum.LoadBalanceStrategy),
+		MaxHeadLag:          uint64(cfg.Ethereum.MaxHeadLag),
+		QuorumSize:          cfg.Ethereum.QuorumSize,
+		EnableFailover:      true,
+	}
+	if cfg.Ethereum.HedgeEnabled {
+		poolConfig.HedgeConfig = &ethereum.HedgeConfig{
+			Percentile:  float64(cfg.Ethereum.HedgePercentile) / 100,
+			BudgetRatio: float64(cfg.Ethereum.HedgeBudgetPercent) / 100,
+		}
+	}
+
+	pool, err := ethereum.NewClientPool(poolConfig, log.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if m != nil {
+		pool.AddHealthCheckHandler(headLagRecorder{metrics: m})
+	}
+	return pool, nil
+}
+
+// headLagRecorder 将健康检查得到的节点落后区块数导出为指标
+type headLagRecorder struct {
+	metrics *metrics.Metrics
+}
+
+// HandleHealthCheck 实现 ethereum.HealthCheckHandler
+func (r headLagRecorder) HandleHealthCheck(results []*ethereum.HealthCheckResult) {
+	for _, result := range results {
+		if !result.IsHealthy {
+			continue
+		}
+		r.metrics.RPCNodeHeadLag.WithLabelValues(ethereum.EndpointName(result.ClientURL)).Set(float64(result.HeadLag))
+	}
+}
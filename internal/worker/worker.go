@@ -0,0 +1,434 @@
+// Package worker 组装区块链订阅组件，将链上数据持久化到数据库
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/internal/config"
+	"simplied-blockchain-data-monitor-alert-go/internal/engine"
+	"simplied-blockchain-data-monitor-alert-go/internal/models"
+	"simplied-blockchain-data-monitor-alert-go/internal/notification"
+	"simplied-blockchain-data-monitor-alert-go/internal/repository"
+	"simplied-blockchain-data-monitor-alert-go/pkg/database"
+	"simplied-blockchain-data-monitor-alert-go/pkg/ethereum"
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
+	"simplied-blockchain-data-monitor-alert-go/pkg/logger"
+	"simplied-blockchain-data-monitor-alert-go/pkg/metrics"
+)
+
+// contractABIReloadInterval 合约 ABI 重新加载间隔
+const contractABIReloadInterval = 30 * time.Second
+
+// Worker 区块链数据采集进程
+type Worker struct {
+	// 应用配置
+	config *config.Config
+	// 日志记录器
+	logger *logger.Logger
+	// Prometheus 指标
+	metrics *metrics.Metrics
+	// PostgreSQL 管理器
+	postgres *database.PostgresManager
+
+	// 以太坊客户端池
+	pool *ethereum.ClientPool
+	// 区块服务
+	blockService *ethereum.BlockService
+	// WebSocket 连接管理器
+	wsManager *ethereum.WSConnectionManager
+	// 订阅管理器
+	subscriptionMgr *ethereum.SubscriptionManager
+	// 区块订阅器
+	blockSubscriber *ethereum.BlockSubscriber
+	// 交易订阅器
+	txSubscriber *ethereum.TxSubscriber
+	// 合约 ABI 注册表，定期从数据库重新加载
+	abis *ethereum.ABIRegistry
+
+	// 待处理交易持久化处理器
+	pendingTxPersister *PendingTxPersister
+
+	// 告警引擎
+	alertEngine *engine.Engine
+	// Gas 价格服务
+	gasService *ethereum.GasService
+	// 告警记录仓库
+	alertRepo *repository.AlertRepository
+	// 通知分发器
+	dispatcher *notification.Dispatcher
+	// 全局告警限流器
+	throttler *notification.Throttler
+	// 告警分组器
+	grouper *notification.Grouper
+	// 摘要通知调度器
+	digestScheduler *notification.DigestScheduler
+	// 告警升级调度器
+	escalator *notification.Escalator
+	// 限制并发通知数量
+	dispatchSem chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// recordAlerts 读完分组器输出后结束，停止时在取消上下文之前等待
+	recording sync.WaitGroup
+}
+
+// New 根据配置创建采集进程
+func New(cfg *config.Config, log *logger.Logger, postgres *database.PostgresManager, redis *database.RedisManager, m *metrics.Metrics) (*Worker, error) {
+	if !strings.HasPrefix(cfg.Ethereum.RPCURL, "ws://") && !strings.HasPrefix(cfg.Ethereum.RPCURL, "wss://") {
+		return nil, fmt.Errorf("ETH_RPC_URL must be a WebSocket endpoint for subscriptions, got %q", cfg.Ethereum.RPCURL)
+	}
+
+	chainID := big.NewInt(cfg.Ethereum.ChainID)
+
+	// 创建客户端池，HTTP 端点用于拉取完整区块和收据
+	pool, err := NewClientPool(cfg, log, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client pool: %w", err)
+	}
+
+	// 创建 WebSocket 连接与订阅管理器
+	wsConfig := ethereum.DefaultWSConfig()
+	wsConfig.URL = cfg.Ethereum.RPCURL
+	wsManager := ethereum.NewWSConnectionManager(wsConfig)
+	subscriptionMgr := ethereum.NewSubscriptionManager(wsManager)
+
+	blockService := ethereum.NewBlockService(pool, log.Logger)
+	signer := types.LatestSignerForChainID(chainID)
+	abis := ethereum.NewABIRegistry()
+
+	// 区块订阅器，采集进程需要全部区块，因此不启用过滤
+	blockConfig := ethereum.DefaultBlockSubscriberConfig()
+	blockConfig.BufferSize = cfg.Worker.QueueSize
+	blockConfig.BatchSize = cfg.Worker.BatchSize
+	blockConfig.ProcessingTimeout = cfg.Worker.Timeout
+	blockConfig.EnableFiltering = false
+	blockConfig.ReorgDepth = cfg.Worker.ReorgDepth
+	blockSubscriber := ethereum.NewBlockSubscriber(blockConfig, subscriptionMgr, nil)
+	blockSubscriber.SetBlockFetcher(blockService)
+	// 断线重连或重启后，从已持久化的最新区块补齐缺失区块
+	blockSubscriber.SetCatchUpSource(blockService, repository.NewBlockRepository(postgres.GetDB()).LatestNumber)
+	blockPersister := NewBlockPersister(
+		postgres, pool, blockService, signer, abis, cfg.Ethereum.Network,
+		cfg.Worker.BatchSize, cfg.Worker.Timeout, m, log,
+	)
+	blockPersister.SetQuorumReads(cfg.Ethereum.QuorumReads)
+	blockSubscriber.AddHandler(blockPersister)
+
+	// 交易订阅器
+	txConfig := ethereum.DefaultTxSubscriberConfig()
+	txConfig.BufferSize = cfg.Worker.QueueSize
+	txConfig.BatchSize = cfg.Worker.BatchSize
+	txConfig.ProcessingTimeout = cfg.Worker.Timeout
+	txConfig.MaxConcurrency = cfg.Worker.PoolSize
+	txConfig.EnableFiltering = false
+	txSubscriber := ethereum.NewTxSubscriber(txConfig, subscriptionMgr, nil, pool)
+	pendingTxPersister := NewPendingTxPersister(
+		postgres, signer, cfg.Worker.PoolSize, cfg.Worker.QueueSize, cfg.Worker.BatchSize,
+		cfg.Worker.Timeout, log,
+	)
+	txSubscriber.AddHandler(pendingTxPersister)
+
+	// 告警引擎订阅区块、交易、合约日志与 Gas 价格事件
+	engineConfig := engine.DefaultConfig()
+	engineConfig.ChainID = chainID
+	engineConfig.BufferSize = cfg.Worker.QueueSize
+	engineConfig.ABIs = abis
+	watchlists, err := cfg.Alert.WatchlistMap()
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert watchlists: %w", err)
+	}
+	engineConfig.Watchlists = expr.MapWatchlists(watchlists)
+	alertEngine := engine.New(engineConfig, repository.NewAlertRuleRepository(postgres.GetDB()), m, log)
+	blockSubscriber.AddHandler(alertEngine)
+	txSubscriber.AddHandler(alertEngine)
+	// 日志在区块写入后评估，与已持久化的数据保持一致
+	blockPersister.AddLogHandler(alertEngine)
+	// 多节点法定数量读取结果不一致时生成系统健康事件
+	pool.AddQuorumHandler(alertEngine)
+
+	alertRepo := repository.NewAlertRepository(postgres.GetDB())
+	dispatcher := notification.NewDispatcher(cfg.Alert, alertRepo, log, notification.NewDefaultNotifiers(cfg)...)
+	throttler := notification.NewThrottler(redis, cfg.Alert, m, log)
+	dispatcher.SetThrottler(throttler)
+	digestScheduler := notification.NewDigestScheduler(
+		dispatcher, repository.NewAlertRuleRepository(postgres.GetDB()), alertRepo, redis,
+		notification.SendTimeout(cfg), log,
+	)
+	escalator := notification.NewEscalator(
+		dispatcher, repository.NewAlertRuleRepository(postgres.GetDB()), alertRepo,
+		repository.NewAlertEscalationRepository(postgres.GetDB()), notification.SendTimeout(cfg), log,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		config:             cfg,
+		logger:             log,
+		metrics:            m,
+		postgres:           postgres,
+		pool:               pool,
+		blockService:       blockService,
+		wsManager:          wsManager,
+		subscriptionMgr:    subscriptionMgr,
+		blockSubscriber:    blockSubscriber,
+		txSubscriber:       txSubscriber,
+		abis:               abis,
+		pendingTxPersister: pendingTxPersister,
+		alertEngine:        alertEngine,
+		gasService:         ethereum.NewGasService(pool, log.Logger),
+		alertRepo:          alertRepo,
+		dispatcher:         dispatcher,
+		throttler:          throttler,
+		grouper:            notification.NewGrouper(cfg.Worker.QueueSize, m, log),
+		digestScheduler:    digestScheduler,
+		escalator:          escalator,
+		dispatchSem:        make(chan struct{}, cfg.Worker.PoolSize),
+		ctx:                ctx,
+		cancel:             cancel,
+	}, nil
+}
+
+// Start 建立连接并启动订阅
+func (w *Worker) Start() error {
+	if err := w.wsManager.Connect(); err != nil {
+		return fmt.Errorf("failed to connect websocket: %w", err)
+	}
+
+	w.pendingTxPersister.Start()
+
+	// ABI 加载失败不影响启动，内置 ABI 仍可用
+	if err := w.loadContractABIs(); err != nil {
+		w.logger.WithError(err).Warn("Failed to load contract ABIs")
+	}
+
+	if err := w.alertEngine.Start(); err != nil {
+		return fmt.Errorf("failed to start alert engine: %w", err)
+	}
+	prices, err := w.gasService.MonitorGasPrices(w.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to monitor gas prices: %w", err)
+	}
+	w.alertEngine.WatchGasPrices(prices)
+
+	w.wg.Add(3)
+	w.recording.Add(1)
+	go w.groupAlerts()
+	go w.recordAlerts()
+	go w.resolveAlerts()
+	go w.reloadContractABIs()
+	w.digestScheduler.Start()
+	w.escalator.Start()
+
+	if err := w.blockSubscriber.Start(); err != nil {
+		return fmt.Errorf("failed to start block subscriber: %w", err)
+	}
+	if err := w.txSubscriber.Start(); err != nil {
+		return fmt.Errorf("failed to start transaction subscriber: %w", err)
+	}
+
+	w.logger.WithField("network", w.config.Ethereum.Network).Info("Worker started")
+	return nil
+}
+
+// Stop 停止订阅并释放资源
+func (w *Worker) Stop() {
+	w.logger.Info("Stopping worker")
+
+	if err := w.txSubscriber.Stop(); err != nil {
+		w.logger.WithError(err).Warn("Failed to stop transaction subscriber")
+	}
+	if err := w.blockSubscriber.Stop(); err != nil {
+		w.logger.WithError(err).Warn("Failed to stop block subscriber")
+	}
+
+	w.pendingTxPersister.Stop()
+	w.alertEngine.Stop()
+	// 输出未结束的分组并关闭分组器，等待 recordAlerts 处理完剩余告警后再取消上下文
+	w.grouper.Stop()
+	w.recording.Wait()
+	w.digestScheduler.Stop()
+	w.escalator.Stop()
+	w.cancel()
+	w.wg.Wait()
+
+	if err := w.subscriptionMgr.Close(); err != nil {
+		w.logger.WithError(err).Warn("Failed to close subscription manager")
+	}
+	if err := w.wsManager.Disconnect(); err != nil {
+		w.logger.WithError(err).Warn("Failed to disconnect websocket")
+	}
+	w.pool.Close()
+
+	w.logger.Info("Worker stopped")
+}
+
+// loadContractABIs 从数据库加载合约 ABI
+func (w *Worker) loadContractABIs() error {
+	ctx, cancel := context.WithTimeout(w.ctx, w.config.Worker.Timeout)
+	defer cancel()
+	return LoadContractABIs(ctx, w.postgres.GetDB(), w.abis)
+}
+
+// reloadContractABIs 定期重新加载合约 ABI，使通过接口上传的 ABI 生效
+func (w *Worker) reloadContractABIs() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(contractABIReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.loadContractABIs(); err != nil {
+				w.logger.WithError(err).Warn("Failed to reload contract ABIs")
+			}
+		}
+	}
+}
+
+// groupAlerts 将告警引擎生成的告警交给分组器
+func (w *Worker) groupAlerts() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case alert := <-w.alertEngine.Alerts():
+			w.grouper.Add(alert)
+		}
+	}
+}
+
+// recordAlerts 持久化分组后的告警并异步发送通知，分组器停止且输出读完后返回
+// 处于静默期或被全局限流抑制的告警以 suppressed 状态记录，不发送通知
+func (w *Worker) recordAlerts() {
+	defer w.recording.Done()
+
+	for alert := range w.grouper.Alerts() {
+		ctx, cancel := context.WithTimeout(w.ctx, w.config.Worker.Timeout)
+		if w.isSnoozed(ctx, alert) {
+			alert.MarkAsSuppressed(notification.SuppressReasonSnoozed)
+			w.metrics.AlertsSuppressedTotal.WithLabelValues(string(alert.Type), notification.SuppressReasonSnoozed).Inc()
+		} else if reason := w.throttler.Check(ctx, alert); reason != "" {
+			alert.MarkAsSuppressed(reason)
+		}
+		err := w.alertRepo.Create(ctx, alert)
+		cancel()
+		if err != nil {
+			w.logger.WithError(err).WithField("rule_id", alert.RuleID).Error("Failed to persist alert")
+			continue
+		}
+		if alert.Status == models.NotificationStatusSuppressed {
+			continue
+		}
+
+		w.dispatchSem <- struct{}{}
+		w.wg.Add(1)
+		go w.dispatch(alert)
+	}
+}
+
+// isSnoozed 检查同一规则与分组是否存在处于静默期的未解决告警，查询失败时视为未静默
+func (w *Worker) isSnoozed(ctx context.Context, alert *models.Alert) bool {
+	snoozed, err := w.alertRepo.IsSnoozed(ctx, alert.RuleID, alert.GroupKey, time.Now())
+	if err != nil {
+		w.logger.WithError(err).WithField("rule_id", alert.RuleID).Warn("Failed to check alert snooze")
+		return false
+	}
+	return snoozed
+}
+
+// resolveAlerts 规则条件恢复后自动解决其未解决的告警，已发送过通知的告警向原渠道发送解决通知
+func (w *Worker) resolveAlerts() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case resolution := <-w.alertEngine.Resolutions():
+			ctx, cancel := context.WithTimeout(w.ctx, w.config.Worker.Timeout)
+			alerts, err := w.alertRepo.ListUnresolvedByRule(ctx, resolution.Rule.ID)
+			if err != nil {
+				cancel()
+				w.logger.WithError(err).WithField("rule_id", resolution.Rule.ID).Error("Failed to load unresolved alerts")
+				continue
+			}
+
+			var resolved []*models.Alert
+			for _, alert := range alerts {
+				alert.Resolve(nil, resolution.ResolvedAt)
+				if err := w.alertRepo.UpdateLifecycle(ctx, alert); err != nil {
+					w.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to resolve alert")
+					continue
+				}
+				if alert.NotificationSent {
+					resolved = append(resolved, alert)
+				}
+			}
+			cancel()
+
+			if len(alerts) > 0 {
+				w.logger.WithFields(logrus.Fields{
+					"rule_id": resolution.Rule.ID,
+					"alerts":  len(alerts),
+				}).Info("Alerts auto-resolved")
+			}
+
+			for _, alert := range resolved {
+				select {
+				case w.dispatchSem <- struct{}{}:
+				case <-w.ctx.Done():
+					return
+				}
+				w.wg.Add(1)
+				go w.dispatchResolved(&resolution.Rule, alert)
+			}
+		}
+	}
+}
+
+// dispatch 发送告警通知，重试等待包含在超时时间内
+func (w *Worker) dispatch(alert *models.Alert) {
+	defer w.wg.Done()
+	defer func() { <-w.dispatchSem }()
+
+	ctx, cancel := context.WithTimeout(w.ctx, notification.SendTimeout(w.config))
+	defer cancel()
+
+	if err := w.dispatcher.Dispatch(ctx, &alert.Rule, alert); err != nil {
+		w.logger.WithError(err).WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"rule_id":  alert.RuleID,
+		}).Error("Failed to deliver alert notifications")
+	}
+}
+
+// dispatchResolved 发送告警解决通知
+func (w *Worker) dispatchResolved(rule *models.AlertRule, alert *models.Alert) {
+	defer w.wg.Done()
+	defer func() { <-w.dispatchSem }()
+
+	ctx, cancel := context.WithTimeout(w.ctx, notification.SendTimeout(w.config))
+	defer cancel()
+
+	if err := w.dispatcher.DispatchResolved(ctx, rule, alert); err != nil {
+		w.logger.WithError(err).WithFields(logrus.Fields{
+			"alert_id": alert.ID,
+			"rule_id":  alert.RuleID,
+		}).Error("Failed to deliver alert resolution notifications")
+	}
+}
@@ -0,0 +1,8 @@
+-- 删除触发器
+DROP TRIGGER IF EXISTS update_backfill_checkpoints_updated_at ON backfill_checkpoints;
+
+-- 删除索引
+DROP INDEX IF EXISTS idx_transaction_logs_tx_hash_log_index;
+
+-- 删除表
+DROP TABLE IF EXISTS backfill_checkpoints;
@@ -0,0 +1,25 @@
+-- 创建回填进度表
+CREATE TABLE IF NOT EXISTS backfill_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    start_block BIGINT NOT NULL,
+    end_block BIGINT NOT NULL,
+    last_block BIGINT,
+    status VARCHAR(20) NOT NULL DEFAULT 'running'
+);
+
+-- 清理已有的重复交易日志，每组保留 id 最大（最近写入）的一条
+DELETE FROM transaction_logs older
+    USING transaction_logs newer
+    WHERE older.transaction_hash = newer.transaction_hash
+      AND older.log_index = newer.log_index
+      AND older.id < newer.id;
+
+-- 交易日志按交易哈希与日志序号去重，支持重复写入
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transaction_logs_tx_hash_log_index ON transaction_logs(transaction_hash, log_index);
+
+-- 回填进度表触发器
+CREATE TRIGGER update_backfill_checkpoints_updated_at BEFORE UPDATE ON backfill_checkpoints
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+-- 删除索引
+DROP INDEX IF EXISTS idx_alerts_group_key;
+
+-- 删除列
+ALTER TABLE alerts DROP COLUMN IF EXISTS group_count;
+ALTER TABLE alerts DROP COLUMN IF EXISTS group_key;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS group_window;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS group_by;
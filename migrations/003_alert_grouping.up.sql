@@ -0,0 +1,10 @@
+-- 告警规则分组配置
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS group_by TEXT;
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS group_window INTEGER DEFAULT 0;
+
+-- 告警记录分组信息
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS group_key VARCHAR(255);
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS group_count INTEGER DEFAULT 1;
+
+-- 告警记录分组键索引
+CREATE INDEX IF NOT EXISTS idx_alerts_group_key ON alerts(group_key);
@@ -0,0 +1,9 @@
+-- 删除索引
+DROP INDEX IF EXISTS idx_alerts_rule_id_unresolved;
+
+-- 删除列
+ALTER TABLE alerts DROP COLUMN IF EXISTS snoozed_until;
+ALTER TABLE alerts DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE alerts DROP COLUMN IF EXISTS resolved_by;
+ALTER TABLE alerts DROP COLUMN IF EXISTS acknowledged_at;
+ALTER TABLE alerts DROP COLUMN IF EXISTS acknowledged_by;
@@ -0,0 +1,9 @@
+-- 告警记录生命周期字段
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS acknowledged_by BIGINT;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS resolved_by BIGINT;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMP WITH TIME ZONE;
+
+-- 未解决告警按规则查询
+CREATE INDEX IF NOT EXISTS idx_alerts_rule_id_unresolved ON alerts(rule_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,9 @@
+-- 删除触发器
+DROP TRIGGER IF EXISTS update_alert_escalations_updated_at ON alert_escalations;
+
+-- 删除表
+DROP TABLE IF EXISTS alert_escalations;
+
+-- 删除列
+ALTER TABLE alerts DROP COLUMN IF EXISTS escalation_level;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS escalation_policy;
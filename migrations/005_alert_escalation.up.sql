@@ -0,0 +1,29 @@
+-- 告警规则升级策略
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS escalation_policy TEXT;
+
+-- 告警记录已执行的升级步骤数
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS escalation_level INTEGER DEFAULT 0;
+
+-- 创建告警升级记录表
+CREATE TABLE IF NOT EXISTS alert_escalations (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    alert_id BIGINT NOT NULL,
+    rule_id BIGINT NOT NULL,
+    level INTEGER NOT NULL,
+    from_severity VARCHAR(20) NOT NULL,
+    to_severity VARCHAR(20) NOT NULL,
+    channels TEXT,
+    notified BOOLEAN DEFAULT FALSE,
+    error_message TEXT
+);
+
+-- 告警升级记录表索引
+CREATE INDEX IF NOT EXISTS idx_alert_escalations_alert_id ON alert_escalations(alert_id);
+CREATE INDEX IF NOT EXISTS idx_alert_escalations_rule_id ON alert_escalations(rule_id);
+CREATE INDEX IF NOT EXISTS idx_alert_escalations_created_at ON alert_escalations(created_at);
+
+-- 告警升级记录表触发器
+CREATE TRIGGER update_alert_escalations_updated_at BEFORE UPDATE ON alert_escalations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
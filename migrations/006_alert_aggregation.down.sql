@@ -0,0 +1,3 @@
+-- 删除列
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS aggregation_field;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS aggregation;
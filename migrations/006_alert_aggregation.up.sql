@@ -0,0 +1,3 @@
+-- 告警规则窗口聚合配置
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS aggregation VARCHAR(20);
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS aggregation_field VARCHAR(100);
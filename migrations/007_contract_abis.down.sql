@@ -0,0 +1,10 @@
+-- 删除索引与列
+DROP INDEX IF EXISTS idx_transaction_logs_event_name;
+ALTER TABLE transaction_logs DROP COLUMN IF EXISTS decoded;
+ALTER TABLE transaction_logs DROP COLUMN IF EXISTS event_name;
+
+-- 删除触发器
+DROP TRIGGER IF EXISTS update_contract_abis_updated_at ON contract_abis;
+
+-- 删除表
+DROP TABLE IF EXISTS contract_abis;
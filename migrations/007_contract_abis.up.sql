@@ -0,0 +1,20 @@
+-- 创建合约 ABI 表
+CREATE TABLE IF NOT EXISTS contract_abis (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    address VARCHAR(42) NOT NULL UNIQUE,
+    name VARCHAR(255),
+    abi TEXT NOT NULL,
+    user_id BIGINT
+);
+
+-- 合约 ABI 表触发器
+CREATE TRIGGER update_contract_abis_updated_at BEFORE UPDATE ON contract_abis
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- 交易日志按 ABI 解码的事件名称与参数
+ALTER TABLE transaction_logs ADD COLUMN IF NOT EXISTS event_name VARCHAR(255);
+ALTER TABLE transaction_logs ADD COLUMN IF NOT EXISTS decoded TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transaction_logs_event_name ON transaction_logs(event_name);
@@ -0,0 +1,2 @@
+-- 删除刷新令牌标识列
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS refresh_token_id;
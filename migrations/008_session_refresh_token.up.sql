@@ -0,0 +1,2 @@
+-- 会话当前有效的刷新令牌标识，每次刷新时轮换
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS refresh_token_id VARCHAR(64);
@@ -86,6 +86,11 @@ func (hc *HealthChecker) Start() {
 	}()
 }
 
+// Stop 停止健康检查
+func (hc *HealthChecker) Stop() {
+	close(hc.stopChan)
+}
+
 // GetHealth 获取健康状态
 func (hc *HealthChecker) GetHealth() DatabaseHealth {
 	hc.mutex.RLock()
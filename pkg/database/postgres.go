@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
 	_ "github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -62,6 +64,9 @@ func NewPostgresManager(cfg config.DatabaseConfig, logger *logger.Logger) (*Post
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
+	// 模型仅定义 json 标签，且与列名一致，因此按 json 标签映射列
+	db.Mapper = reflectx.NewMapperFunc("json", strings.ToLower)
+
 	// 配置连接池
 	// 最大打开连接数
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
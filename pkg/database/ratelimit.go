@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// slidingWindowScript 滑动窗口限流脚本
+// 清理窗口外的请求记录，未超限时记录本次请求，返回 {是否放行, 窗口内请求数, 最早请求时间(毫秒)}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local oldest = now
+local first = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if first[2] then
+	oldest = tonumber(first[2])
+end
+return {allowed, count, oldest}
+`)
+
+// RateLimitResult 限流判定结果
+type RateLimitResult struct {
+	// 是否放行
+	Allowed bool
+	// 窗口内允许的最大请求数
+	Limit int
+	// 窗口内剩余请求数
+	Remaining int
+	// 窗口内最早请求过期、释放配额的时间
+	ResetAt time.Time
+	// 被拒绝时距离可重试的时长
+	RetryAfter time.Duration
+}
+
+// AllowSlidingWindow 按滑动窗口判定 key 的本次请求是否放行
+// 计数存储在 Redis 有序集合中，多个服务副本共享同一限额
+func (rm *RedisManager) AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	start := time.Now()
+
+	now := start.UnixMilli()
+	member := fmt.Sprintf("%d-%d", now, rand.Int64())
+	values, err := slidingWindowScript.Run(ctx, rm.client, []string{key}, now, window.Milliseconds(), limit, member).Int64Slice()
+	duration := time.Since(start)
+
+	// 记录指标
+	rm.metrics.CommandDuration.WithLabelValues("ratelimit").Observe(duration.Seconds())
+
+	if err == nil && len(values) != 3 {
+		err = fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	if err != nil {
+		rm.metrics.CommandsTotal.WithLabelValues("ratelimit", "error").Inc()
+		rm.metrics.ErrorsTotal.WithLabelValues("command").Inc()
+
+		rm.logger.WithFields(logrus.Fields{
+			"key":      key,
+			"error":    err.Error(),
+			"duration": duration.Milliseconds(),
+		}).Error("Redis rate limit check failed")
+
+		return nil, err
+	}
+
+	allowed := values[0] == 1
+	status := "allowed"
+	if !allowed {
+		status = "limited"
+	}
+	rm.metrics.CommandsTotal.WithLabelValues("ratelimit", status).Inc()
+
+	count := int(values[1])
+	resetAt := time.UnixMilli(values[2]).Add(window)
+	result := &RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(limit-count, 0),
+		ResetAt:   resetAt,
+	}
+	if !allowed {
+		result.RetryAfter = max(resetAt.Sub(start), 0)
+	}
+
+	return result, nil
+}
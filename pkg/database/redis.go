@@ -213,6 +213,40 @@ func (rm *RedisManager) Set(ctx context.Context, key string, value interface{},
 	return nil
 }
 
+// SetNX 键不存在时设置键值，返回是否设置成功
+func (rm *RedisManager) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	start := time.Now()
+
+	ok, err := rm.client.SetNX(ctx, key, value, expiration).Result()
+	duration := time.Since(start)
+
+	// 记录指标
+	rm.metrics.CommandDuration.WithLabelValues("setnx").Observe(duration.Seconds())
+
+	if err != nil {
+		rm.metrics.CommandsTotal.WithLabelValues("setnx", "error").Inc()
+		rm.metrics.ErrorsTotal.WithLabelValues("command").Inc()
+
+		rm.logger.WithFields(logrus.Fields{
+			"key":      key,
+			"error":    err.Error(),
+			"duration": duration.Milliseconds(),
+		}).Error("Redis SETNX failed")
+
+		return false, err
+	}
+
+	rm.metrics.CommandsTotal.WithLabelValues("setnx", "success").Inc()
+
+	rm.logger.WithFields(logrus.Fields{
+		"key":      key,
+		"set":      ok,
+		"duration": duration.Milliseconds(),
+	}).Debug("Redis SETNX executed")
+
+	return ok, nil
+}
+
 // Get 获取键值
 func (rm *RedisManager) Get(ctx context.Context, key string) (string, error) {
 	start := time.Now()
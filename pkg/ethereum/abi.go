@@ -0,0 +1,326 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Token standards recognized by the built-in ABIs
+const (
+	StandardERC20   = "erc20"
+	StandardERC721  = "erc721"
+	StandardERC1155 = "erc1155"
+)
+
+// erc20ABI covers the ERC-20 events and transfer/approve methods
+const erc20ABI = `[
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// erc721ABI covers the ERC-721 events and transfer methods.
+// Transfer and Approval share their signatures with ERC-20 but index every argument.
+const erc721ABI = `[
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"approved","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]}
+]`
+
+// erc1155ABI covers the ERC-1155 events and transfer methods
+const erc1155ABI = `[
+	{"type":"event","name":"TransferSingle","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"id","type":"uint256","indexed":false},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"TransferBatch","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"account","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]},
+	{"type":"event","name":"URI","inputs":[{"name":"value","type":"string","indexed":false},{"name":"id","type":"uint256","indexed":true}]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"safeBatchTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"values","type":"uint256[]"},{"name":"data","type":"bytes"}],"outputs":[]}
+]`
+
+// standardABI is a built-in ABI tagged with its token standard
+type standardABI struct {
+	standard string
+	abi      *abi.ABI
+}
+
+// builtinABIs are tried in order for contracts without a registered ABI.
+// Ambiguous selectors (ERC-20/ERC-721 transferFrom, ApprovalForAll) resolve to the first match.
+var builtinABIs = []standardABI{
+	{standard: StandardERC20, abi: mustParseABI(erc20ABI)},
+	{standard: StandardERC721, abi: mustParseABI(erc721ABI)},
+	{standard: StandardERC1155, abi: mustParseABI(erc1155ABI)},
+}
+
+// mustParseABI parses a built-in ABI
+func mustParseABI(definition string) *abi.ABI {
+	parsed, err := ParseABI(definition)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// ParseABI parses a JSON contract ABI; an ABI without events or methods is rejected
+func ParseABI(definition string) (*abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	if len(parsed.Events) == 0 && len(parsed.Methods) == 0 {
+		return nil, errors.New("ABI declares no events or methods")
+	}
+	return &parsed, nil
+}
+
+// DecodedEvent is a log decoded into named arguments.
+// Addresses and byte values are lowercase hex strings; integers are *big.Int.
+type DecodedEvent struct {
+	Name      string                 `json:"name"`
+	Signature string                 `json:"signature"`
+	Standard  string                 `json:"standard,omitempty"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// DecodedCall is transaction input decoded into named arguments
+type DecodedCall struct {
+	Name      string                 `json:"name"`
+	Signature string                 `json:"signature"`
+	Standard  string                 `json:"standard,omitempty"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// ABIRegistry holds per-contract ABIs and decodes logs and transaction input with them.
+// Contracts without a registered ABI are decoded with the built-in token standard ABIs.
+type ABIRegistry struct {
+	mu        sync.RWMutex
+	contracts map[common.Address]*abi.ABI
+}
+
+// NewABIRegistry creates a registry containing only the built-in ABIs
+func NewABIRegistry() *ABIRegistry {
+	return &ABIRegistry{contracts: make(map[common.Address]*abi.ABI)}
+}
+
+// Register sets the ABI of a contract, replacing any previous one
+func (r *ABIRegistry) Register(address common.Address, definition string) error {
+	parsed, err := ParseABI(definition)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.contracts[address] = parsed
+	r.mu.Unlock()
+	return nil
+}
+
+// Unregister removes the ABI of a contract
+func (r *ABIRegistry) Unregister(address common.Address) {
+	r.mu.Lock()
+	delete(r.contracts, address)
+	r.mu.Unlock()
+}
+
+// Replace swaps in a complete set of contract ABIs.
+// Invalid definitions are skipped and reported in the returned error.
+func (r *ABIRegistry) Replace(definitions map[common.Address]string) error {
+	contracts := make(map[common.Address]*abi.ABI, len(definitions))
+	var errs []error
+	for address, definition := range definitions {
+		parsed, err := ParseABI(definition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address.Hex(), err))
+			continue
+		}
+		contracts[address] = parsed
+	}
+
+	r.mu.Lock()
+	r.contracts = contracts
+	r.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Len returns the number of registered contract ABIs
+func (r *ABIRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.contracts)
+}
+
+// candidates returns the ABIs to try for a contract: its registered ABI first, then the built-ins
+func (r *ABIRegistry) candidates(address common.Address) []standardABI {
+	r.mu.RLock()
+	registered, ok := r.contracts[address]
+	r.mu.RUnlock()
+	if !ok {
+		return builtinABIs
+	}
+	return append([]standardABI{{abi: registered}}, builtinABIs...)
+}
+
+// DecodeLog decodes a log, returning nil when no known ABI matches it
+func (r *ABIRegistry) DecodeLog(log *types.Log) *DecodedEvent {
+	if log == nil || len(log.Topics) == 0 {
+		return nil
+	}
+
+	for _, candidate := range r.candidates(log.Address) {
+		event, err := candidate.abi.EventByID(log.Topics[0])
+		if err != nil || event.Anonymous {
+			continue
+		}
+		args, err := decodeEventArgs(event, log)
+		if err != nil {
+			// Same signature with a different indexed layout, e.g. ERC-20 vs ERC-721 Transfer
+			continue
+		}
+		return &DecodedEvent{
+			Name:      event.RawName,
+			Signature: event.Sig,
+			Standard:  candidate.standard,
+			Args:      args,
+		}
+	}
+	return nil
+}
+
+// DecodeCall decodes transaction input sent to a contract, returning nil when no known ABI matches it
+func (r *ABIRegistry) DecodeCall(to *common.Address, data []byte) *DecodedCall {
+	if to == nil || len(data) < 4 {
+		return nil
+	}
+
+	for _, candidate := range r.candidates(*to) {
+		method, err := candidate.abi.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		values, err := method.Inputs.UnpackValues(data[4:])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]interface{}, len(values))
+		for i, input := range method.Inputs {
+			args[argName(input, i)] = normalizeABIValue(values[i])
+		}
+		return &DecodedCall{
+			Name:      method.RawName,
+			Signature: method.Sig,
+			Standard:  candidate.standard,
+			Args:      args,
+		}
+	}
+	return nil
+}
+
+// decodeEventArgs decodes indexed arguments from topics and the rest from data
+func decodeEventArgs(event *abi.Event, log *types.Log) (map[string]interface{}, error) {
+	var indexed abi.Arguments
+	for i, input := range event.Inputs {
+		if input.Indexed {
+			input.Name = argName(input, i)
+			indexed = append(indexed, input)
+		}
+	}
+	if len(indexed) != len(log.Topics)-1 {
+		return nil, errors.New("topic count mismatch")
+	}
+
+	raw := make(map[string]interface{}, len(event.Inputs))
+	if err := abi.ParseTopicsIntoMap(raw, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	values, err := event.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return nil, err
+	}
+	i := 0
+	for pos, input := range event.Inputs {
+		if input.Indexed {
+			continue
+		}
+		raw[argName(input, pos)] = values[i]
+		i++
+	}
+
+	args := make(map[string]interface{}, len(raw))
+	for name, value := range raw {
+		args[name] = normalizeABIValue(value)
+	}
+	return args, nil
+}
+
+// argName returns the argument name, or argN for unnamed arguments
+func argName(input abi.Argument, index int) string {
+	if input.Name == "" {
+		return fmt.Sprintf("arg%d", index)
+	}
+	return input.Name
+}
+
+// normalizeABIValue converts decoded values to plain types: lowercase hex strings for
+// addresses and bytes, *big.Int for integers, slices and maps for arrays and tuples
+func normalizeABIValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return strings.ToLower(v.Hex())
+	case common.Hash:
+		return v.Hex()
+	case *big.Int:
+		return v
+	case []byte:
+		return hexutil.Encode(v)
+	case string, bool:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint())
+	case reflect.Array:
+		// Fixed-size byte arrays (bytesN)
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = normalizeABIValue(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Struct:
+		// Tuples decode to anonymous structs tagged with the component names
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = normalizeABIValue(rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return fmt.Sprintf("%v", value)
+}
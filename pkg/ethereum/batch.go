@@ -0,0 +1,373 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRPCBatchSize 单次JSON-RPC批量请求的默认最大调用数
+const DefaultRPCBatchSize = 100
+
+// BatchCallOptions JSON-RPC批量请求选项
+type BatchCallOptions struct {
+	// 单次批量请求的最大调用数，超出部分拆分为多个批量请求
+	MaxSize int
+	// 失败调用的重试次数，只重试失败的调用
+	RetryAttempts int
+	// 重试延迟，按重试次数线性递增
+	RetryDelay time.Duration
+	// 校验单个调用的结果，返回错误时视为该调用失败（如节点返回 null）
+	Validate func(elem *rpc.BatchElem) error
+}
+
+// BatchCall 以JSON-RPC批量请求执行 elems，单个调用的错误写入对应的 elem.Error
+// 节点拒绝批量请求时记录该节点不支持批量，本次及之后的调用退化为逐个请求
+func (c *Client) BatchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	rpcClient := c.GetRPCClient()
+	if rpcClient == nil {
+		return fmt.Errorf("rpc client is nil")
+	}
+
+	c.mu.Lock()
+	c.requestCount++
+	batchDisabled := c.batchDisabled
+	c.mu.Unlock()
+
+	if !batchDisabled {
+		err := rpcClient.BatchCallContext(ctx, elems)
+		if err == nil {
+			err = batchRejection(elems)
+			if err == nil {
+				return nil
+			}
+		} else if !isBatchRejected(err) {
+			c.recordError(err)
+			return err
+		}
+
+		c.mu.Lock()
+		c.batchDisabled = true
+		c.mu.Unlock()
+
+		c.logger.WithFields(logrus.Fields{
+			"url":   c.config.URL,
+			"error": err,
+		}).Warn("Node rejected JSON-RPC batch request, falling back to individual calls")
+	}
+
+	for i := range elems {
+		elem := &elems[i]
+		elem.Error = rpcClient.CallContext(ctx, elem.Result, elem.Method, elem.Args...)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordError 记录一次请求错误
+func (c *Client) recordError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errorCount++
+	c.lastError = err
+}
+
+// batchRejection 返回批量响应中表示节点拒绝批量请求的错误
+// 部分节点对整批返回单个错误（如 batch too large），go-ethereum 将其写入首个调用
+func batchRejection(elems []rpc.BatchElem) error {
+	for _, elem := range elems {
+		if elem.Error != nil && !errors.Is(elem.Error, rpc.ErrMissingBatchResponse) && isBatchRejected(elem.Error) {
+			return elem.Error
+		}
+	}
+	return nil
+}
+
+// isBatchRejected 检查错误是否表示节点不接受批量请求
+func isBatchRejected(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType, http.StatusNotImplemented:
+			return true
+		}
+	}
+
+	// 不支持批量的节点返回单个对象而非数组
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return true
+	}
+
+	// -32600: Invalid Request
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32600 {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "batch")
+}
+
+// BatchCall 以JSON-RPC批量请求执行 elems（带故障转移）
+// 批量请求整体失败时切换客户端重试；单个调用的错误写入对应的 elem.Error
+func (p *ClientPool) BatchCall(ctx context.Context, elems []rpc.BatchElem, maxSize int) error {
+	if maxSize <= 0 {
+		maxSize = DefaultRPCBatchSize
+	}
+
+	for start := 0; start < len(elems); start += maxSize {
+		chunk := elems[start:min(start+maxSize, len(elems))]
+		err := p.ExecuteWithFailover(ctx, func(client *Client) error {
+			for i := range chunk {
+				chunk[i].Error = nil
+			}
+			return client.BatchCall(ctx, chunk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchCallWithRetry 以JSON-RPC批量请求执行 elems，只对失败的调用重试
+// 返回错误表示批量请求本身无法完成；重试耗尽后仍失败的调用保留在 elem.Error 中
+func (p *ClientPool) BatchCallWithRetry(ctx context.Context, elems []rpc.BatchElem, options BatchCallOptions) error {
+	pending := make([]int, len(elems))
+	for i := range elems {
+		pending[i] = i
+	}
+
+	for attempt := 0; ; attempt++ {
+		batch := make([]rpc.BatchElem, len(pending))
+		for i, index := range pending {
+			batch[i] = elems[index]
+		}
+
+		if err := p.BatchCall(ctx, batch, options.MaxSize); err != nil {
+			return err
+		}
+
+		var failed []int
+		for i, index := range pending {
+			elem := &batch[i]
+			if elem.Error == nil && options.Validate != nil {
+				elem.Error = options.Validate(elem)
+			}
+			elems[index].Error = elem.Error
+			if elem.Error != nil {
+				failed = append(failed, index)
+			}
+		}
+
+		if len(failed) == 0 || attempt >= options.RetryAttempts {
+			return nil
+		}
+
+		p.logger.WithFields(logrus.Fields{
+			"failed":  len(failed),
+			"total":   len(elems),
+			"attempt": attempt + 1,
+			"error":   elems[failed[0]].Error,
+		}).Warn("Batch calls failed, retrying failed calls")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(options.RetryDelay * time.Duration(attempt+1)):
+		}
+		pending = failed
+	}
+}
+
+// rpcBlock 包含完整交易的区块响应中除区块头外的部分
+type rpcBlock struct {
+	Hash         *common.Hash         `json:"hash"`
+	Transactions []*types.Transaction `json:"transactions"`
+	UncleHashes  []common.Hash        `json:"uncles"`
+	Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+}
+
+// blockByNumberCall 构造按区块号获取区块（包含完整交易）的批量调用
+func blockByNumberCall(number *big.Int, result *json.RawMessage) rpc.BatchElem {
+	return rpc.BatchElem{
+		Method: "eth_getBlockByNumber",
+		Args:   []interface{}{hexutil.EncodeBig(number), true},
+		Result: result,
+	}
+}
+
+// requireRawResult 将 null 响应视为未找到
+func requireRawResult(elem *rpc.BatchElem) error {
+	raw := elem.Result.(*json.RawMessage)
+	if len(*raw) == 0 || string(*raw) == "null" {
+		return ethereum.NotFound
+	}
+	return nil
+}
+
+// decodeBlock 解析区块响应，返回区块与叔块哈希，叔块需另行获取
+func decodeBlock(raw json.RawMessage) (*types.Block, *rpcBlock, error) {
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, nil, err
+	}
+	if head == nil {
+		return nil, nil, ethereum.NotFound
+	}
+
+	var body rpcBlock
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, nil, err
+	}
+	if body.Hash == nil {
+		hash := head.Hash()
+		body.Hash = &hash
+	}
+
+	// 与 ethclient 相同的交易与叔块列表校验
+	if head.UncleHash == types.EmptyUncleHash && len(body.UncleHashes) > 0 {
+		return nil, nil, errors.New("server returned non-empty uncle list but block header indicates no uncles")
+	}
+	if head.UncleHash != types.EmptyUncleHash && len(body.UncleHashes) == 0 {
+		return nil, nil, errors.New("server returned empty uncle list but block header indicates uncles")
+	}
+	if head.TxHash == types.EmptyTxsHash && len(body.Transactions) > 0 {
+		return nil, nil, errors.New("server returned non-empty transaction list but block header indicates no transactions")
+	}
+	if head.TxHash != types.EmptyTxsHash && len(body.Transactions) == 0 {
+		return nil, nil, errors.New("server returned empty transaction list but block header indicates transactions")
+	}
+
+	block := types.NewBlockWithHeader(head).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	})
+	return block, &body, nil
+}
+
+// decodeTransaction 解析交易响应，所在区块号为空表示交易仍在 pending
+func decodeTransaction(raw json.RawMessage) (*types.Transaction, bool, error) {
+	var tx *types.Transaction
+	if err := json.Unmarshal(raw, &tx); err != nil {
+		return nil, false, err
+	}
+	if tx == nil {
+		return nil, false, ethereum.NotFound
+	}
+	if _, r, _ := tx.RawSignatureValues(); r == nil {
+		return nil, false, errors.New("server returned transaction without signature")
+	}
+
+	var extra struct {
+		BlockNumber *string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(raw, &extra); err != nil {
+		return nil, false, err
+	}
+	return tx, extra.BlockNumber == nil, nil
+}
+
+// fetchBlocksByNumber 以批量请求获取区块（包含完整交易与叔块），结果与 numbers 顺序一致
+// 重试耗尽后仍失败的区块在 errs 中返回对应错误
+func fetchBlocksByNumber(ctx context.Context, pool *ClientPool, numbers []*big.Int, options BatchCallOptions) ([]*types.Block, []error, error) {
+	raws := make([]json.RawMessage, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		elems[i] = blockByNumberCall(number, &raws[i])
+	}
+
+	options.Validate = requireRawResult
+	if err := pool.BatchCallWithRetry(ctx, elems, options); err != nil {
+		return nil, nil, err
+	}
+
+	blocks := make([]*types.Block, len(numbers))
+	bodies := make([]*rpcBlock, len(numbers))
+	errs := make([]error, len(numbers))
+	for i := range elems {
+		if elems[i].Error != nil {
+			errs[i] = elems[i].Error
+			continue
+		}
+		blocks[i], bodies[i], errs[i] = decodeBlock(raws[i])
+	}
+
+	if err := fetchUncles(ctx, pool, blocks, bodies, errs, options); err != nil {
+		return nil, nil, err
+	}
+	return blocks, errs, nil
+}
+
+// fetchUncles 以一次批量请求补齐所有区块的叔块
+func fetchUncles(ctx context.Context, pool *ClientPool, blocks []*types.Block, bodies []*rpcBlock, errs []error, options BatchCallOptions) error {
+	type uncleRef struct {
+		block int
+		index int
+	}
+
+	var (
+		refs  []uncleRef
+		elems []rpc.BatchElem
+	)
+	uncles := make([][]*types.Header, len(blocks))
+	for i, body := range bodies {
+		if body == nil || len(body.UncleHashes) == 0 {
+			continue
+		}
+		uncles[i] = make([]*types.Header, len(body.UncleHashes))
+		for j := range body.UncleHashes {
+			refs = append(refs, uncleRef{block: i, index: j})
+			elems = append(elems, rpc.BatchElem{
+				Method: "eth_getUncleByBlockHashAndIndex",
+				Args:   []interface{}{body.Hash, hexutil.EncodeUint64(uint64(j))},
+				Result: &uncles[i][j],
+			})
+		}
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+
+	options.Validate = func(elem *rpc.BatchElem) error {
+		if *elem.Result.(**types.Header) == nil {
+			return ethereum.NotFound
+		}
+		return nil
+	}
+	if err := pool.BatchCallWithRetry(ctx, elems, options); err != nil {
+		return err
+	}
+
+	for k, ref := range refs {
+		if elems[k].Error != nil && errs[ref.block] == nil {
+			errs[ref.block] = fmt.Errorf("failed to fetch uncle %d of block %s: %w",
+				ref.index, bodies[ref.block].Hash.Hex(), elems[k].Error)
+		}
+	}
+	for i, block := range blocks {
+		if uncles[i] != nil && errs[i] == nil {
+			blocks[i] = block.WithBody(types.Body{
+				Transactions: block.Transactions(),
+				Uncles:       uncles[i],
+				Withdrawals:  block.Withdrawals(),
+			})
+		}
+	}
+	return nil
+}
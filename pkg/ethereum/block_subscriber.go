@@ -3,6 +3,7 @@ package ethereum
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -19,6 +20,9 @@ type BlockSubscriberConfig struct {
 	RetryInterval     time.Duration `json:"retry_interval"`
 	EnableFiltering   bool          `json:"enable_filtering"`
 	BatchSize         int           `json:"batch_size"`
+	ReorgDepth        int           `json:"reorg_depth"` // 0 disables reorg detection
+	MaxCatchUpBlocks  int           `json:"max_catch_up_blocks"`
+	CatchUpTimeout    time.Duration `json:"catch_up_timeout"`
 }
 
 // DefaultBlockSubscriberConfig returns default configuration
@@ -31,6 +35,9 @@ func DefaultBlockSubscriberConfig() *BlockSubscriberConfig {
 		RetryInterval:     5 * time.Second,
 		EnableFiltering:   true,
 		BatchSize:         10,
+		ReorgDepth:        64,
+		MaxCatchUpBlocks:  1000,
+		CatchUpTimeout:    2 * time.Minute,
 	}
 }
 
@@ -41,8 +48,23 @@ type BlockEvent struct {
 	Timestamp time.Time        `json:"timestamp"`
 	Source    string           `json:"source"`
 	Processed bool             `json:"processed"`
+	Reorg     *ReorgEvent      `json:"reorg,omitempty"` // set on the first block of a new chain
+	
+	// ackOnly marks a filtered-out block that only advances the cursor;
+	// barrier is closed once every event queued before it has been handled
+	ackOnly   bool
+	barrier   chan struct{}
+}
+
+// BlockRangeFetcher fetches a contiguous block range; BlockService satisfies it
+type BlockRangeFetcher interface {
+	GetBlockRange(ctx context.Context, from, to *big.Int, options *BlockSyncOptions) ([]*types.Block, error)
 }
 
+// LastBlockFunc returns the last block already handled downstream (e.g. persisted),
+// with ok=false when nothing has been handled yet
+type LastBlockFunc func(ctx context.Context) (number uint64, ok bool, err error)
+
 // BlockEventHandler defines the interface for handling block events
 type BlockEventHandler interface {
 	HandleBlock(event *BlockEvent) error
@@ -56,6 +78,15 @@ type BlockSubscriber struct {
 	subscriptionMgr   *SubscriptionManager
 	eventFilter       *EventFilter
 	subscription      *Subscription
+	chainTracker      *ChainTracker
+	pendingReorg      *ReorgEvent
+	
+	// Gap catch-up; cursor is the last block of the contiguous run every handler acknowledged
+	rangeFetcher      BlockRangeFetcher
+	lastBlockFunc     LastBlockFunc
+	cursor            uint64
+	hasCursor         bool
+	cursorMutex       sync.Mutex
 	
 	// Event handling
 	handlers          []BlockEventHandler
@@ -93,6 +124,9 @@ type BlockSubscriberStats struct {
 	LastBlockNumber     uint64        `json:"last_block_number"`
 	LastBlockHash       string        `json:"last_block_hash"`
 	FilterMatches       int64         `json:"filter_matches"`
+	ReorgsDetected      int64         `json:"reorgs_detected"`
+	BlocksCaughtUp      int64         `json:"blocks_caught_up"`
+	LastReorgDepth      int           `json:"last_reorg_depth"`
 	HandlerCount        int           `json:"handler_count"`
 	TotalUptime         time.Duration `json:"total_uptime"`
 }
@@ -105,10 +139,16 @@ func NewBlockSubscriber(config *BlockSubscriberConfig, subscriptionMgr *Subscrip
 	
 	ctx, cancel := context.WithCancel(context.Background())
 	
+	var tracker *ChainTracker
+	if config.ReorgDepth > 0 {
+		tracker = NewChainTracker(config.ReorgDepth, nil)
+	}
+	
 	return &BlockSubscriber{
 		config:          config,
 		subscriptionMgr: subscriptionMgr,
 		eventFilter:     eventFilter,
+		chainTracker:    tracker,
 		blockEvents:     make(chan *BlockEvent, config.BufferSize),
 		processedEvents: make(chan *BlockEvent, config.BufferSize),
 		errorEvents:     make(chan error, 100),
@@ -273,10 +313,151 @@ func (bs *BlockSubscriber) subscriptionProcessor() {
 	}
 }
 
-// processBlockHeader processes a new block header
+// processBlockHeader processes a header received from the subscription,
+// first catching up on any blocks missed since the last acknowledged one
 func (bs *BlockSubscriber) processBlockHeader(header *types.Header) {
+	if bs.rangeFetcher != nil {
+		bs.catchUp(header)
+	}
+	bs.processHeader(header, "subscription")
+}
+
+// catchUp handles the blocks between the last acknowledged block and header.
+// Queued events are drained first so the cursor covers every block already
+// handed to handlers, and the missed blocks are handled before returning.
+func (bs *BlockSubscriber) catchUp(header *types.Header) {
+	if !bs.drain() {
+		return
+	}
+	
+	last, ok := bs.getCursor()
+	if !ok && bs.lastBlockFunc != nil {
+		ctx, cancel := context.WithTimeout(bs.ctx, bs.config.ProcessingTimeout)
+		var err error
+		last, ok, err = bs.lastBlockFunc(ctx)
+		cancel()
+		if err != nil {
+			bs.logger.WithError(err).Warn("Failed to get last handled block, skipping catch-up")
+			return
+		}
+		if ok {
+			bs.setCursor(last)
+		}
+	}
+	
+	number := header.Number.Uint64()
+	if !ok || number <= last+1 {
+		return
+	}
+	
+	from, to := last+1, number-1
+	if limit := uint64(bs.config.MaxCatchUpBlocks); limit > 0 && to-from+1 > limit {
+		bs.logger.WithFields(logrus.Fields{
+			"from":    from,
+			"to":      to,
+			"max":     limit,
+			"skipped": to - from + 1 - limit,
+		}).Warn("Block gap exceeds catch-up limit, older blocks must be backfilled")
+		from = to - limit + 1
+		bs.setCursor(from - 1)
+	}
+	
+	bs.logger.WithFields(logrus.Fields{
+		"from": from,
+		"to":   to,
+	}).Info("Catching up on missed blocks")
+	
+	ctx, cancel := context.WithTimeout(bs.ctx, bs.config.CatchUpTimeout)
+	blocks, err := bs.rangeFetcher.GetBlockRange(ctx, new(big.Int).SetUint64(from), new(big.Int).SetUint64(to), nil)
+	cancel()
+	if err != nil {
+		err = fmt.Errorf("failed to catch up on blocks %d-%d: %v", from, to, err)
+		bs.logger.WithError(err).Error("Block catch-up failed")
+		select {
+		case bs.errorEvents <- err:
+		default:
+		}
+		return
+	}
+	
+	for _, block := range blocks {
+		bs.processHeader(block.Header(), "catchup")
+	}
+	bs.drain()
+	
+	bs.statsMutex.Lock()
+	bs.stats.BlocksCaughtUp += int64(len(blocks))
+	bs.statsMutex.Unlock()
+}
+
+// drain waits until every queued event has been handled, false if stopped first
+func (bs *BlockSubscriber) drain() bool {
+	barrier := make(chan struct{})
+	select {
+	case bs.blockEvents <- &BlockEvent{barrier: barrier}:
+	case <-bs.ctx.Done():
+		return false
+	}
+	
+	select {
+	case <-barrier:
+		return true
+	case <-bs.ctx.Done():
+		return false
+	}
+}
+
+// getCursor returns the last acknowledged block
+func (bs *BlockSubscriber) getCursor() (uint64, bool) {
+	bs.cursorMutex.Lock()
+	defer bs.cursorMutex.Unlock()
+	return bs.cursor, bs.hasCursor
+}
+
+// setCursor moves the cursor to number, e.g. from the persisted head
+func (bs *BlockSubscriber) setCursor(number uint64) {
+	bs.cursorMutex.Lock()
+	defer bs.cursorMutex.Unlock()
+	bs.cursor, bs.hasCursor = number, true
+}
+
+// advanceCursor records that every handler acknowledged block number. The
+// cursor only moves onto the next block, so a failed or dropped block holds it
+// back and is handled again by the next catch-up.
+func (bs *BlockSubscriber) advanceCursor(number uint64) {
+	bs.cursorMutex.Lock()
+	defer bs.cursorMutex.Unlock()
+	if !bs.hasCursor || number == bs.cursor+1 {
+		bs.cursor, bs.hasCursor = number, true
+	}
+}
+
+// processHeader runs a header through reorg detection and filtering and queues it
+func (bs *BlockSubscriber) processHeader(header *types.Header, source string) {
 	startTime := time.Now()
 	
+	// Check the header against the tracked canonical chain
+	if bs.chainTracker != nil {
+		ctx, cancel := context.WithTimeout(bs.ctx, bs.config.ProcessingTimeout)
+		reorg, ok, err := bs.chainTracker.Process(ctx, header)
+		cancel()
+		if err != nil {
+			bs.logger.WithError(err).WithField("block", header.Number).Error("Failed to resolve chain reorganization")
+			select {
+			case bs.errorEvents <- err:
+			default:
+			}
+		}
+		// Caught-up blocks may already be tracked when an earlier attempt failed
+		if !ok && source != "catchup" {
+			bs.logger.WithField("block", header.Number).Debug("Duplicate block header ignored")
+			return
+		}
+		if reorg != nil {
+			bs.processReorg(reorg)
+		}
+	}
+	
 	// Update stats
 	bs.statsMutex.Lock()
 	bs.stats.BlocksReceived++
@@ -289,9 +470,11 @@ func (bs *BlockSubscriber) processBlockHeader(header *types.Header) {
 	event := &BlockEvent{
 		Header:    header,
 		Timestamp: time.Now(),
-		Source:    "subscription",
+		Source:    source,
 		Processed: false,
+		Reorg:     bs.pendingReorg,
 	}
+	bs.pendingReorg = nil
 	
 	// Apply filters if enabled
 	if bs.config.EnableFiltering && bs.eventFilter != nil {
@@ -301,24 +484,32 @@ func (bs *BlockSubscriber) processBlockHeader(header *types.Header) {
 			bs.statsMutex.Lock()
 			bs.stats.FilterMatches += int64(len(matches))
 			bs.statsMutex.Unlock()
-		} else {
+		} else if event.Reorg == nil {
 			bs.statsMutex.Lock()
 			bs.stats.BlocksFiltered++
 			bs.statsMutex.Unlock()
 			
-			// Skip processing if no matches and filtering is strict
-			return
+			// Skip processing if no matches and filtering is strict, the
+			// block is still acknowledged in order behind queued events
+			event.ackOnly = true
 		}
 	}
 	
-	// Send to processing channel
-	select {
-	case bs.blockEvents <- event:
-	default:
-		bs.logger.Warn("Block events channel full, dropping block")
-		bs.statsMutex.Lock()
-		bs.stats.ProcessingErrors++
-		bs.statsMutex.Unlock()
+	// Send to processing channel; caught-up blocks must not be dropped
+	if source == "subscription" {
+		select {
+		case bs.blockEvents <- event:
+		default:
+			bs.logger.Warn("Block events channel full, dropping block")
+			bs.statsMutex.Lock()
+			bs.stats.ProcessingErrors++
+			bs.statsMutex.Unlock()
+		}
+	} else {
+		select {
+		case bs.blockEvents <- event:
+		case <-bs.ctx.Done():
+		}
 	}
 	
 	// Update processing time
@@ -332,6 +523,44 @@ func (bs *BlockSubscriber) processBlockHeader(header *types.Header) {
 	bs.statsMutex.Unlock()
 }
 
+// processReorg queues the replacement blocks preceding the new head; the
+// first of them carries the reorg so handlers can undo orphaned blocks first
+func (bs *BlockSubscriber) processReorg(reorg *ReorgEvent) {
+	bs.statsMutex.Lock()
+	bs.stats.ReorgsDetected++
+	bs.stats.LastReorgDepth = reorg.Depth
+	bs.statsMutex.Unlock()
+	
+	bs.logger.WithFields(logrus.Fields{
+		"ancestor":   reorg.CommonAncestor.Number,
+		"depth":      reorg.Depth,
+		"new_blocks": len(reorg.NewBlocks),
+	}).Warn("Chain reorganization detected")
+	
+	// The new head itself goes through the regular path
+	replaced := reorg.NewBlocks[:len(reorg.NewBlocks)-1]
+	if len(replaced) == 0 {
+		bs.pendingReorg = reorg
+		return
+	}
+	
+	for i, header := range replaced {
+		event := &BlockEvent{
+			Header:    header,
+			Timestamp: time.Now(),
+			Source:    "reorg",
+		}
+		if i == 0 {
+			event.Reorg = reorg
+		}
+		select {
+		case bs.blockEvents <- event:
+		case <-bs.ctx.Done():
+			return
+		}
+	}
+}
+
 // eventProcessor processes block events through handlers
 func (bs *BlockSubscriber) eventProcessor() {
 	defer bs.logger.Info("Event processor stopped")
@@ -341,71 +570,66 @@ func (bs *BlockSubscriber) eventProcessor() {
 		case <-bs.ctx.Done():
 			return
 		case event := <-bs.blockEvents:
-			bs.processEvent(event)
+			switch {
+			case event.barrier != nil:
+				close(event.barrier)
+			case event.ackOnly:
+				bs.advanceCursor(event.Header.Number.Uint64())
+			default:
+				bs.processEvent(event)
+			}
 		}
 	}
 }
 
-// processEvent processes a single block event
+// processEvent processes a single block event. Handlers run to completion
+// before the next event so they never overlap; exceeding ProcessingTimeout
+// is only reported.
 func (bs *BlockSubscriber) processEvent(event *BlockEvent) {
-	defer func() {
-		if r := recover(); r != nil {
-			bs.logger.WithField("panic", r).Error("Panic in event processing")
-			bs.statsMutex.Lock()
-			bs.stats.ProcessingErrors++
-			bs.statsMutex.Unlock()
-		}
-	}()
+	startTime := time.Now()
+	acked := bs.executeHandlers(event)
 	
-	// Process with timeout
-	ctx, cancel := context.WithTimeout(bs.ctx, bs.config.ProcessingTimeout)
-	defer cancel()
+	if elapsed := time.Since(startTime); elapsed > bs.config.ProcessingTimeout {
+		bs.logger.WithFields(logrus.Fields{
+			"block":   event.Header.Number,
+			"elapsed": elapsed,
+		}).Warn("Event processing exceeded timeout")
+	}
 	
-	done := make(chan bool, 1)
+	event.Processed = true
+	bs.statsMutex.Lock()
+	bs.stats.BlocksProcessed++
+	if !acked {
+		bs.stats.ProcessingErrors++
+	}
+	bs.statsMutex.Unlock()
 	
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				bs.logger.WithField("panic", r).Error("Panic in handler execution")
-			}
-			done <- true
-		}()
-		
-		bs.executeHandlers(event)
-	}()
+	if acked {
+		bs.advanceCursor(event.Header.Number.Uint64())
+	}
 	
+	// Send to processed events channel
 	select {
-	case <-ctx.Done():
-		bs.logger.WithField("block", event.Header.Number).Warn("Event processing timeout")
-		bs.statsMutex.Lock()
-		bs.stats.ProcessingErrors++
-		bs.statsMutex.Unlock()
-	case <-done:
-		event.Processed = true
-		bs.statsMutex.Lock()
-		bs.stats.BlocksProcessed++
-		bs.statsMutex.Unlock()
-		
-		// Send to processed events channel
-		select {
-		case bs.processedEvents <- event:
-		default:
-			bs.logger.Warn("Processed events channel full, dropping event")
-		}
+	case bs.processedEvents <- event:
+	default:
+		bs.logger.Warn("Processed events channel full, dropping event")
 	}
 }
 
-// executeHandlers executes all registered handlers for an event
-func (bs *BlockSubscriber) executeHandlers(event *BlockEvent) {
+// executeHandlers executes all registered handlers for an event and reports
+// whether every handler succeeded
+func (bs *BlockSubscriber) executeHandlers(event *BlockEvent) bool {
 	bs.handlersMutex.RLock()
 	handlers := make([]BlockEventHandler, len(bs.handlers))
 	copy(handlers, bs.handlers)
 	bs.handlersMutex.RUnlock()
 	
+	acked := true
 	for _, handler := range handlers {
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
+					acked = false
 					bs.logger.WithFields(logrus.Fields{
 						"handler": handler.GetName(),
 						"panic":   r,
@@ -413,7 +637,22 @@ func (bs *BlockSubscriber) executeHandlers(event *BlockEvent) {
 				}
 			}()
 			
+			if event.Reorg != nil {
+				if reorgHandler, ok := handler.(ReorgEventHandler); ok {
+					if err := reorgHandler.HandleReorg(event.Reorg); err != nil {
+						acked = false
+						bs.logger.WithFields(logrus.Fields{
+							"handler": handler.GetName(),
+							"error":   err,
+						}).Error("Reorg handler error")
+						
+						handler.HandleError(err)
+					}
+				}
+			}
+			
 			if err := handler.HandleBlock(event); err != nil {
+				acked = false
 				bs.logger.WithFields(logrus.Fields{
 					"handler": handler.GetName(),
 					"error":   err,
@@ -428,6 +667,7 @@ func (bs *BlockSubscriber) executeHandlers(event *BlockEvent) {
 			}
 		}()
 	}
+	return acked
 }
 
 // errorProcessor processes error events
@@ -499,6 +739,25 @@ func (bs *BlockSubscriber) SetFilter(filter *EventFilter) {
 	bs.logger.Info("Event filter updated")
 }
 
+// SetBlockFetcher enables walking back unknown parents during reorg detection
+func (bs *BlockSubscriber) SetBlockFetcher(fetcher BlockFetcher) {
+	if bs.chainTracker != nil {
+		bs.chainTracker.SetFetcher(fetcher)
+	}
+}
+
+// SetCatchUpSource enables gap catch-up. fetcher loads missed blocks and
+// lastBlock supplies the persisted head used as the cursor until a block is acknowledged.
+func (bs *BlockSubscriber) SetCatchUpSource(fetcher BlockRangeFetcher, lastBlock LastBlockFunc) {
+	bs.rangeFetcher = fetcher
+	bs.lastBlockFunc = lastBlock
+}
+
+// GetChainTracker returns the canonical chain tracker, nil if reorg detection is disabled
+func (bs *BlockSubscriber) GetChainTracker() *ChainTracker {
+	return bs.chainTracker
+}
+
 // GetHandlers returns a copy of all registered handlers
 func (bs *BlockSubscriber) GetHandlers() []BlockEventHandler {
 	bs.handlersMutex.RLock()
@@ -52,6 +52,8 @@ type BlockSyncOptions struct {
 	IncludeUncles bool `json:"include_uncles"`
 	// 是否验证完整性
 	VerifyIntegrity bool `json:"verify_integrity"`
+	// 单次JSON-RPC批量请求的最大调用数，0表示使用 DefaultRPCBatchSize
+	RPCBatchSize int `json:"rpc_batch_size"`
 }
 
 // NewBlockService 创建新的区块数据服务
@@ -78,15 +80,9 @@ func (bs *BlockService) GetBlockByNumber(ctx context.Context, number *big.Int) (
 
 // GetBlockByHash 根据区块哈希获取区块
 func (bs *BlockService) GetBlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	var block *types.Block
-
-	err := bs.pool.ExecuteWithFailover(ctx, func(client *Client) error {
-		var err error
-		block, err = client.GetBlockByHash(ctx, hash)
-		return err
+	return ExecuteRead(ctx, bs.pool, func(ctx context.Context, client *Client) (*types.Block, error) {
+		return client.GetBlockByHash(ctx, hash)
 	})
-
-	return block, err
 }
 
 // GetBlockRange 获取区块范围
@@ -217,50 +213,34 @@ func (bs *BlockService) fetchBatchesConcurrently(ctx context.Context, batches []
 	return allBlocks, nil
 }
 
-// fetchBatch 获取单个批次
+// fetchBatch 以一次JSON-RPC批量请求获取单个批次，只重试失败的区块
 func (bs *BlockService) fetchBatch(ctx context.Context, blockRange *BlockRange, options *BlockSyncOptions) *BlockBatch {
 	result := &BlockBatch{
 		Range: blockRange,
 	}
 
-	var blocks []*types.Block
-	current := new(big.Int).Set(blockRange.From)
-
-	for current.Cmp(blockRange.To) <= 0 {
-		var block *types.Block
-		var err error
-
-		// 重试机制
-		for attempt := 0; attempt <= options.RetryAttempts; attempt++ {
-			block, err = bs.pool.GetBlockByNumber(ctx, current)
-			if err == nil {
-				break
-			}
+	var numbers []*big.Int
+	for n := new(big.Int).Set(blockRange.From); n.Cmp(blockRange.To) <= 0; n = new(big.Int).Add(n, big.NewInt(1)) {
+		numbers = append(numbers, n)
+	}
 
-			if attempt < options.RetryAttempts {
-				bs.logger.WithFields(logrus.Fields{
-					"block_number": current.String(),
-					"attempt":      attempt + 1,
-					"error":        err,
-				}).Warn("Failed to fetch block, retrying")
-
-				select {
-				case <-ctx.Done():
-					result.Error = ctx.Err()
-					return result
-				case <-time.After(options.RetryDelay * time.Duration(attempt+1)):
-				}
-			}
-		}
+	blocks, errs, err := fetchBlocksByNumber(ctx, bs.pool, numbers, BatchCallOptions{
+		MaxSize:       options.RPCBatchSize,
+		RetryAttempts: options.RetryAttempts,
+		RetryDelay:    options.RetryDelay,
+	})
+	if err != nil {
+		result.Error = fmt.Errorf("failed to fetch blocks %s-%s: %w",
+			blockRange.From.String(), blockRange.To.String(), err)
+		return result
+	}
 
+	for i, err := range errs {
 		if err != nil {
 			result.Error = fmt.Errorf("failed to fetch block %s after %d attempts: %w",
-				current.String(), options.RetryAttempts+1, err)
+				numbers[i].String(), options.RetryAttempts+1, err)
 			return result
 		}
-
-		blocks = append(blocks, block)
-		current.Add(current, big.NewInt(1))
 	}
 
 	result.Blocks = blocks
@@ -359,24 +339,19 @@ func (bs *BlockService) GetBlocksWithTransactions(ctx context.Context, numbers [
 
 // GetLatestBlockNumber 获取最新区块号
 func (bs *BlockService) GetLatestBlockNumber(ctx context.Context) (*big.Int, error) {
-	var blockNumber *big.Int
-
-	err := bs.pool.ExecuteWithFailover(ctx, func(client *Client) error {
+	return ExecuteRead(ctx, bs.pool, func(ctx context.Context, client *Client) (*big.Int, error) {
 		ethClient := client.GetEthClient()
 		if ethClient == nil {
-			return fmt.Errorf("eth client is nil")
+			return nil, fmt.Errorf("eth client is nil")
 		}
 
 		number, err := ethClient.BlockNumber(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		blockNumber = big.NewInt(int64(number))
-		return nil
+		return big.NewInt(int64(number)), nil
 	})
-
-	return blockNumber, err
 }
 
 // IsBlockExists 检查区块是否存在
@@ -0,0 +1,205 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrReorgTooDeep is returned when no common ancestor is found within the tracked depth
+var ErrReorgTooDeep = errors.New("reorg deeper than tracked chain")
+
+// BlockFetcher fetches blocks by hash; BlockService satisfies it
+type BlockFetcher interface {
+	GetBlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+}
+
+// ReorgEvent describes a chain reorganization
+type ReorgEvent struct {
+	// CommonAncestor is the last block shared by the old and new chains
+	CommonAncestor *types.Header `json:"common_ancestor"`
+	// OrphanedBlocks are the blocks removed from the canonical chain, in ascending order
+	OrphanedBlocks []*types.Header `json:"orphaned_blocks"`
+	// NewBlocks are the blocks that replaced them, in ascending order, ending with the new head
+	NewBlocks []*types.Header `json:"new_blocks"`
+	// Depth is the number of orphaned blocks
+	Depth     int       `json:"depth"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ReorgEventHandler is implemented by block handlers that need to undo work for orphaned blocks.
+// HandleReorg is called before HandleBlock for the first block of the new chain.
+type ReorgEventHandler interface {
+	HandleReorg(event *ReorgEvent) error
+}
+
+// ChainTracker keeps the most recent canonical headers and detects reorganizations
+type ChainTracker struct {
+	depth   int
+	fetcher BlockFetcher
+
+	// canonical headers in ascending order
+	headers []*types.Header
+	mutex   sync.Mutex
+}
+
+// NewChainTracker creates a tracker remembering up to depth headers.
+// fetcher is used to walk back unknown parents; without it only reorgs whose
+// parent is already tracked can be resolved.
+func NewChainTracker(depth int, fetcher BlockFetcher) *ChainTracker {
+	if depth <= 0 {
+		depth = 64
+	}
+	return &ChainTracker{
+		depth:   depth,
+		fetcher: fetcher,
+		headers: make([]*types.Header, 0, depth),
+	}
+}
+
+// SetFetcher sets the block fetcher used to walk back the new chain
+func (ct *ChainTracker) SetFetcher(fetcher BlockFetcher) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+	ct.fetcher = fetcher
+}
+
+// Head returns the current canonical head, or nil if nothing is tracked
+func (ct *ChainTracker) Head() *types.Header {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+	if len(ct.headers) == 0 {
+		return nil
+	}
+	return ct.headers[len(ct.headers)-1]
+}
+
+// Process records a new head. It returns a ReorgEvent if the header does not
+// extend the current head, and ok=false if the header is already canonical.
+func (ct *ChainTracker) Process(ctx context.Context, header *types.Header) (reorg *ReorgEvent, ok bool, err error) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	if len(ct.headers) == 0 {
+		ct.append(header)
+		return nil, true, nil
+	}
+
+	head := ct.headers[len(ct.headers)-1]
+	number := header.Number.Uint64()
+
+	// Common case: the header extends the current head
+	if header.ParentHash == head.Hash() {
+		ct.append(header)
+		return nil, true, nil
+	}
+
+	// Duplicate notification for a block we already consider canonical
+	if known := ct.find(number); known != nil && known.Hash() == header.Hash() {
+		return nil, false, nil
+	}
+
+	// Gap after the head: parent cannot be verified against tracked state
+	if number > head.Number.Uint64()+1 {
+		ct.append(header)
+		return nil, true, nil
+	}
+
+	reorg, err = ct.resolve(ctx, header)
+	if err != nil {
+		// Start over from the new head so later blocks are tracked again
+		ct.headers = ct.headers[:0]
+		ct.append(header)
+		return nil, true, err
+	}
+	return reorg, true, nil
+}
+
+// resolve walks back from header until it reaches a tracked canonical block
+func (ct *ChainTracker) resolve(ctx context.Context, header *types.Header) (*ReorgEvent, error) {
+	newBlocks := []*types.Header{header}
+	current := header
+
+	for i := 0; i < ct.depth; i++ {
+		if current.Number.Sign() == 0 {
+			break
+		}
+		parentNumber := current.Number.Uint64() - 1
+
+		if ancestor := ct.find(parentNumber); ancestor != nil && ancestor.Hash() == current.ParentHash {
+			orphaned := ct.truncateAfter(parentNumber)
+			reverse(newBlocks)
+			for _, h := range newBlocks {
+				ct.append(h)
+			}
+			return &ReorgEvent{
+				CommonAncestor: ancestor,
+				OrphanedBlocks: orphaned,
+				NewBlocks:      newBlocks,
+				Depth:          len(orphaned),
+				Timestamp:      time.Now(),
+			}, nil
+		}
+
+		if oldest := ct.headers[0]; parentNumber < oldest.Number.Uint64() {
+			break
+		}
+		if ct.fetcher == nil {
+			return nil, fmt.Errorf("cannot walk back past block %d without a block fetcher", current.Number.Uint64())
+		}
+
+		parent, err := ct.fetcher.GetBlockByHash(ctx, current.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch parent block %s: %w", current.ParentHash.Hex(), err)
+		}
+		current = parent.Header()
+		newBlocks = append(newBlocks, current)
+	}
+
+	return nil, fmt.Errorf("%w: head %d, depth %d", ErrReorgTooDeep, header.Number.Uint64(), ct.depth)
+}
+
+// find returns the tracked header at number
+func (ct *ChainTracker) find(number uint64) *types.Header {
+	for i := len(ct.headers) - 1; i >= 0; i-- {
+		n := ct.headers[i].Number.Uint64()
+		if n == number {
+			return ct.headers[i]
+		}
+		if n < number {
+			break
+		}
+	}
+	return nil
+}
+
+// truncateAfter removes and returns tracked headers above number
+func (ct *ChainTracker) truncateAfter(number uint64) []*types.Header {
+	i := len(ct.headers)
+	for i > 0 && ct.headers[i-1].Number.Uint64() > number {
+		i--
+	}
+	removed := make([]*types.Header, len(ct.headers)-i)
+	copy(removed, ct.headers[i:])
+	ct.headers = ct.headers[:i]
+	return removed
+}
+
+// append adds a header and drops the oldest beyond depth
+func (ct *ChainTracker) append(header *types.Header) {
+	ct.headers = append(ct.headers, header)
+	if over := len(ct.headers) - ct.depth; over > 0 {
+		ct.headers = append(ct.headers[:0], ct.headers[over:]...)
+	}
+}
+
+func reverse(headers []*types.Header) {
+	for i, j := 0, len(headers)-1; i < j; i, j = i+1, j-1 {
+		headers[i], headers[j] = headers[j], headers[i]
+	}
+}
@@ -74,6 +74,18 @@ type Client struct {
 	requestCount int64
 	// errorCount: 错误次数
 	errorCount int64
+	// batchDisabled: 节点拒绝过批量请求，之后逐个调用
+	batchDisabled bool
+	// latencyEWMA: 成功请求延迟的指数加权平均（纳秒）
+	latencyEWMA float64
+	// errorEWMA: 请求错误率的指数加权平均
+	errorEWMA float64
+	// samples: 已记录的请求结果数
+	samples int64
+	// headLag: 最近一次健康检查时落后于连接池最高区块的区块数
+	headLag uint64
+	// degraded: 落后过多被降级，不参与请求分配
+	degraded bool
 }
 
 // ClientStats 客户端统计信息
@@ -98,6 +110,18 @@ type ClientStats struct {
 	Uptime time.Duration `json:"uptime"`
 	// ErrorRate: 错误率
 	ErrorRate float64 `json:"error_rate"`
+	// BatchSupported: 是否支持JSON-RPC批量请求
+	BatchSupported bool `json:"batch_supported"`
+	// LatencyEWMA: 请求延迟的指数加权平均
+	LatencyEWMA time.Duration `json:"latency_ewma"`
+	// ErrorRateEWMA: 请求错误率的指数加权平均
+	ErrorRateEWMA float64 `json:"error_rate_ewma"`
+	// Score: 延迟感知评分，越低越优先
+	Score float64 `json:"score"`
+	// HeadLag: 落后于连接池最高区块的区块数
+	HeadLag uint64 `json:"head_lag"`
+	// Degraded: 是否因落后过多被降级
+	Degraded bool `json:"degraded"`
 }
 
 // NewClient 创建新的以太坊客户端
@@ -242,19 +266,42 @@ func (c *Client) IsHealthy() bool {
 	return c.isHealthy
 }
 
+// IsDegraded 检查客户端是否因落后过多被降级
+func (c *Client) IsDegraded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.degraded
+}
+
+// setHeadLag 更新落后区块数与降级状态，返回降级状态是否变化
+func (c *Client) setHeadLag(lag uint64, degraded bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := c.degraded != degraded
+	c.headLag = lag
+	c.degraded = degraded
+	return changed
+}
+
 // GetStats 获取客户端统计信息
 func (c *Client) GetStats() ClientStats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	stats := ClientStats{
-		URL:          c.config.URL,
-		Type:         c.config.Type,
-		IsHealthy:    c.isHealthy,
-		LastCheck:    c.lastCheck,
-		ConnectedAt:  c.connectedAt,
-		RequestCount: c.requestCount,
-		ErrorCount:   c.errorCount,
+		URL:            c.config.URL,
+		Type:           c.config.Type,
+		IsHealthy:      c.isHealthy,
+		LastCheck:      c.lastCheck,
+		ConnectedAt:    c.connectedAt,
+		RequestCount:   c.requestCount,
+		ErrorCount:     c.errorCount,
+		BatchSupported: !c.batchDisabled,
+		LatencyEWMA:    time.Duration(c.latencyEWMA),
+		ErrorRateEWMA:  c.errorEWMA,
+		Score:          c.score(),
+		HeadLag:        c.headLag,
+		Degraded:       c.degraded,
 	}
 
 	if c.lastError != nil {
@@ -475,3 +522,12 @@ func ValidateURL(rawURL string) error {
 
 	return nil
 }
+
+// EndpointName 返回节点URL的主机部分，用于日志之外的展示（指标、告警），避免暴露路径中的API Key
+func EndpointName(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return "unknown"
+	}
+	return parsedURL.Host
+}
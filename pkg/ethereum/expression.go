@@ -0,0 +1,270 @@
+package ethereum
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
+)
+
+// ExpressionEnv declares the typed fields that custom filter and alert expressions may reference.
+// Amounts and gas prices are in wei, so units apply directly: tx.gas_price > 50 gwei.
+// Numbers are float64: wei amounts above 2^53 keep about 16 significant digits,
+// so a tx.value comparison can be off by about 1 part in 10^16 of the amount
+// (under 1 gwei for amounts below a million ether) rather than exact to the wei.
+// Addresses and hashes are hex strings and compare case-insensitively.
+// Decoded ABI arguments (tx.args.*, log.args.*) are typed at runtime: integers are numbers
+// in the token's smallest unit, addresses and bytes are hex strings.
+var ExpressionEnv = expr.NewEnv(map[string]expr.Type{
+	// Transaction fields
+	"tx.hash":                 expr.TypeString,
+	"tx.from":                 expr.TypeString,
+	"tx.to":                   expr.TypeString,
+	"tx.value":                expr.TypeNumber,
+	"tx.gas":                  expr.TypeNumber,
+	"tx.gas_price":            expr.TypeNumber,
+	"tx.gas_tip_cap":          expr.TypeNumber,
+	"tx.gas_fee_cap":          expr.TypeNumber,
+	"tx.nonce":                expr.TypeNumber,
+	"tx.type":                 expr.TypeNumber,
+	"tx.method":               expr.TypeString,
+	"tx.input_size":           expr.TypeNumber,
+	"tx.is_contract_creation": expr.TypeBool,
+	"tx.method_name":          expr.TypeString,
+	"tx.args.*":               expr.TypeAny,
+
+	// Block fields
+	"block.number":          expr.TypeNumber,
+	"block.hash":            expr.TypeString,
+	"block.parent_hash":     expr.TypeString,
+	"block.miner":           expr.TypeString,
+	"block.gas_used":        expr.TypeNumber,
+	"block.gas_limit":       expr.TypeNumber,
+	"block.gas_utilization": expr.TypeNumber,
+	"block.base_fee":        expr.TypeNumber,
+	"block.timestamp":       expr.TypeNumber,
+
+	// Log fields
+	"log.address":      expr.TypeString,
+	"log.topics":       expr.TypeList,
+	"log.topic0":       expr.TypeString,
+	"log.topic1":       expr.TypeString,
+	"log.topic2":       expr.TypeString,
+	"log.topic3":       expr.TypeString,
+	"log.data_size":    expr.TypeNumber,
+	"log.block_number": expr.TypeNumber,
+	"log.tx_hash":      expr.TypeString,
+	"log.index":        expr.TypeNumber,
+	"log.removed":      expr.TypeBool,
+	"log.event":        expr.TypeString,
+	"log.signature":    expr.TypeString,
+	"log.standard":     expr.TypeString,
+	"log.args.*":       expr.TypeAny,
+
+	// Gas price sample fields
+	"gas.standard": expr.TypeNumber,
+	"gas.fast":     expr.TypeNumber,
+	"gas.instant":  expr.TypeNumber,
+	"gas.base_fee": expr.TypeNumber,
+	"gas.priority": expr.TypeNumber,
+})
+
+// CompileExpression compiles an expression against ExpressionEnv
+func CompileExpression(source string) (*expr.Program, error) {
+	return expr.Compile(source, ExpressionEnv)
+}
+
+// txVars exposes transaction fields to expressions; the sender is recovered lazily
+type txVars struct {
+	tx     *types.Transaction
+	signer types.Signer
+	call   *DecodedCall
+
+	fromOnce sync.Once
+	from     string
+}
+
+// TransactionVars returns expression fields for a transaction.
+// A nil signer derives one from the transaction's chain ID; call is the decoded input, if any.
+func TransactionVars(tx *types.Transaction, signer types.Signer, call *DecodedCall) expr.Vars {
+	if signer == nil {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	return &txVars{tx: tx, signer: signer, call: call}
+}
+
+// Lookup implements expr.Vars
+func (v *txVars) Lookup(name string) (interface{}, bool) {
+	tx := v.tx
+	switch name {
+	case "tx.hash":
+		return tx.Hash().Hex(), true
+	case "tx.from":
+		v.fromOnce.Do(func() {
+			if from, err := types.Sender(v.signer, tx); err == nil {
+				v.from = from.Hex()
+			}
+		})
+		return v.from, v.from != ""
+	case "tx.to":
+		if tx.To() == nil {
+			return nil, false
+		}
+		return tx.To().Hex(), true
+	case "tx.value":
+		return tx.Value(), true
+	case "tx.gas":
+		return tx.Gas(), true
+	case "tx.gas_price":
+		return tx.GasPrice(), true
+	case "tx.gas_tip_cap":
+		return tx.GasTipCap(), true
+	case "tx.gas_fee_cap":
+		return tx.GasFeeCap(), true
+	case "tx.nonce":
+		return tx.Nonce(), true
+	case "tx.type":
+		return uint64(tx.Type()), true
+	case "tx.method":
+		if len(tx.Data()) < 4 {
+			return nil, false
+		}
+		return hexutil.Encode(tx.Data()[:4]), true
+	case "tx.input_size":
+		return len(tx.Data()), true
+	case "tx.is_contract_creation":
+		return tx.To() == nil, true
+	case "tx.method_name":
+		if v.call == nil {
+			return nil, false
+		}
+		return v.call.Name, true
+	}
+	if name, ok := strings.CutPrefix(name, "tx.args."); ok && v.call != nil {
+		value, exists := v.call.Args[name]
+		return value, exists
+	}
+	return nil, false
+}
+
+// headerVars exposes block header fields to expressions
+type headerVars struct {
+	header *types.Header
+}
+
+// BlockVars returns expression fields for a block header
+func BlockVars(header *types.Header) expr.Vars {
+	return headerVars{header: header}
+}
+
+// Lookup implements expr.Vars
+func (v headerVars) Lookup(name string) (interface{}, bool) {
+	h := v.header
+	switch name {
+	case "block.number":
+		return h.Number, true
+	case "block.hash":
+		return h.Hash().Hex(), true
+	case "block.parent_hash":
+		return h.ParentHash.Hex(), true
+	case "block.miner":
+		return h.Coinbase.Hex(), true
+	case "block.gas_used":
+		return h.GasUsed, true
+	case "block.gas_limit":
+		return h.GasLimit, true
+	case "block.gas_utilization":
+		if h.GasLimit == 0 {
+			return nil, false
+		}
+		return float64(h.GasUsed) / float64(h.GasLimit) * 100, true
+	case "block.base_fee":
+		if h.BaseFee == nil {
+			return nil, false
+		}
+		return h.BaseFee, true
+	case "block.timestamp":
+		return h.Time, true
+	}
+	return nil, false
+}
+
+// logVars exposes log fields to expressions
+type logVars struct {
+	log   *types.Log
+	event *DecodedEvent
+}
+
+// LogVars returns expression fields for a contract log; event is the decoded log, if any
+func LogVars(log *types.Log, event *DecodedEvent) expr.Vars {
+	return logVars{log: log, event: event}
+}
+
+// Lookup implements expr.Vars
+func (v logVars) Lookup(name string) (interface{}, bool) {
+	l := v.log
+	switch name {
+	case "log.address":
+		return l.Address.Hex(), true
+	case "log.topics":
+		topics := make([]string, len(l.Topics))
+		for i, topic := range l.Topics {
+			topics[i] = topic.Hex()
+		}
+		return topics, true
+	case "log.topic0", "log.topic1", "log.topic2", "log.topic3":
+		i := int(name[len(name)-1] - '0')
+		if i >= len(l.Topics) {
+			return nil, false
+		}
+		return l.Topics[i].Hex(), true
+	case "log.data_size":
+		return len(l.Data), true
+	case "log.block_number":
+		return l.BlockNumber, true
+	case "log.tx_hash":
+		return l.TxHash.Hex(), true
+	case "log.index":
+		return uint64(l.Index), true
+	case "log.removed":
+		return l.Removed, true
+	}
+
+	if v.event == nil {
+		return nil, false
+	}
+	switch name {
+	case "log.event":
+		return v.event.Name, true
+	case "log.signature":
+		return v.event.Signature, true
+	case "log.standard":
+		return v.event.Standard, v.event.Standard != ""
+	}
+	if name, ok := strings.CutPrefix(name, "log.args."); ok {
+		value, exists := v.event.Args[name]
+		return value, exists
+	}
+	return nil, false
+}
+
+// GasVars returns expression fields for a gas price sample
+func GasVars(info *GasPriceInfo) expr.Vars {
+	vars := expr.MapVars{}
+	for name, value := range map[string]*big.Int{
+		"gas.standard": info.Standard,
+		"gas.fast":     info.Fast,
+		"gas.instant":  info.Instant,
+		"gas.base_fee": info.BaseFee,
+		"gas.priority": info.Priority,
+	} {
+		if value != nil {
+			vars[name] = value
+		}
+	}
+	return vars
+}
@@ -6,10 +6,13 @@ import (
 	"math/big"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
+
+	"simplied-blockchain-data-monitor-alert-go/pkg/expr"
 )
 
 // FilterType represents different types of filters
@@ -25,6 +28,8 @@ const (
 	FilterTypeMethod      FilterType = "method"
 	FilterTypeContract    FilterType = "contract"
 	FilterTypeCustom      FilterType = "custom"
+	FilterTypeEvent       FilterType = "event"    // Decoded event or method name
+	FilterTypeEventArg    FilterType = "eventArg" // Decoded argument named by Field
 )
 
 // FilterOperator represents filter comparison operators
@@ -50,7 +55,10 @@ type FilterCondition struct {
 	Type     FilterType     `json:"type"`
 	Operator FilterOperator `json:"operator"`
 	Value    interface{}    `json:"value"`
-	Field    string         `json:"field,omitempty"` // For custom filters
+	Field    string         `json:"field,omitempty"` // Argument name for eventArg filters
+
+	// program is the compiled expression of a custom condition, set during validation
+	program *expr.Program
 }
 
 // FilterRule represents a complete filter rule with multiple conditions
@@ -64,18 +72,50 @@ type FilterRule struct {
 	Priority    int                `json:"priority"`
 }
 
-// EventFilter manages filtering of blockchain events
+// EventFilter manages filtering of blockchain events.
+// Rules are indexed per event kind on add/remove so that matching an event only
+// evaluates the rules whose equality or threshold conditions it can satisfy.
 type EventFilter struct {
-	rules  map[string]*FilterRule
-	logger *logrus.Entry
+	rules      map[string]*FilterRule
+	indexes    map[string]*filterIndex
+	regexes    sync.Map // pattern -> *regexp.Regexp, compiled during validation
+	watchlists expr.Watchlists
+	abis       *ABIRegistry
+	mu         sync.RWMutex
+	logger     *logrus.Entry
 }
 
 // NewEventFilter creates a new event filter
 func NewEventFilter() *EventFilter {
-	return &EventFilter{
+	ef := &EventFilter{
 		rules:  make(map[string]*FilterRule),
+		abis:   NewABIRegistry(),
 		logger: logrus.WithField("component", "event_filter"),
 	}
+	ef.resetIndexes()
+	return ef
+}
+
+// resetIndexes creates empty indexes for every event kind
+func (ef *EventFilter) resetIndexes() {
+	ef.indexes = make(map[string]*filterIndex, len(filterKinds))
+	for _, kind := range filterKinds {
+		ef.indexes[kind] = newFilterIndex()
+	}
+}
+
+// indexRule adds a rule to every event kind index
+func (ef *EventFilter) indexRule(rule *FilterRule) {
+	for _, kind := range filterKinds {
+		ef.indexes[kind].add(kind, rule)
+	}
+}
+
+// unindexRule removes a rule from every event kind index
+func (ef *EventFilter) unindexRule(ruleID string) {
+	for _, kind := range filterKinds {
+		ef.indexes[kind].remove(ruleID)
+	}
 }
 
 // AddRule adds a new filter rule
@@ -88,19 +128,38 @@ func (ef *EventFilter) AddRule(rule *FilterRule) error {
 		return fmt.Errorf("invalid rule: %v", err)
 	}
 	
+	ef.mu.Lock()
+	ef.unindexRule(rule.ID)
 	ef.rules[rule.ID] = rule
+	ef.indexRule(rule)
+	ef.mu.Unlock()
 	ef.logger.WithField("rule_id", rule.ID).Info("Filter rule added")
 	
 	return nil
 }
 
+// SetWatchlists sets the address lists available to custom expressions through watchlist()
+func (ef *EventFilter) SetWatchlists(lists expr.Watchlists) {
+	ef.watchlists = lists
+}
+
+// SetABIRegistry sets the registry used to decode logs and transaction input for
+// event, eventArg and custom conditions; without one only the built-in ABIs are used
+func (ef *EventFilter) SetABIRegistry(abis *ABIRegistry) {
+	ef.abis = abis
+}
+
 // RemoveRule removes a filter rule
 func (ef *EventFilter) RemoveRule(ruleID string) error {
+	ef.mu.Lock()
 	if _, exists := ef.rules[ruleID]; !exists {
+		ef.mu.Unlock()
 		return fmt.Errorf("rule not found: %s", ruleID)
 	}
 	
 	delete(ef.rules, ruleID)
+	ef.unindexRule(ruleID)
+	ef.mu.Unlock()
 	ef.logger.WithField("rule_id", ruleID).Info("Filter rule removed")
 	
 	return nil
@@ -108,12 +167,16 @@ func (ef *EventFilter) RemoveRule(ruleID string) error {
 
 // GetRule returns a filter rule by ID
 func (ef *EventFilter) GetRule(ruleID string) (*FilterRule, bool) {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
 	rule, exists := ef.rules[ruleID]
 	return rule, exists
 }
 
 // GetAllRules returns all filter rules
 func (ef *EventFilter) GetAllRules() map[string]*FilterRule {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
 	result := make(map[string]*FilterRule)
 	for id, rule := range ef.rules {
 		result[id] = rule
@@ -125,7 +188,10 @@ func (ef *EventFilter) GetAllRules() map[string]*FilterRule {
 func (ef *EventFilter) FilterBlock(header *types.Header) []*FilterMatch {
 	var matches []*FilterMatch
 	
-	for _, rule := range ef.rules {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
+	
+	for _, rule := range ef.indexes[filterKindBlock].candidates(blockFields(header)) {
 		if !rule.Enabled {
 			continue
 		}
@@ -148,12 +214,16 @@ func (ef *EventFilter) FilterBlock(header *types.Header) []*FilterMatch {
 func (ef *EventFilter) FilterTransaction(tx *types.Transaction) []*FilterMatch {
 	var matches []*FilterMatch
 	
-	for _, rule := range ef.rules {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
+	
+	call := ef.abis.DecodeCall(tx.To(), tx.Data())
+	for _, rule := range ef.indexes[filterKindTransaction].candidates(transactionFields(tx, call)) {
 		if !rule.Enabled {
 			continue
 		}
 		
-		if ef.matchesTransactionRule(tx, rule) {
+		if ef.matchesTransactionRule(tx, call, rule) {
 			matches = append(matches, &FilterMatch{
 				RuleID:    rule.ID,
 				RuleName:  rule.Name,
@@ -171,12 +241,16 @@ func (ef *EventFilter) FilterTransaction(tx *types.Transaction) []*FilterMatch {
 func (ef *EventFilter) FilterLog(log *types.Log) []*FilterMatch {
 	var matches []*FilterMatch
 	
-	for _, rule := range ef.rules {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
+	
+	event := ef.abis.DecodeLog(log)
+	for _, rule := range ef.indexes[filterKindLog].candidates(logFields(log, event)) {
 		if !rule.Enabled {
 			continue
 		}
 		
-		if ef.matchesLogRule(log, rule) {
+		if ef.matchesLogRule(log, event, rule) {
 			matches = append(matches, &FilterMatch{
 				RuleID:    rule.ID,
 				RuleName:  rule.Name,
@@ -224,6 +298,20 @@ func (ef *EventFilter) validateCondition(condition *FilterCondition) error {
 	if condition.Type == "" {
 		return fmt.Errorf("condition type cannot be empty")
 	}
+
+	// Custom conditions carry an expression in Value and need no operator
+	if condition.Type == FilterTypeCustom {
+		source, ok := condition.Value.(string)
+		if !ok {
+			return fmt.Errorf("custom condition value must be an expression string")
+		}
+		program, err := CompileExpression(source)
+		if err != nil {
+			return err
+		}
+		condition.program = program
+		return nil
+	}
 	
 	if condition.Operator == "" {
 		return fmt.Errorf("condition operator cannot be empty")
@@ -233,11 +321,25 @@ func (ef *EventFilter) validateCondition(condition *FilterCondition) error {
 		return fmt.Errorf("condition value cannot be nil")
 	}
 	
+	if condition.Type == FilterTypeEventArg && condition.Field == "" {
+		return fmt.Errorf("eventArg condition requires a field")
+	}
+	
+	// Precompile regex patterns so matching does not compile them per event
+	if condition.Operator == FilterOpRegex {
+		pattern := fmt.Sprintf("%v", condition.Value)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex pattern: %v", err)
+		}
+		ef.regexes.Store(pattern, re)
+	}
+	
 	// Validate operator compatibility with type
 	switch condition.Type {
-	case FilterTypeAddress, FilterTypeContract:
+	case FilterTypeAddress, FilterTypeContract, FilterTypeEvent:
 		if !ef.isStringOperator(condition.Operator) {
-			return fmt.Errorf("invalid operator for address/contract type: %s", condition.Operator)
+			return fmt.Errorf("invalid operator for %s type: %s", condition.Type, condition.Operator)
 		}
 	case FilterTypeValue, FilterTypeGasPrice, FilterTypeGasUsed, FilterTypeBlockNumber:
 		if !ef.isNumericOperator(condition.Operator) {
@@ -284,22 +386,22 @@ func (ef *EventFilter) matchesBlockRule(header *types.Header, rule *FilterRule)
 }
 
 // matchesTransactionRule checks if a transaction matches a rule
-func (ef *EventFilter) matchesTransactionRule(tx *types.Transaction, rule *FilterRule) bool {
+func (ef *EventFilter) matchesTransactionRule(tx *types.Transaction, call *DecodedCall, rule *FilterRule) bool {
 	results := make([]bool, len(rule.Conditions))
 	
 	for i, condition := range rule.Conditions {
-		results[i] = ef.matchesTransactionCondition(tx, condition)
+		results[i] = ef.matchesTransactionCondition(tx, call, condition)
 	}
 	
 	return ef.evaluateLogic(results, rule.Logic)
 }
 
 // matchesLogRule checks if a log matches a rule
-func (ef *EventFilter) matchesLogRule(log *types.Log, rule *FilterRule) bool {
+func (ef *EventFilter) matchesLogRule(log *types.Log, event *DecodedEvent, rule *FilterRule) bool {
 	results := make([]bool, len(rule.Conditions))
 	
 	for i, condition := range rule.Conditions {
-		results[i] = ef.matchesLogCondition(log, condition)
+		results[i] = ef.matchesLogCondition(log, event, condition)
 	}
 	
 	return ef.evaluateLogic(results, rule.Logic)
@@ -314,14 +416,16 @@ func (ef *EventFilter) matchesBlockCondition(header *types.Header, condition *Fi
 		return ef.compareString(header.Coinbase.Hex(), condition.Operator, condition.Value)
 	case FilterTypeGasUsed:
 		return ef.compareNumeric(new(big.Int).SetUint64(header.GasUsed), condition.Operator, condition.Value)
+	case FilterTypeCustom:
+		return ef.matchesExpression(BlockVars(header), condition)
 	default:
 		ef.logger.WithField("type", condition.Type).Warn("Unsupported condition type for block")
 		return false
 	}
 }
 
-// matchesTransactionCondition checks if a transaction matches a specific condition
-func (ef *EventFilter) matchesTransactionCondition(tx *types.Transaction, condition *FilterCondition) bool {
+// matchesTransactionCondition checks if a transaction matches a specific condition; call is the decoded input, if any
+func (ef *EventFilter) matchesTransactionCondition(tx *types.Transaction, call *DecodedCall, condition *FilterCondition) bool {
 	switch condition.Type {
 	case FilterTypeAddress:
 		if tx.To() != nil {
@@ -346,14 +450,20 @@ func (ef *EventFilter) matchesTransactionCondition(tx *types.Transaction, condit
 			return condition.Operator == FilterOpEqual && condition.Value == "creation"
 		}
 		return ef.compareString(tx.To().Hex(), condition.Operator, condition.Value)
+	case FilterTypeEvent:
+		return call != nil && ef.compareString(call.Name, condition.Operator, condition.Value)
+	case FilterTypeEventArg:
+		return call != nil && ef.compareArg(call.Args, condition)
+	case FilterTypeCustom:
+		return ef.matchesExpression(TransactionVars(tx, nil, call), condition)
 	default:
 		ef.logger.WithField("type", condition.Type).Warn("Unsupported condition type for transaction")
 		return false
 	}
 }
 
-// matchesLogCondition checks if a log matches a specific condition
-func (ef *EventFilter) matchesLogCondition(log *types.Log, condition *FilterCondition) bool {
+// matchesLogCondition checks if a log matches a specific condition; event is the decoded log, if any
+func (ef *EventFilter) matchesLogCondition(log *types.Log, event *DecodedEvent, condition *FilterCondition) bool {
 	switch condition.Type {
 	case FilterTypeAddress, FilterTypeContract:
 		return ef.compareString(log.Address.Hex(), condition.Operator, condition.Value)
@@ -361,12 +471,33 @@ func (ef *EventFilter) matchesLogCondition(log *types.Log, condition *FilterCond
 		return ef.matchesTopics(log.Topics, condition)
 	case FilterTypeBlockNumber:
 		return ef.compareNumeric(new(big.Int).SetUint64(log.BlockNumber), condition.Operator, condition.Value)
+	case FilterTypeEvent:
+		return event != nil && ef.compareString(event.Name, condition.Operator, condition.Value)
+	case FilterTypeEventArg:
+		return event != nil && ef.compareArg(event.Args, condition)
+	case FilterTypeCustom:
+		return ef.matchesExpression(LogVars(log, event), condition)
 	default:
 		ef.logger.WithField("type", condition.Type).Warn("Unsupported condition type for log")
 		return false
 	}
 }
 
+// matchesExpression evaluates a custom condition's expression; fields of other event kinds evaluate as missing
+func (ef *EventFilter) matchesExpression(vars expr.Vars, condition *FilterCondition) bool {
+	if condition.program == nil {
+		ef.logger.Warn("Custom condition was not compiled")
+		return false
+	}
+
+	matched, err := condition.program.Eval(vars, ef.watchlists)
+	if err != nil {
+		ef.logger.WithError(err).WithField("expression", condition.program.Source()).Warn("Failed to evaluate custom condition")
+		return false
+	}
+	return matched
+}
+
 // matchesTopics checks if log topics match the condition
 func (ef *EventFilter) matchesTopics(topics []common.Hash, condition *FilterCondition) bool {
 	switch condition.Operator {
@@ -399,6 +530,18 @@ func (ef *EventFilter) matchesTopics(topics []common.Hash, condition *FilterCond
 	}
 }
 
+// compareArg compares a decoded argument: integers numerically, everything else as strings
+func (ef *EventFilter) compareArg(args map[string]interface{}, condition *FilterCondition) bool {
+	value, ok := args[condition.Field]
+	if !ok {
+		return false
+	}
+	if n, ok := value.(*big.Int); ok {
+		return ef.compareNumeric(n, condition.Operator, condition.Value)
+	}
+	return ef.compareString(fmt.Sprintf("%v", value), condition.Operator, condition.Value)
+}
+
 // compareString compares string values based on the operator
 func (ef *EventFilter) compareString(actual string, operator FilterOperator, expected interface{}) bool {
 	expectedStr := fmt.Sprintf("%v", expected)
@@ -415,6 +558,9 @@ func (ef *EventFilter) compareString(actual string, operator FilterOperator, exp
 	case FilterOpEndsWith:
 		return strings.HasSuffix(strings.ToLower(actual), strings.ToLower(expectedStr))
 	case FilterOpRegex:
+		if re, ok := ef.regexes.Load(expectedStr); ok {
+			return re.(*regexp.Regexp).MatchString(actual)
+		}
 		matched, err := regexp.MatchString(expectedStr, actual)
 		if err != nil {
 			ef.logger.WithError(err).Warn("Invalid regex pattern")
@@ -544,6 +690,14 @@ func (ef *EventFilter) evaluateLogic(results []bool, logic string) bool {
 
 // GetStats returns filter statistics
 func (ef *EventFilter) GetStats() map[string]interface{} {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
+	
+	scanned := make(map[string]int, len(ef.indexes))
+	for kind, idx := range ef.indexes {
+		scanned[kind] = len(idx.scan)
+	}
+	
 	enabledRules := 0
 	totalConditions := 0
 	
@@ -559,6 +713,7 @@ func (ef *EventFilter) GetStats() map[string]interface{} {
 		"enabled_rules":     enabledRules,
 		"total_conditions":  totalConditions,
 		"rules_by_priority": ef.getRulesByPriority(),
+		"unindexed_rules":   scanned,
 	}
 }
 
@@ -575,6 +730,8 @@ func (ef *EventFilter) getRulesByPriority() map[int]int {
 
 // ExportRules exports all rules to JSON
 func (ef *EventFilter) ExportRules() ([]byte, error) {
+	ef.mu.RLock()
+	defer ef.mu.RUnlock()
 	return json.MarshalIndent(ef.rules, "", "  ")
 }
 
@@ -593,7 +750,13 @@ func (ef *EventFilter) ImportRules(data []byte) error {
 	}
 	
 	// Clear existing rules and import new ones
+	ef.mu.Lock()
 	ef.rules = rules
+	ef.resetIndexes()
+	for _, rule := range rules {
+		ef.indexRule(rule)
+	}
+	ef.mu.Unlock()
 	ef.logger.WithField("count", len(rules)).Info("Rules imported successfully")
 	
 	return nil
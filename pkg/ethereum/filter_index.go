@@ -0,0 +1,391 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event kinds handled by EventFilter
+const (
+	filterKindBlock       = "block"
+	filterKindTransaction = "transaction"
+	filterKindLog         = "log"
+)
+
+// filterKinds lists the event kinds in a fixed order
+var filterKinds = []string{filterKindBlock, filterKindTransaction, filterKindLog}
+
+// kindFields lists the condition types each event kind evaluates and whether they are numeric.
+// Conditions of other types never match that kind, see matchesBlockCondition and friends.
+var kindFields = map[string]map[FilterType]bool{
+	filterKindBlock: {
+		FilterTypeBlockNumber: true,
+		FilterTypeAddress:     false,
+		FilterTypeGasUsed:     true,
+		FilterTypeCustom:      false,
+	},
+	filterKindTransaction: {
+		FilterTypeAddress:  false,
+		FilterTypeValue:    true,
+		FilterTypeGasPrice: true,
+		FilterTypeGasUsed:  true,
+		FilterTypeMethod:   false,
+		FilterTypeContract: false,
+		FilterTypeEvent:    false,
+		FilterTypeEventArg: false,
+		FilterTypeCustom:   false,
+	},
+	filterKindLog: {
+		FilterTypeAddress:     false,
+		FilterTypeContract:    false,
+		FilterTypeTopics:      false,
+		FilterTypeBlockNumber: true,
+		FilterTypeEvent:       false,
+		FilterTypeEventArg:    false,
+		FilterTypeCustom:      false,
+	},
+}
+
+// exactKey identifies an equality posting: condition type plus lowercased value
+type exactKey struct {
+	ctype FilterType
+	value string
+}
+
+// thresholdKey identifies a sorted threshold list
+type thresholdKey struct {
+	ctype FilterType
+	op    FilterOperator
+}
+
+// thresholdEntry is a rule whose indexed condition compares against value
+type thresholdEntry struct {
+	value *big.Int
+	rule  *FilterRule
+}
+
+// posting records where a rule was indexed so it can be removed again
+type posting struct {
+	exact     []exactKey
+	threshold *thresholdKey
+	value     *big.Int
+	scan      bool
+}
+
+// filterIndex narrows the rules worth evaluating for one event kind.
+// Postings are necessary conditions only; candidates are always fully evaluated.
+type filterIndex struct {
+	// rules indexed by an equality/in/contains condition
+	exact map[exactKey]map[string]*FilterRule
+	// rules indexed by a numeric threshold, sorted ascending by threshold
+	thresholds map[thresholdKey][]thresholdEntry
+	// rules without an indexable condition, evaluated for every event
+	scan map[string]*FilterRule
+	// postings per rule ID
+	postings map[string]posting
+}
+
+// newFilterIndex creates an empty index
+func newFilterIndex() *filterIndex {
+	return &filterIndex{
+		exact:      make(map[exactKey]map[string]*FilterRule),
+		thresholds: make(map[thresholdKey][]thresholdEntry),
+		scan:       make(map[string]*FilterRule),
+		postings:   make(map[string]posting),
+	}
+}
+
+// add indexes a rule for the given event kind; rules that can never match the kind are skipped
+func (idx *filterIndex) add(kind string, rule *FilterRule) {
+	p, ok := planPosting(kind, rule)
+	if !ok {
+		return
+	}
+
+	for _, key := range p.exact {
+		rules, exists := idx.exact[key]
+		if !exists {
+			rules = make(map[string]*FilterRule)
+			idx.exact[key] = rules
+		}
+		rules[rule.ID] = rule
+	}
+	if p.threshold != nil {
+		entries := idx.thresholds[*p.threshold]
+		i := sort.Search(len(entries), func(i int) bool { return entries[i].value.Cmp(p.value) >= 0 })
+		entries = append(entries, thresholdEntry{})
+		copy(entries[i+1:], entries[i:])
+		entries[i] = thresholdEntry{value: p.value, rule: rule}
+		idx.thresholds[*p.threshold] = entries
+	}
+	if p.scan {
+		idx.scan[rule.ID] = rule
+	}
+	idx.postings[rule.ID] = p
+}
+
+// remove drops a rule from the index
+func (idx *filterIndex) remove(ruleID string) {
+	p, ok := idx.postings[ruleID]
+	if !ok {
+		return
+	}
+	delete(idx.postings, ruleID)
+
+	for _, key := range p.exact {
+		delete(idx.exact[key], ruleID)
+		if len(idx.exact[key]) == 0 {
+			delete(idx.exact, key)
+		}
+	}
+	if p.threshold != nil {
+		entries := idx.thresholds[*p.threshold]
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.rule.ID != ruleID {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == 0 {
+			delete(idx.thresholds, *p.threshold)
+		} else {
+			idx.thresholds[*p.threshold] = kept
+		}
+	}
+	delete(idx.scan, ruleID)
+}
+
+// candidates returns the rules that may match an event with the given field values
+func (idx *filterIndex) candidates(strs map[FilterType][]string, nums map[FilterType]*big.Int) map[string]*FilterRule {
+	result := make(map[string]*FilterRule, len(idx.scan))
+	for id, rule := range idx.scan {
+		result[id] = rule
+	}
+
+	for ctype, values := range strs {
+		for _, value := range values {
+			for id, rule := range idx.exact[exactKey{ctype: ctype, value: value}] {
+				result[id] = rule
+			}
+		}
+	}
+
+	for ctype, actual := range nums {
+		if actual == nil {
+			continue
+		}
+		for id, rule := range idx.exact[exactKey{ctype: ctype, value: actual.String()}] {
+			result[id] = rule
+		}
+
+		// Entries are sorted ascending, so each operator selects a prefix or suffix
+		for _, op := range []FilterOperator{FilterOpGreaterThan, FilterOpGreaterThanOrEqual, FilterOpLessThan, FilterOpLessThanOrEqual} {
+			entries := idx.thresholds[thresholdKey{ctype: ctype, op: op}]
+			if len(entries) == 0 {
+				continue
+			}
+			var from, to int
+			switch op {
+			case FilterOpGreaterThan: // threshold < actual
+				from, to = 0, sort.Search(len(entries), func(i int) bool { return entries[i].value.Cmp(actual) >= 0 })
+			case FilterOpGreaterThanOrEqual: // threshold <= actual
+				from, to = 0, sort.Search(len(entries), func(i int) bool { return entries[i].value.Cmp(actual) > 0 })
+			case FilterOpLessThan: // threshold > actual
+				from, to = sort.Search(len(entries), func(i int) bool { return entries[i].value.Cmp(actual) > 0 }), len(entries)
+			case FilterOpLessThanOrEqual: // threshold >= actual
+				from, to = sort.Search(len(entries), func(i int) bool { return entries[i].value.Cmp(actual) >= 0 }), len(entries)
+			}
+			for _, entry := range entries[from:to] {
+				result[entry.rule.ID] = entry.rule
+			}
+		}
+	}
+	return result
+}
+
+// planPosting decides how a rule is indexed for an event kind.
+// AND rules are indexed by their most selective condition; OR rules need every
+// applicable condition indexed, otherwise they fall back to a scan.
+func planPosting(kind string, rule *FilterRule) (posting, bool) {
+	fields := kindFields[kind]
+
+	if rule.Logic == "OR" {
+		var p posting
+		applicable := false
+		for _, condition := range rule.Conditions {
+			if _, ok := fields[condition.Type]; !ok {
+				// Never true for this kind
+				continue
+			}
+			applicable = true
+			keys, threshold, _, ok := conditionPosting(condition, fields[condition.Type])
+			if !ok || threshold != nil {
+				// Thresholds of several conditions cannot share one posting
+				return posting{scan: true}, true
+			}
+			p.exact = append(p.exact, keys...)
+		}
+		return p, applicable
+	}
+
+	var best posting
+	bestRank := 0
+	for _, condition := range rule.Conditions {
+		numeric, ok := fields[condition.Type]
+		if !ok {
+			// An AND rule with a condition this kind never satisfies cannot match
+			return posting{}, false
+		}
+		keys, threshold, value, ok := conditionPosting(condition, numeric)
+		if !ok {
+			continue
+		}
+		rank := 1
+		if threshold == nil {
+			rank = 2
+		}
+		if rank > bestRank {
+			best, bestRank = posting{exact: keys, threshold: threshold, value: value}, rank
+		}
+	}
+	if bestRank == 0 {
+		return posting{scan: true}, true
+	}
+	return best, true
+}
+
+// conditionPosting returns the equality keys, or the threshold and its value, that a matching event must hit
+func conditionPosting(condition *FilterCondition, numeric bool) ([]exactKey, *thresholdKey, *big.Int, bool) {
+	// Decoded arguments have no fixed type, so only their rule's other conditions are indexed
+	if condition.Type == FilterTypeCustom || condition.Type == FilterTypeEventArg {
+		return nil, nil, nil, false
+	}
+
+	if condition.Type == FilterTypeTopics {
+		topic, ok := condition.Value.(string)
+		if condition.Operator != FilterOpContains || !ok {
+			return nil, nil, nil, false
+		}
+		return []exactKey{{ctype: FilterTypeTopics, value: strings.ToLower(topic)}}, nil, nil, true
+	}
+
+	switch condition.Operator {
+	case FilterOpEqual:
+		key, ok := postingValue(condition.Value, numeric)
+		if !ok {
+			return nil, nil, nil, false
+		}
+		return []exactKey{{ctype: condition.Type, value: key}}, nil, nil, true
+
+	case FilterOpIn:
+		items, ok := condition.Value.([]interface{})
+		if !ok {
+			return nil, nil, nil, false
+		}
+		keys := make([]exactKey, 0, len(items))
+		for _, item := range items {
+			key, ok := postingValue(item, numeric)
+			if !ok {
+				return nil, nil, nil, false
+			}
+			keys = append(keys, exactKey{ctype: condition.Type, value: key})
+		}
+		return keys, nil, nil, true
+
+	case FilterOpGreaterThan, FilterOpGreaterThanOrEqual, FilterOpLessThan, FilterOpLessThanOrEqual:
+		value, ok := toBigInt(condition.Value)
+		if !numeric || !ok {
+			return nil, nil, nil, false
+		}
+		return nil, &thresholdKey{ctype: condition.Type, op: condition.Operator}, value, true
+	}
+	return nil, nil, nil, false
+}
+
+// postingValue normalizes a condition value the same way compareString/compareNumeric compare it
+func postingValue(value interface{}, numeric bool) (string, bool) {
+	if !numeric {
+		return strings.ToLower(fmt.Sprintf("%v", value)), true
+	}
+	n, ok := toBigInt(value)
+	if !ok {
+		return "", false
+	}
+	return n.String(), true
+}
+
+// toBigInt converts a numeric condition value, mirroring compareNumeric
+func toBigInt(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case string:
+		return new(big.Int).SetString(v, 0)
+	case int64:
+		return big.NewInt(v), true
+	case uint64:
+		return new(big.Int).SetUint64(v), true
+	case *big.Int:
+		return v, v != nil
+	case float64:
+		return big.NewInt(int64(v)), true
+	default:
+		return nil, false
+	}
+}
+
+// blockFields extracts the indexed field values of a block header
+func blockFields(header *types.Header) (map[FilterType][]string, map[FilterType]*big.Int) {
+	return map[FilterType][]string{
+		FilterTypeAddress: {strings.ToLower(header.Coinbase.Hex())},
+	}, map[FilterType]*big.Int{
+		FilterTypeBlockNumber: header.Number,
+		FilterTypeGasUsed:     new(big.Int).SetUint64(header.GasUsed),
+	}
+}
+
+// transactionFields extracts the indexed field values of a transaction and its decoded input
+func transactionFields(tx *types.Transaction, call *DecodedCall) (map[FilterType][]string, map[FilterType]*big.Int) {
+	strs := map[FilterType][]string{}
+	if to := tx.To(); to != nil {
+		addr := strings.ToLower(to.Hex())
+		strs[FilterTypeAddress] = []string{addr}
+		strs[FilterTypeContract] = []string{addr}
+	} else {
+		strs[FilterTypeContract] = []string{"creation"}
+	}
+	if len(tx.Data()) >= 4 {
+		strs[FilterTypeMethod] = []string{fmt.Sprintf("0x%x", tx.Data()[:4])}
+	}
+	if call != nil {
+		strs[FilterTypeEvent] = []string{strings.ToLower(call.Name)}
+	}
+
+	return strs, map[FilterType]*big.Int{
+		FilterTypeValue:    tx.Value(),
+		FilterTypeGasPrice: tx.GasPrice(),
+		FilterTypeGasUsed:  new(big.Int).SetUint64(tx.Gas()),
+	}
+}
+
+// logFields extracts the indexed field values of a log and its decoded event
+func logFields(log *types.Log, event *DecodedEvent) (map[FilterType][]string, map[FilterType]*big.Int) {
+	addr := strings.ToLower(log.Address.Hex())
+	topics := make([]string, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = strings.ToLower(topic.Hex())
+	}
+	strs := map[FilterType][]string{
+		FilterTypeAddress:  {addr},
+		FilterTypeContract: {addr},
+		FilterTypeTopics:   topics,
+	}
+	if event != nil {
+		strs[FilterTypeEvent] = []string{strings.ToLower(event.Name)}
+	}
+	return strs, map[FilterType]*big.Int{
+		FilterTypeBlockNumber: new(big.Int).SetUint64(log.BlockNumber),
+	}
+}
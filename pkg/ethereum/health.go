@@ -24,6 +24,13 @@ type HealthChecker struct {
 	mu sync.RWMutex
 	// 是否正在运行
 	running bool
+	// 健康检查结果处理器
+	handlers []HealthCheckHandler
+}
+
+// HealthCheckHandler 健康检查结果处理器，每轮健康检查完成后调用
+type HealthCheckHandler interface {
+	HandleHealthCheck(results []*HealthCheckResult)
 }
 
 // HealthCheckResult 健康检查结果
@@ -40,6 +47,10 @@ type HealthCheckResult struct {
 	BlockNumber uint64 `json:"block_number"`
 	// 链ID
 	ChainID string `json:"chain_id"`
+	// 落后于连接池最高区块的区块数
+	HeadLag uint64 `json:"head_lag"`
+	// 是否因落后过多被降级
+	Degraded bool `json:"degraded"`
 	// 检查时间
 	CheckTime time.Time `json:"check_time"`
 }
@@ -137,13 +148,20 @@ func (hc *HealthChecker) performHealthCheck() {
 		close(results)
 	}()
 
+	// 收集检查结果并计算各节点落后的区块数
+	var collected []*HealthCheckResult
+	for result := range results {
+		collected = append(collected, result)
+	}
+	hc.updateHeadLag(clients, collected)
+
 	// 处理检查结果
 	healthyCount := 0
 	totalCount := 0
 
-	for result := range results {
+	for _, result := range collected {
 		totalCount++
-		if result.IsHealthy {
+		if result.IsHealthy && !result.Degraded {
 			healthyCount++
 		}
 
@@ -153,10 +171,18 @@ func (hc *HealthChecker) performHealthCheck() {
 			"response_time": result.ResponseTime,
 			"block_number":  result.BlockNumber,
 			"chain_id":      result.ChainID,
+			"head_lag":      result.HeadLag,
 			"error":         result.Error,
 		}).Debug("Health check result")
 	}
 
+	hc.mu.RLock()
+	handlers := hc.handlers
+	hc.mu.RUnlock()
+	for _, handler := range handlers {
+		handler.HandleHealthCheck(collected)
+	}
+
 	// 检查是否满足最小健康客户端数量要求
 	if healthyCount < hc.pool.config.MinHealthyClients {
 		hc.logger.WithFields(logrus.Fields{
@@ -175,6 +201,43 @@ func (hc *HealthChecker) performHealthCheck() {
 	}).Info("Health check completed")
 }
 
+// updateHeadLag 以本轮检查的最高区块为基准计算各节点落后的区块数
+// 落后超过 MaxHeadLag 的节点标记为降级，不再参与请求分配，直到追上为止
+func (hc *HealthChecker) updateHeadLag(clients []*Client, results []*HealthCheckResult) {
+	var head uint64
+	byURL := make(map[string]*HealthCheckResult, len(results))
+	for _, result := range results {
+		byURL[result.ClientURL] = result
+		if result.IsHealthy && result.BlockNumber > head {
+			head = result.BlockNumber
+		}
+	}
+
+	for _, client := range clients {
+		result := byURL[client.config.URL]
+		if result == nil || !result.IsHealthy {
+			continue
+		}
+
+		result.HeadLag = head - result.BlockNumber
+		result.Degraded = result.HeadLag > hc.pool.config.MaxHeadLag
+		if !client.setHeadLag(result.HeadLag, result.Degraded) {
+			continue
+		}
+
+		fields := logrus.Fields{
+			"client_url": client.config.URL,
+			"head_lag":   result.HeadLag,
+			"max_lag":    hc.pool.config.MaxHeadLag,
+		}
+		if result.Degraded {
+			hc.logger.WithFields(fields).Warn("Client is lagging behind the pool head, marking as degraded")
+		} else {
+			hc.logger.WithFields(fields).Info("Client caught up with the pool head, no longer degraded")
+		}
+	}
+}
+
 // checkClient 检查单个客户端的健康状态
 func (hc *HealthChecker) checkClient(client *Client) *HealthCheckResult {
 	result := &HealthCheckResult{
@@ -196,6 +259,8 @@ func (hc *HealthChecker) checkClient(client *Client) *HealthCheckResult {
 	// 尝试获取最新区块号
 	block, err := client.GetLatestBlock(ctx)
 	result.ResponseTime = time.Since(startTime)
+	// 健康检查同样计入延迟评分，使未被选中的节点评分也能恢复
+	client.observe(result.ResponseTime, err)
 
 	if err != nil {
 		result.Error = err.Error()
@@ -279,10 +344,18 @@ func (hc *HealthChecker) GetHealthStatus() []*HealthCheckResult {
 		result := hc.checkClient(client)
 		results = append(results, result)
 	}
+	hc.updateHeadLag(clients, results)
 
 	return results
 }
 
+// AddHandler 添加健康检查结果处理器
+func (hc *HealthChecker) AddHandler(handler HealthCheckHandler) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.handlers = append(hc.handlers, handler)
+}
+
 // IsRunning 检查健康检查器是否正在运行
 func (hc *HealthChecker) IsRunning() bool {
 	hc.mu.RLock()
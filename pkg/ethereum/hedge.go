@@ -0,0 +1,252 @@
+package ethereum
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// hedgeWindowSize 计算对冲延迟所保留的最近成功请求耗时样本数
+	hedgeWindowSize = 512
+	// hedgeMinSamples 样本不足时使用 HedgeConfig.MaxDelay 作为对冲延迟
+	hedgeMinSamples = 20
+	// hedgeBudgetBurst 对冲预算的最大积累量，限制空闲后的突发对冲数
+	hedgeBudgetBurst = 10.0
+)
+
+// HedgeConfig 对冲请求配置
+// 读取请求超过延迟分位数仍未返回时，向另一个健康节点发出相同请求，采用先成功的结果
+type HedgeConfig struct {
+	// 触发对冲的延迟分位数，如 0.95
+	Percentile float64 `json:"percentile"`
+	// 对冲延迟下限
+	MinDelay time.Duration `json:"min_delay"`
+	// 对冲延迟上限，样本不足时也使用该值
+	MaxDelay time.Duration `json:"max_delay"`
+	// 对冲预算：每个请求积累的对冲额度，0.1 表示最多对冲 10% 的请求
+	BudgetRatio float64 `json:"budget_ratio"`
+}
+
+// hedger 记录请求耗时分布并管理对冲预算
+type hedger struct {
+	config *HedgeConfig
+	mu     sync.Mutex
+	// 最近成功请求耗时的环形缓冲区
+	samples []time.Duration
+	next    int
+	// 剩余对冲额度
+	tokens float64
+}
+
+// newHedger 创建对冲器，未设置的配置项使用默认值
+func newHedger(config *HedgeConfig) *hedger {
+	if config.Percentile <= 0 || config.Percentile >= 1 {
+		// 默认在 P95 延迟后对冲
+		config.Percentile = 0.95
+	}
+	if config.MinDelay == 0 {
+		// 默认对冲延迟下限为50毫秒
+		config.MinDelay = 50 * time.Millisecond
+	}
+	if config.MaxDelay == 0 {
+		// 默认对冲延迟上限为2秒
+		config.MaxDelay = 2 * time.Second
+	}
+	if config.MaxDelay < config.MinDelay {
+		config.MaxDelay = config.MinDelay
+	}
+	if config.BudgetRatio <= 0 {
+		// 默认最多对冲 10% 的请求
+		config.BudgetRatio = 0.1
+	}
+
+	return &hedger{
+		config:  config,
+		samples: make([]time.Duration, 0, hedgeWindowSize),
+		tokens:  hedgeBudgetBurst,
+	}
+}
+
+// record 记录一次成功请求的耗时
+func (h *hedger) record(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < hedgeWindowSize {
+		h.samples = append(h.samples, latency)
+		return
+	}
+	h.samples[h.next] = latency
+	h.next = (h.next + 1) % hedgeWindowSize
+}
+
+// delay 返回对冲延迟：最近请求耗时的分位数，限制在 [MinDelay, MaxDelay] 内
+func (h *hedger) delay() time.Duration {
+	h.mu.Lock()
+	if len(h.samples) < hedgeMinSamples {
+		h.mu.Unlock()
+		return h.config.MaxDelay
+	}
+	sorted := slices.Clone(h.samples)
+	h.mu.Unlock()
+
+	slices.Sort(sorted)
+	index := int(math.Ceil(h.config.Percentile*float64(len(sorted)))) - 1
+	return min(max(sorted[max(index, 0)], h.config.MinDelay), h.config.MaxDelay)
+}
+
+// deposit 每个可对冲的请求积累 BudgetRatio 个对冲额度
+func (h *hedger) deposit() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tokens = min(h.tokens+h.config.BudgetRatio, hedgeBudgetBurst)
+}
+
+// withdraw 消耗一个对冲额度，额度不足时返回 false
+func (h *hedger) withdraw() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tokens < 1 {
+		return false
+	}
+	h.tokens--
+	return true
+}
+
+// observe 记录请求结果：更新客户端评分，成功请求计入对冲延迟样本
+func (p *ClientPool) observe(client *Client, latency time.Duration, err error) {
+	client.observe(latency, err)
+	if err == nil && p.hedger != nil {
+		p.hedger.record(latency)
+	}
+}
+
+// getHedgeClient 选择对冲节点：除 primary 外评分较低的健康节点
+func (p *ClientPool) getHedgeClient(primary *Client) *Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var candidates []*Client
+	for _, client := range p.getHealthyClients() {
+		if client != primary {
+			candidates = append(candidates, client)
+		}
+	}
+	return p.getLatencyClient(candidates)
+}
+
+// ExecuteRead 执行带故障转移的只读请求，启用 HedgeConfig 时对慢请求进行对冲
+// read 可能在两个节点上并发执行，只能通过返回值传递结果，不能写入共享变量；
+// 落选的请求通过 ctx 取消。写操作（如发送交易）应使用 ExecuteWithFailover
+func ExecuteRead[T any](ctx context.Context, p *ClientPool, read func(ctx context.Context, client *Client) (T, error)) (T, error) {
+	var result T
+
+	if p.hedger == nil {
+		err := p.ExecuteWithFailover(ctx, func(client *Client) error {
+			var err error
+			result, err = read(ctx, client)
+			return err
+		})
+		return result, err
+	}
+
+	err := p.failover(ctx, func(client *Client) error {
+		var err error
+		result, err = hedgedRead(ctx, p, client, read)
+		return err
+	})
+	return result, err
+}
+
+// hedgedRead 在 primary 上执行 read，超过对冲延迟且预算允许时向另一个节点发出相同请求
+// 返回先成功的结果并取消另一个请求；两个请求都失败时返回 primary 的错误
+func hedgedRead[T any](ctx context.Context, p *ClientPool, primary *Client, read func(ctx context.Context, client *Client) (T, error)) (T, error) {
+	type outcome struct {
+		client *Client
+		value  T
+		err    error
+	}
+
+	var zero T
+	p.hedger.deposit()
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 缓冲区容纳两个请求的结果，返回后落选的请求不会阻塞
+	results := make(chan outcome, 2)
+	launch := func(client *Client) {
+		go func() {
+			started := time.Now()
+			value, err := read(attemptCtx, client)
+			switch {
+			case ctx.Err() != nil:
+				// 调用方取消的请求不反映节点质量，不计入评分
+			case attemptCtx.Err() != nil:
+				// 被对冲取消的请求按已耗时计入延迟，该耗时是实际延迟的下限
+				client.observe(time.Since(started), nil)
+			default:
+				p.observe(client, time.Since(started), err)
+			}
+			results <- outcome{client: client, value: value, err: err}
+		}()
+	}
+
+	launch(primary)
+	pending := 1
+
+	timer := time.NewTimer(p.hedger.delay())
+	defer timer.Stop()
+
+	var primaryErr error
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				if result.client != primary {
+					p.mu.Lock()
+					p.stats.HedgeWins++
+					p.mu.Unlock()
+				}
+				return result.value, nil
+			}
+			if result.client == primary {
+				primaryErr = result.err
+			}
+			if pending == 0 {
+				if primaryErr == nil {
+					primaryErr = result.err
+				}
+				return zero, primaryErr
+			}
+
+		case <-timer.C:
+			hedge := p.getHedgeClient(primary)
+			if hedge == nil || !p.hedger.withdraw() {
+				continue
+			}
+
+			p.mu.Lock()
+			p.stats.HedgedRequests++
+			p.mu.Unlock()
+
+			p.logger.WithFields(logrus.Fields{
+				"client_url": primary.config.URL,
+				"hedge_url":  hedge.config.URL,
+			}).Debug("Request exceeded hedge delay, sending hedged request")
+
+			launch(hedge)
+			pending++
+
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
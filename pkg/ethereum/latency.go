@@ -0,0 +1,78 @@
+package ethereum
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// latencyEWMAAlpha 延迟与错误率指数加权平均的平滑系数，越大越偏向最近的请求
+	latencyEWMAAlpha = 0.3
+	// errorRatePenalty 错误率对评分的放大倍数，错误率 10% 时评分翻倍
+	errorRatePenalty = 10.0
+)
+
+// observe 记录一次请求结果，更新延迟与错误率的指数加权平均
+// 失败请求通常很快返回，不计入延迟，只计入错误率
+func (c *Client) observe(latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	failed := 0.0
+	if err != nil {
+		failed = 1.0
+	}
+
+	if c.samples == 0 {
+		c.errorEWMA = failed
+	} else {
+		c.errorEWMA += latencyEWMAAlpha * (failed - c.errorEWMA)
+	}
+	if err == nil {
+		if c.latencyEWMA == 0 {
+			c.latencyEWMA = float64(latency)
+		} else {
+			c.latencyEWMA += latencyEWMAAlpha * (float64(latency) - c.latencyEWMA)
+		}
+	}
+	c.samples++
+}
+
+// score 返回客户端评分（秒），越低越优先；调用方需持有 c.mu
+// 尚无样本的客户端评分为 0，保证新节点会被尝试
+func (c *Client) score() float64 {
+	if c.samples == 0 {
+		return 0
+	}
+	return time.Duration(c.latencyEWMA).Seconds() * (1 + errorRatePenalty*c.errorEWMA)
+}
+
+// Score 返回客户端的延迟感知评分
+func (c *Client) Score() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.score()
+}
+
+// getLatencyClient 按二选一策略获取客户端：随机取两个，选择评分较低的一个
+// 相比总选最优节点，避免所有请求集中到同一节点
+func (p *ClientPool) getLatencyClient(clients []*Client) *Client {
+	switch len(clients) {
+	case 0:
+		return nil
+	case 1:
+		return clients[0]
+	}
+
+	i := rand.IntN(len(clients))
+	j := rand.IntN(len(clients) - 1)
+	if j >= i {
+		j++
+	}
+
+	a, b := clients[i], clients[j]
+	if b.Score() < a.Score() {
+		return b
+	}
+	return a
+}
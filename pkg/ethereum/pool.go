@@ -21,6 +21,7 @@ const (
 	StrategyRandom     LoadBalanceStrategy = "random"      // 随机
 	StrategyPriority   LoadBalanceStrategy = "priority"    // 优先级
 	StrategyHealthy    LoadBalanceStrategy = "healthy"     // 最健康
+	StrategyLatency    LoadBalanceStrategy = "latency"     // 延迟感知（EWMA 评分，二选一）
 )
 
 // PoolConfig 连接池配置
@@ -37,10 +38,16 @@ type PoolConfig struct {
 	RetryDelay time.Duration `json:"retry_delay"`
 	// 最小健康客户端数
 	MinHealthyClients int `json:"min_healthy_clients"`
+	// 允许落后于连接池最高区块的最大区块数，超过时节点被降级
+	MaxHeadLag uint64 `json:"max_head_lag"`
+	// 法定数量读取所需的一致节点数，默认为客户端数的多数
+	QuorumSize int `json:"quorum_size"`
 	// 是否启用故障转移
 	EnableFailover bool `json:"enable_failover"`
 	// 熔断器配置
 	CircuitBreakerConfig *CircuitBreakerConfig `json:"circuit_breaker_config"`
+	// 对冲请求配置，为空时不对冲
+	HedgeConfig *HedgeConfig `json:"hedge_config"`
 }
 
 // CircuitBreakerConfig 熔断器配置
@@ -78,8 +85,12 @@ type ClientPool struct {
 	healthChecker *HealthChecker
 	// 熔断器
 	circuitBreaker *CircuitBreaker
+	// 对冲器
+	hedger *hedger
 	// 连接池统计信息
 	stats *PoolStats
+	// 法定数量读取不一致处理器
+	quorumHandlers []QuorumHandler
 }
 
 // PoolStats 连接池统计信息
@@ -88,10 +99,16 @@ type PoolStats struct {
 	TotalClients int `json:"total_clients"`
 	// 健康客户端数
 	HealthyClients int `json:"healthy_clients"`
+	// 因落后过多被降级的客户端数
+	DegradedClients int `json:"degraded_clients"`
 	// 总请求数
 	TotalRequests int64 `json:"total_requests"`
 	// 失败请求数
 	FailedRequests int64 `json:"failed_requests"`
+	// 发出对冲请求的次数
+	HedgedRequests int64 `json:"hedged_requests"`
+	// 对冲请求先于原请求成功的次数
+	HedgeWins int64 `json:"hedge_wins"`
 	// 客户端统计信息
 	ClientStats map[string]ClientStats `json:"client_stats"`
 	// 最后更新时间
@@ -145,6 +162,14 @@ func NewClientPool(config *PoolConfig, logger *logrus.Logger) (*ClientPool, erro
 		// 默认最小健康客户端数为1
 		config.MinHealthyClients = 1
 	}
+	if config.MaxHeadLag == 0 {
+		// 默认允许落后5个区块
+		config.MaxHeadLag = 5
+	}
+	if config.QuorumSize == 0 {
+		// 默认要求多数节点一致
+		config.QuorumSize = len(config.Clients)/2 + 1
+	}
 
 	if logger == nil {
 		logger = logrus.New()
@@ -163,6 +188,11 @@ func NewClientPool(config *PoolConfig, logger *logrus.Logger) (*ClientPool, erro
 		pool.circuitBreaker = NewCircuitBreaker(config.CircuitBreakerConfig)
 	}
 
+	// 初始化对冲器
+	if config.HedgeConfig != nil {
+		pool.hedger = newHedger(config.HedgeConfig)
+	}
+
 	// 创建客户端
 	if err := pool.initializeClients(); err != nil {
 		return nil, fmt.Errorf("failed to initialize clients: %w", err)
@@ -231,6 +261,8 @@ func (p *ClientPool) GetClient() (*Client, error) {
 		client = p.getPriorityClient(healthyClients)
 	case StrategyHealthy:
 		client = p.getHealthiestClient(healthyClients)
+	case StrategyLatency:
+		client = p.getLatencyClient(healthyClients)
 	default:
 		client = p.getRoundRobinClient(healthyClients)
 	}
@@ -238,11 +270,11 @@ func (p *ClientPool) GetClient() (*Client, error) {
 	return client, nil
 }
 
-// getHealthyClients 获取所有健康的客户端
+// getHealthyClients 获取所有健康且未降级的客户端
 func (p *ClientPool) getHealthyClients() []*Client {
 	var healthy []*Client
 	for _, client := range p.clients {
-		if client.IsHealthy() {
+		if client.IsHealthy() && !client.IsDegraded() {
 			healthy = append(healthy, client)
 		}
 	}
@@ -310,6 +342,19 @@ func (p *ClientPool) getHealthiestClient(clients []*Client) *Client {
 
 // ExecuteWithFailover 执行带故障转移的操作
 func (p *ClientPool) ExecuteWithFailover(ctx context.Context, operation func(*Client) error) error {
+	return p.failover(ctx, func(client *Client) error {
+		started := time.Now()
+		err := operation(client)
+		// 调用方取消的请求不反映节点质量，不计入评分
+		if ctx.Err() == nil {
+			p.observe(client, time.Since(started), err)
+		}
+		return err
+	})
+}
+
+// failover 依次在负载均衡选出的客户端上执行 attempt，失败时等待后换下一个客户端
+func (p *ClientPool) failover(ctx context.Context, attempt func(*Client) error) error {
 	var lastErr error
 	attempts := 0
 	maxAttempts := p.config.MaxRetries + 1
@@ -326,7 +371,7 @@ func (p *ClientPool) ExecuteWithFailover(ctx context.Context, operation func(*Cl
 		p.stats.TotalRequests++
 		p.mu.Unlock()
 
-		err = operation(client)
+		err = attempt(client)
 		if err == nil {
 			// 成功时通知熔断器
 			if p.circuitBreaker != nil {
@@ -370,28 +415,16 @@ func (p *ClientPool) ExecuteWithFailover(ctx context.Context, operation func(*Cl
 
 // GetLatestBlock 获取最新区块（带故障转移）
 func (p *ClientPool) GetLatestBlock(ctx context.Context) (*types.Block, error) {
-	var block *types.Block
-
-	err := p.ExecuteWithFailover(ctx, func(client *Client) error {
-		var err error
-		block, err = client.GetLatestBlock(ctx)
-		return err
+	return ExecuteRead(ctx, p, func(ctx context.Context, client *Client) (*types.Block, error) {
+		return client.GetLatestBlock(ctx)
 	})
-
-	return block, err
 }
 
 // GetBlockByNumber 根据区块号获取区块（带故障转移）
 func (p *ClientPool) GetBlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
-	var block *types.Block
-
-	err := p.ExecuteWithFailover(ctx, func(client *Client) error {
-		var err error
-		block, err = client.GetBlockByNumber(ctx, number)
-		return err
+	return ExecuteRead(ctx, p, func(ctx context.Context, client *Client) (*types.Block, error) {
+		return client.GetBlockByNumber(ctx, number)
 	})
-
-	return block, err
 }
 
 // GetTransactionByHash 根据交易哈希获取交易（带故障转移）
@@ -410,28 +443,16 @@ func (p *ClientPool) GetTransactionByHash(ctx context.Context, hash common.Hash)
 
 // GetTransactionReceipt 获取交易收据（带故障转移）
 func (p *ClientPool) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
-	var receipt *types.Receipt
-
-	err := p.ExecuteWithFailover(ctx, func(client *Client) error {
-		var err error
-		receipt, err = client.GetTransactionReceipt(ctx, hash)
-		return err
+	return ExecuteRead(ctx, p, func(ctx context.Context, client *Client) (*types.Receipt, error) {
+		return client.GetTransactionReceipt(ctx, hash)
 	})
-
-	return receipt, err
 }
 
 // GetGasPrice 获取Gas价格（带故障转移）
 func (p *ClientPool) GetGasPrice(ctx context.Context) (*big.Int, error) {
-	var gasPrice *big.Int
-
-	err := p.ExecuteWithFailover(ctx, func(client *Client) error {
-		var err error
-		gasPrice, err = client.GetGasPrice(ctx)
-		return err
+	return ExecuteRead(ctx, p, func(ctx context.Context, client *Client) (*big.Int, error) {
+		return client.GetGasPrice(ctx)
 	})
-
-	return gasPrice, err
 }
 
 // GetStats 获取连接池统计信息
@@ -447,6 +468,7 @@ func (p *ClientPool) GetStats() *PoolStats {
 func (p *ClientPool) updateStats() {
 	p.stats.TotalClients = len(p.clients)
 	p.stats.HealthyClients = 0
+	p.stats.DegradedClients = 0
 	p.stats.LastUpdate = time.Now()
 
 	for _, client := range p.clients {
@@ -456,9 +478,17 @@ func (p *ClientPool) updateStats() {
 		if stats.IsHealthy {
 			p.stats.HealthyClients++
 		}
+		if stats.Degraded {
+			p.stats.DegradedClients++
+		}
 	}
 }
 
+// AddHealthCheckHandler 添加健康检查结果处理器
+func (p *ClientPool) AddHealthCheckHandler(handler HealthCheckHandler) {
+	p.healthChecker.AddHandler(handler)
+}
+
 // Close 关闭连接池
 func (p *ClientPool) Close() {
 	p.mu.Lock()
@@ -0,0 +1,255 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrQuorumNotReached 一致的结果数未达到法定数量
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+// QuorumMismatch 法定数量读取中与多数结果不一致的节点
+type QuorumMismatch struct {
+	// 读取方法，如 eth_getBlockByNumber
+	Method string `json:"method"`
+	// 读取对象，如区块号、交易哈希或账户地址
+	Target string `json:"target"`
+	// 结果不一致的节点URL
+	Endpoint string `json:"endpoint"`
+	// 多数节点返回的结果
+	Expected string `json:"expected"`
+	// 该节点返回的结果
+	Actual string `json:"actual"`
+	// 一致的节点数
+	Agreeing int `json:"agreeing"`
+	// 返回结果的节点数
+	Responding int `json:"responding"`
+	// 是否达到法定数量；未达到时 Expected 为得票最多的结果
+	QuorumReached bool `json:"quorum_reached"`
+	// 检测时间
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// QuorumHandler 法定数量读取结果不一致时的处理器
+type QuorumHandler interface {
+	HandleQuorumMismatch(mismatch *QuorumMismatch)
+}
+
+// quorumVote 单个节点的读取结果
+type quorumVote[T any] struct {
+	client *Client
+	value  T
+	key    string
+	err    error
+}
+
+// AddQuorumHandler 添加法定数量读取不一致处理器
+func (p *ClientPool) AddQuorumHandler(handler QuorumHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.quorumHandlers = append(p.quorumHandlers, handler)
+}
+
+// QuorumBlockHash 向多个节点读取指定高度的区块哈希，至少 QuorumSize 个节点一致时返回
+func (p *ClientPool) QuorumBlockHash(ctx context.Context, number *big.Int) (common.Hash, error) {
+	if number == nil {
+		return common.Hash{}, fmt.Errorf("block number is required for quorum reads")
+	}
+
+	header, err := quorumRead(ctx, p, "eth_getBlockByNumber", number.String(),
+		func(ctx context.Context, ethClient *ethclient.Client) (*types.Header, error) {
+			return ethClient.HeaderByNumber(ctx, number)
+		},
+		func(header *types.Header) string {
+			return header.Hash().Hex()
+		})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// QuorumTransactionReceipt 向多个节点读取交易收据，至少 QuorumSize 个节点一致时返回
+// 比较收据的共识字段（状态、累计Gas、Bloom、日志）以及所在区块与位置
+func (p *ClientPool) QuorumTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return quorumRead(ctx, p, "eth_getTransactionReceipt", hash.Hex(),
+		func(ctx context.Context, ethClient *ethclient.Client) (*types.Receipt, error) {
+			return ethClient.TransactionReceipt(ctx, hash)
+		},
+		func(receipt *types.Receipt) string {
+			encoded, err := receipt.MarshalBinary()
+			if err != nil {
+				return err.Error()
+			}
+			return fmt.Sprintf("%s@%s/%d", crypto.Keccak256Hash(encoded).Hex(), receipt.BlockHash.Hex(), receipt.TransactionIndex)
+		})
+}
+
+// QuorumBlockReceipts 向多个节点读取区块内全部交易收据，至少 QuorumSize 个节点一致时返回
+// 比较全部收据共识编码的哈希
+func (p *ClientPool) QuorumBlockReceipts(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	return quorumRead(ctx, p, "eth_getBlockReceipts", blockHash.Hex(),
+		func(ctx context.Context, ethClient *ethclient.Client) ([]*types.Receipt, error) {
+			return ethClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(blockHash, false))
+		},
+		func(receipts []*types.Receipt) string {
+			hasher := crypto.NewKeccakState()
+			for _, receipt := range receipts {
+				encoded, err := receipt.MarshalBinary()
+				if err != nil {
+					return err.Error()
+				}
+				hasher.Write(encoded)
+			}
+			return common.BytesToHash(hasher.Sum(nil)).Hex()
+		})
+}
+
+// QuorumBalance 向多个节点读取账户在指定区块的余额，至少 QuorumSize 个节点一致时返回
+// 必须指定区块号，各节点的最新区块可能不同
+func (p *ClientPool) QuorumBalance(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if blockNumber == nil {
+		return nil, fmt.Errorf("block number is required for quorum reads")
+	}
+
+	return quorumRead(ctx, p, "eth_getBalance", account.Hex()+"@"+blockNumber.String(),
+		func(ctx context.Context, ethClient *ethclient.Client) (*big.Int, error) {
+			return ethClient.BalanceAt(ctx, account, blockNumber)
+		},
+		func(balance *big.Int) string {
+			return balance.String()
+		})
+}
+
+// quorumRead 在所有可用节点上并发执行 read，按 key 对结果分组
+// 得票最多的结果达到 QuorumSize 时返回；读取失败的节点不参与投票，
+// 返回不同结果的节点逐个上报给 QuorumHandler
+func quorumRead[T any](
+	ctx context.Context,
+	p *ClientPool,
+	method, target string,
+	read func(ctx context.Context, ethClient *ethclient.Client) (T, error),
+	key func(T) string,
+) (T, error) {
+	var zero T
+
+	p.mu.RLock()
+	clients := p.getHealthyClients()
+	quorum := p.config.QuorumSize
+	p.mu.RUnlock()
+
+	if len(clients) < quorum {
+		return zero, fmt.Errorf("%w: %d healthy clients, %d required", ErrQuorumNotReached, len(clients), quorum)
+	}
+
+	votes := make([]*quorumVote[T], len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+
+			vote := &quorumVote[T]{client: client}
+			started := time.Now()
+			if ethClient := client.GetEthClient(); ethClient == nil {
+				vote.err = fmt.Errorf("eth client is nil")
+			} else {
+				vote.value, vote.err = read(ctx, ethClient)
+			}
+			if ctx.Err() == nil {
+				client.observe(time.Since(started), vote.err)
+			}
+			if vote.err == nil {
+				vote.key = key(vote.value)
+			}
+			votes[i] = vote
+		}(i, client)
+	}
+	wg.Wait()
+
+	// 按结果分组，得票相同时以先出现（优先级更高）的结果为准
+	counts := make(map[string]int)
+	var (
+		winner     *quorumVote[T]
+		responding int
+		lastErr    error
+	)
+	for _, vote := range votes {
+		if vote.err != nil {
+			lastErr = vote.err
+			continue
+		}
+		responding++
+		counts[vote.key]++
+		if winner == nil || counts[vote.key] > counts[winner.key] {
+			winner = vote
+		}
+	}
+
+	if winner == nil {
+		return zero, fmt.Errorf("%w: no client answered %s %s: %v", ErrQuorumNotReached, method, target, lastErr)
+	}
+
+	agreeing := counts[winner.key]
+	reached := agreeing >= quorum
+	if len(counts) > 1 {
+		reportQuorumMismatches(p, votes, winner, method, target, agreeing, responding, reached)
+	}
+
+	if !reached {
+		return zero, fmt.Errorf("%w: %d of %d clients agree on %s %s, %d required",
+			ErrQuorumNotReached, agreeing, responding, method, target, quorum)
+	}
+	return winner.value, nil
+}
+
+// reportQuorumMismatches 将与得票最多结果不同的节点逐个上报
+func reportQuorumMismatches[T any](p *ClientPool, votes []*quorumVote[T], winner *quorumVote[T], method, target string, agreeing, responding int, reached bool) {
+	p.mu.RLock()
+	handlers := p.quorumHandlers
+	p.mu.RUnlock()
+
+	for _, vote := range votes {
+		if vote.err != nil || vote.key == winner.key {
+			continue
+		}
+
+		mismatch := &QuorumMismatch{
+			Method:        method,
+			Target:        target,
+			Endpoint:      vote.client.config.URL,
+			Expected:      winner.key,
+			Actual:        vote.key,
+			Agreeing:      agreeing,
+			Responding:    responding,
+			QuorumReached: reached,
+			Timestamp:     time.Now(),
+		}
+
+		p.logger.WithFields(logrus.Fields{
+			"method":         method,
+			"target":         target,
+			"client_url":     mismatch.Endpoint,
+			"expected":       mismatch.Expected,
+			"actual":         mismatch.Actual,
+			"agreeing":       agreeing,
+			"responding":     responding,
+			"quorum_reached": reached,
+		}).Warn("Client returned a result that differs from the quorum")
+
+		for _, handler := range handlers {
+			handler.HandleQuorumMismatch(mismatch)
+		}
+	}
+}
@@ -327,12 +327,17 @@ func (sm *SubscriptionManager) GetSubscriptionsByType(subType SubscriptionType)
 func (sm *SubscriptionManager) Close() error {
 	sm.logger.Info("Closing subscription manager")
 	
-	// Close all subscriptions
-	sm.mutex.Lock()
+	// Close all subscriptions (unsubscribe acquires the mutex itself)
+	sm.mutex.RLock()
+	ids := make([]string, 0, len(sm.subscriptions))
 	for id := range sm.subscriptions {
+		ids = append(ids, id)
+	}
+	sm.mutex.RUnlock()
+	
+	for _, id := range ids {
 		sm.unsubscribe(id)
 	}
-	sm.mutex.Unlock()
 	
 	sm.cancel()
 	return nil
@@ -544,9 +549,18 @@ func (sm *SubscriptionManager) reestablishSubscriptions() {
 	for _, sub := range subscriptions {
 		sm.logger.WithField("id", sub.ID).Info("Reestablishing subscription")
 		
-		// Create new subscription request
+		// Drop routes to the server-side ID of the lost connection
+		sm.routerMutex.Lock()
+		for serverID, routed := range sm.messageRouter {
+			if routed == sub {
+				delete(sm.messageRouter, serverID)
+			}
+		}
+		sm.routerMutex.Unlock()
+		
+		// Reuse the subscription ID as request ID so the confirmation is routed back to it
 		subscribeMsg := &WSMessage{
-			ID:      generateSubscriptionID(),
+			ID:      sub.ID,
 			Method:  "eth_subscribe",
 			Params:  []interface{}{string(sub.Config.Type), sub.Config.Parameters},
 			JSONRPC: "2.0",
@@ -2,13 +2,16 @@ package ethereum
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/sirupsen/logrus"
 )
 
@@ -76,6 +79,8 @@ type TransactionSyncOptions struct {
 	Filter *TransactionFilter `json:"filter,omitempty"`
 	// 是否验证完整性
 	VerifyIntegrity bool `json:"verify_integrity"`
+	// 单次JSON-RPC批量请求的最大调用数，0表示使用 DefaultRPCBatchSize
+	RPCBatchSize int `json:"rpc_batch_size"`
 }
 
 // NewTransactionService 创建新的交易数据服务
@@ -225,92 +230,172 @@ func (ts *TransactionService) fetchTransactionBatchesConcurrently(ctx context.Co
 	return allTransactions, nil
 }
 
-// fetchTransactionBatch 获取单个交易批次
+// fetchTransactionBatch 以JSON-RPC批量请求获取单个交易批次，只重试失败的交易
+// 交易、收据与区块依次各用一轮批量请求获取，重试后仍失败的交易记录日志后跳过
 func (ts *TransactionService) fetchTransactionBatch(ctx context.Context, hashes []common.Hash, options *TransactionSyncOptions) *TransactionBatch {
 	result := &TransactionBatch{
 		Hashes: hashes,
 	}
 
-	var transactions []*TransactionWithReceipt
+	batchOptions := BatchCallOptions{
+		MaxSize:       options.RPCBatchSize,
+		RetryAttempts: options.RetryAttempts,
+		RetryDelay:    options.RetryDelay,
+	}
 
-	for _, hash := range hashes {
-		var txWithReceipt *TransactionWithReceipt
-		var err error
+	transactions, err := ts.fetchTransactions(ctx, hashes, batchOptions)
+	if err == nil && options.IncludeReceipts {
+		transactions, err = ts.fetchReceipts(ctx, transactions, batchOptions)
+	}
+	if err == nil && options.IncludeBlocks {
+		err = ts.fetchBlocks(ctx, transactions, batchOptions)
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("failed to fetch transaction batch: %w", err)
+		return result
+	}
 
-		// 重试机制
-		for attempt := 0; attempt <= options.RetryAttempts; attempt++ {
-			txWithReceipt, err = ts.fetchSingleTransaction(ctx, hash, options)
-			if err == nil {
-				break
-			}
+	result.Transactions = transactions
+	return result
+}
 
-			if attempt < options.RetryAttempts {
-				ts.logger.WithFields(logrus.Fields{
-					"hash":    hash.Hex(),
-					"attempt": attempt + 1,
-					"error":   err,
-				}).Warn("Failed to fetch transaction, retrying")
-
-				select {
-				case <-ctx.Done():
-					result.Error = ctx.Err()
-					return result
-				case <-time.After(options.RetryDelay * time.Duration(attempt+1)):
-				}
-			}
+// fetchTransactions 批量获取交易本身
+func (ts *TransactionService) fetchTransactions(ctx context.Context, hashes []common.Hash, options BatchCallOptions) ([]*TransactionWithReceipt, error) {
+	raws := make([]json.RawMessage, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{hash},
+			Result: &raws[i],
 		}
+	}
 
+	options.Validate = requireRawResult
+	if err := ts.pool.BatchCallWithRetry(ctx, elems, options); err != nil {
+		return nil, err
+	}
+
+	var transactions []*TransactionWithReceipt
+	for i := range elems {
+		err := elems[i].Error
+		var tx *types.Transaction
+		var isPending bool
+		if err == nil {
+			tx, isPending, err = decodeTransaction(raws[i])
+		}
 		if err != nil {
 			ts.logger.WithFields(logrus.Fields{
-				"hash":  hash.Hex(),
+				"hash":  hashes[i].Hex(),
 				"error": err,
 			}).Error("Failed to fetch transaction after all retries")
 			continue
 		}
 
-		transactions = append(transactions, txWithReceipt)
+		transactions = append(transactions, &TransactionWithReceipt{
+			Transaction: tx,
+			IsPending:   isPending,
+		})
 	}
-
-	result.Transactions = transactions
-	return result
+	return transactions, nil
 }
 
-// fetchSingleTransaction 获取单个交易
-func (ts *TransactionService) fetchSingleTransaction(ctx context.Context, hash common.Hash, options *TransactionSyncOptions) (*TransactionWithReceipt, error) {
-	tx, isPending, err := ts.pool.GetTransactionByHash(ctx, hash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
+// fetchReceipts 批量获取已上链交易的收据，获取失败的交易被跳过
+func (ts *TransactionService) fetchReceipts(ctx context.Context, transactions []*TransactionWithReceipt, options BatchCallOptions) ([]*TransactionWithReceipt, error) {
+	var (
+		mined []*TransactionWithReceipt
+		elems []rpc.BatchElem
+	)
+	receipts := make([]*types.Receipt, len(transactions))
+	for _, tx := range transactions {
+		if tx.IsPending {
+			continue
+		}
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{tx.Transaction.Hash()},
+			Result: &receipts[len(mined)],
+		})
+		mined = append(mined, tx)
+	}
+	if len(elems) == 0 {
+		return transactions, nil
 	}
 
-	result := &TransactionWithReceipt{
-		Transaction: tx,
-		IsPending:   isPending,
+	options.Validate = func(elem *rpc.BatchElem) error {
+		if *elem.Result.(**types.Receipt) == nil {
+			return ethereum.NotFound
+		}
+		return nil
+	}
+	if err := ts.pool.BatchCallWithRetry(ctx, elems, options); err != nil {
+		return nil, err
 	}
 
-	// 获取收据
-	if options.IncludeReceipts && !isPending {
-		receipt, err := ts.pool.GetTransactionReceipt(ctx, hash)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+	failed := make(map[*TransactionWithReceipt]bool)
+	for i, tx := range mined {
+		if err := elems[i].Error; err != nil {
+			ts.logger.WithFields(logrus.Fields{
+				"hash":  tx.Transaction.Hash().Hex(),
+				"error": err,
+			}).Error("Failed to fetch transaction receipt after all retries")
+			failed[tx] = true
+			continue
 		}
-		result.Receipt = receipt
+		tx.Receipt = receipts[i]
 	}
 
-	// 获取区块信息
-	if options.IncludeBlocks && !isPending && result.Receipt != nil {
-		block, err := ts.pool.GetBlockByNumber(ctx, result.Receipt.BlockNumber)
-		if err != nil {
+	if len(failed) == 0 {
+		return transactions, nil
+	}
+	var fetched []*TransactionWithReceipt
+	for _, tx := range transactions {
+		if !failed[tx] {
+			fetched = append(fetched, tx)
+		}
+	}
+	return fetched, nil
+}
+
+// fetchBlocks 批量获取交易所在区块，同一区块只请求一次，获取失败时仅记录日志
+func (ts *TransactionService) fetchBlocks(ctx context.Context, transactions []*TransactionWithReceipt, options BatchCallOptions) error {
+	index := make(map[uint64]int)
+	var numbers []*big.Int
+	for _, tx := range transactions {
+		if tx.IsPending || tx.Receipt == nil {
+			continue
+		}
+		number := tx.Receipt.BlockNumber.Uint64()
+		if _, ok := index[number]; !ok {
+			index[number] = len(numbers)
+			numbers = append(numbers, tx.Receipt.BlockNumber)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	blocks, errs, err := fetchBlocksByNumber(ctx, ts.pool, numbers, options)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		if tx.IsPending || tx.Receipt == nil {
+			continue
+		}
+		i := index[tx.Receipt.BlockNumber.Uint64()]
+		if errs[i] != nil {
 			ts.logger.WithFields(logrus.Fields{
-				"hash":         hash.Hex(),
-				"block_number": result.Receipt.BlockNumber,
-				"error":        err,
+				"hash":         tx.Transaction.Hash().Hex(),
+				"block_number": tx.Receipt.BlockNumber,
+				"error":        errs[i],
 			}).Warn("Failed to get block for transaction")
-		} else {
-			result.Block = block
+			continue
 		}
+		tx.Block = blocks[i]
 	}
-
-	return result, nil
+	return nil
 }
 
 // GetTransactionsFromBlock 从区块中获取所有交易
@@ -197,7 +197,7 @@ func (ts *TxSubscriber) Start() error {
 	go ts.errorProcessor()
 	
 	// Start hash fetcher if needed
-	if ts.config.FetchFullTx && ts.config.SubscriptionType == SubscriptionTypeNewPendingTxs {
+	if ts.config.FetchFullTx {
 		for i := 0; i < ts.config.MaxConcurrency; i++ {
 			go ts.hashFetcher()
 		}
@@ -314,9 +314,11 @@ func (ts *TxSubscriber) processSubscriptionData(data interface{}) {
 			ts.logger.WithField("type", fmt.Sprintf("%T", data)).Warn("Unexpected hash data type")
 		}
 	case SubscriptionTypePendingTxs:
-		// Data is full transaction
+		// Data is full transaction, but most nodes only send hashes by default
 		if tx, ok := data.(*types.Transaction); ok {
 			ts.processTransaction(tx.Hash(), tx)
+		} else if hash, ok := data.(common.Hash); ok {
+			ts.processTransactionHash(hash)
 		} else {
 			ts.logger.WithField("type", fmt.Sprintf("%T", data)).Warn("Unexpected transaction data type")
 		}
@@ -0,0 +1,284 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// node 语法树节点，求值结果为 float64、string、bool、[]interface{} 或 nil（字段缺失）
+type node interface {
+	typ() Type
+	eval(s *scope) (interface{}, error)
+}
+
+// literalNode 字面量
+type literalNode struct {
+	value interface{}
+	t     Type
+}
+
+func (n *literalNode) typ() Type { return n.t }
+
+func (n *literalNode) eval(*scope) (interface{}, error) { return n.value, nil }
+
+// fieldNode 字段引用
+type fieldNode struct {
+	name string
+	t    Type
+}
+
+func (n *fieldNode) typ() Type { return n.t }
+
+func (n *fieldNode) eval(s *scope) (interface{}, error) {
+	if s.vars == nil {
+		return nil, nil
+	}
+	v, ok := s.vars.Lookup(n.name)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	return normalize(v, n.t), nil
+}
+
+// listNode 列表字面量
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) typ() Type { return TypeList }
+
+func (n *listNode) eval(s *scope) (interface{}, error) {
+	values := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// watchlistNode 地址名单
+type watchlistNode struct {
+	name string
+}
+
+func (n *watchlistNode) typ() Type { return TypeList }
+
+func (n *watchlistNode) eval(s *scope) (interface{}, error) {
+	if s.lists == nil {
+		return nil, fmt.Errorf("watchlist %q is not available", n.name)
+	}
+	entries, ok := s.lists.Watchlist(n.name)
+	if !ok {
+		return nil, fmt.Errorf("unknown watchlist %q", n.name)
+	}
+	return normalize(entries, TypeList), nil
+}
+
+// callNode 内置函数调用
+type callNode struct {
+	fn   string
+	args []node
+	t    Type
+}
+
+func (n *callNode) typ() Type { return n.t }
+
+func (n *callNode) eval(s *scope) (interface{}, error) {
+	v, err := n.args[0].eval(s)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch n.fn {
+	case "lower":
+		if s, ok := v.(string); ok {
+			return strings.ToLower(s), nil
+		}
+		return nil, nil
+	case "len":
+		switch x := v.(type) {
+		case []interface{}:
+			return float64(len(x)), nil
+		case string:
+			return float64(len(x)), nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown function %s", n.fn)
+}
+
+// unaryNode 一元运算
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) typ() Type { return n.x.typ() }
+
+func (n *unaryNode) eval(s *scope) (interface{}, error) {
+	v, err := n.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, _ := v.(bool)
+		return !b, nil
+	}
+	if f, ok := v.(float64); ok {
+		return -f, nil
+	}
+	return nil, nil
+}
+
+// matchNode 正则匹配，模式在编译时预编译
+type matchNode struct {
+	x  node
+	re *regexp.Regexp
+}
+
+func (n *matchNode) typ() Type { return TypeBool }
+
+func (n *matchNode) eval(s *scope) (interface{}, error) {
+	v, err := n.x.eval(s)
+	if err != nil {
+		return false, err
+	}
+	str, ok := v.(string)
+	return ok && n.re.MatchString(str), nil
+}
+
+// binaryNode 二元运算
+type binaryNode struct {
+	op          string
+	left, right node
+	t           Type
+}
+
+func (n *binaryNode) typ() Type { return n.t }
+
+func (n *binaryNode) eval(s *scope) (interface{}, error) {
+	// 逻辑运算短路求值
+	if n.op == "&&" || n.op == "||" {
+		l, err := n.left.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		lb, _ := l.(bool)
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		r, err := n.right.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		rb, _ := r.(bool)
+		return rb, nil
+	}
+
+	l, err := n.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	}
+
+	// 空值或运行时类型不符（动态字段）时，比较为 false，算术结果为空值
+	switch n.op {
+	case "<", "<=", ">", ">=", "+", "-", "*", "/":
+		lf, lok := l.(float64)
+		rf, rok := r.(float64)
+		if !lok || !rok {
+			if n.t == TypeBool {
+				return false, nil
+			}
+			return nil, nil
+		}
+		return arithmetic(n.op, lf, rf)
+	case "in":
+		list, ok := r.([]interface{})
+		return ok && listContains(list, l), nil
+	case "contains":
+		if list, ok := l.([]interface{}); ok {
+			return listContains(list, r), nil
+		}
+	}
+
+	ls, lok := l.(string)
+	rs, rok := r.(string)
+	if !lok || !rok {
+		return false, nil
+	}
+	switch n.op {
+	case "contains":
+		return strings.Contains(strings.ToLower(ls), strings.ToLower(rs)), nil
+	case "startsWith":
+		return strings.HasPrefix(strings.ToLower(ls), strings.ToLower(rs)), nil
+	case "endsWith":
+		return strings.HasSuffix(strings.ToLower(ls), strings.ToLower(rs)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+// arithmetic 数值比较与算术运算
+func arithmetic(op string, l, r float64) (interface{}, error) {
+	switch op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return l / r, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// equal 比较两个值，字符串不区分大小写，空值只与空值相等
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		return ok && strings.EqualFold(as, bs)
+	}
+	return a == b
+}
+
+// listContains 检查列表是否包含值
+func listContains(list []interface{}, v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	for _, item := range list {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+// Package expr 实现告警规则与事件过滤使用的表达式语言
+//
+// 表达式在创建规则时编译并做类型检查，运行时只读取调用方提供的字段，不支持循环与赋值，
+// 求值耗时与表达式长度成正比。示例：
+//
+//	tx.value > 10 ether && tx.to in watchlist("exchanges")
+//
+// 支持的语法：
+//   - 字面量：数字（含 0x 十六进制）、双引号字符串、true/false、列表 [a, b]
+//   - 单位后缀：wei、gwei、ether，如 50 gwei
+//   - 运算符：|| && ! == != < <= > >= + - * / 以及 in、contains、startsWith、endsWith、matches
+//   - 函数：watchlist("name")、lower(s)、len(s|list)
+//
+// 环境可用 "前缀.*" 声明一组动态字段，如 log.args.*，其值类型在运行时确定；
+// 运行时类型与运算不符时按空值处理。
+//
+// 字符串比较（==、!=、in、contains、startsWith、endsWith）不区分大小写，以便直接比较以太坊地址；
+// matches 按正则表达式匹配，需要忽略大小写时使用 (?i)。
+// 事件缺少的字段视为空值，空值参与的比较除 != 外均为 false。
+//
+// 数字统一以 float64 表示，绝对值超过 2^53 的整数只保留约 16 位有效数字。
+// 以 wei 计的金额通常超过该范围，比较结果是近似的：1 ether 与 1 ether + 1 wei 被视为相等。
+// 一百万 ether 以内的金额误差小于 1 gwei，规则阈值不要依赖 wei 级别的精确比较。
+package expr
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// MaxLength 表达式最大长度
+const MaxLength = 4096
+
+// maxDepth 语法树最大嵌套深度
+const maxDepth = 64
+
+// Type 表达式值类型
+type Type int
+
+const (
+	TypeBool   Type = iota + 1 // 布尔
+	TypeNumber                 // 数字，float64 表示，超过 2^53 的整数为近似值
+	TypeString                 // 字符串
+	TypeList                   // 列表
+	TypeAny                    // 动态类型，运行时确定
+)
+
+// String 返回类型名称
+func (t Type) String() string {
+	switch t {
+	case TypeBool:
+		return "bool"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeList:
+		return "list"
+	case TypeAny:
+		return "any"
+	default:
+		return "unknown"
+	}
+}
+
+// Env 编译环境，声明表达式可引用的字段及其类型
+type Env struct {
+	vars map[string]Type
+	// 以 ".*" 结尾声明的动态字段前缀（含末尾的点号）
+	prefixes map[string]Type
+}
+
+// NewEnv 创建编译环境，字段名可包含点号，如 tx.value；以 ".*" 结尾的名称声明动态字段前缀
+func NewEnv(vars map[string]Type) *Env {
+	env := &Env{vars: make(map[string]Type), prefixes: make(map[string]Type)}
+	for name, t := range vars {
+		env.declare(name, t)
+	}
+	return env
+}
+
+// declare 声明字段或动态字段前缀
+func (e *Env) declare(name string, t Type) {
+	if strings.HasSuffix(name, ".*") {
+		e.prefixes[strings.TrimSuffix(name, "*")] = t
+		return
+	}
+	e.vars[name] = t
+}
+
+// Merge 返回包含两个环境全部字段的新环境，同名字段以 other 为准
+func (e *Env) Merge(other *Env) *Env {
+	merged := NewEnv(nil)
+	for _, env := range []*Env{e, other} {
+		for name, t := range env.vars {
+			merged.vars[name] = t
+		}
+		for prefix, t := range env.prefixes {
+			merged.prefixes[prefix] = t
+		}
+	}
+	return merged
+}
+
+// lookup 返回字段类型，先精确匹配再按最长前缀匹配动态字段
+func (e *Env) lookup(name string) (Type, bool) {
+	if t, ok := e.vars[name]; ok {
+		return t, true
+	}
+	best, found := "", Type(0)
+	for prefix, t := range e.prefixes {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+			best, found = prefix, t
+		}
+	}
+	return found, found != 0
+}
+
+// Fields 返回按名称排序的字段列表，动态字段以 "前缀.*" 形式列出
+func (e *Env) Fields() []string {
+	names := make([]string, 0, len(e.vars)+len(e.prefixes))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	for prefix := range e.prefixes {
+		names = append(names, prefix+"*")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Vars 表达式运行时的字段来源
+type Vars interface {
+	// Lookup 返回字段值，字段不存在时返回 false
+	Lookup(name string) (interface{}, bool)
+}
+
+// MapVars 基于 map 的字段来源
+type MapVars map[string]interface{}
+
+// Lookup 返回字段值
+func (m MapVars) Lookup(name string) (interface{}, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// Watchlists 地址名单来源，用于 watchlist 函数
+type Watchlists interface {
+	// Watchlist 返回名单中的条目，名单不存在时返回 false
+	Watchlist(name string) ([]string, bool)
+}
+
+// MapWatchlists 基于 map 的地址名单
+type MapWatchlists map[string][]string
+
+// Watchlist 返回名单中的条目
+func (m MapWatchlists) Watchlist(name string) ([]string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// Error 表达式编译错误
+type Error struct {
+	// 出错位置（字节偏移）
+	Pos int
+	// 错误描述
+	Msg string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("expression error at position %d: %s", e.Pos, e.Msg)
+}
+
+// Program 编译后的表达式，可并发求值
+type Program struct {
+	source     string
+	root       node
+	watchlists []string
+}
+
+// Compile 编译表达式并做类型检查，表达式结果必须为布尔值
+func Compile(source string, env *Env) (*Program, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, &Error{Pos: 0, Msg: "empty expression"}
+	}
+	if len(source) > MaxLength {
+		return nil, &Error{Pos: MaxLength, Msg: fmt.Sprintf("expression longer than %d characters", MaxLength)}
+	}
+
+	p := &parser{lexer: newLexer(source), env: env}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	if !accepts(root.typ(), TypeBool) {
+		return nil, &Error{Pos: 0, Msg: fmt.Sprintf("expression must be bool, got %s", root.typ())}
+	}
+
+	return &Program{source: source, root: root, watchlists: p.watchlists}, nil
+}
+
+// Source 返回表达式源码
+func (p *Program) Source() string {
+	return p.source
+}
+
+// Watchlists 返回表达式引用的地址名单
+func (p *Program) Watchlists() []string {
+	return p.watchlists
+}
+
+// Eval 使用给定字段求值，lists 为空时 watchlist 函数返回错误
+func (p *Program) Eval(vars Vars, lists Watchlists) (bool, error) {
+	v, err := p.root.eval(&scope{vars: vars, lists: lists})
+	if err != nil {
+		return false, err
+	}
+	b, _ := v.(bool)
+	return b, nil
+}
+
+// scope 单次求值的上下文
+type scope struct {
+	vars  Vars
+	lists Watchlists
+}
+
+// units 数字单位后缀，换算为 wei
+var units = map[string]float64{
+	"wei":   1,
+	"gwei":  1e9,
+	"ether": 1e18,
+}
+
+// normalize 将字段值转换为声明类型的内部表示，无法转换时返回 nil
+// 动态类型依次尝试数字、字符串、布尔与列表
+func normalize(v interface{}, t Type) interface{} {
+	if t == TypeAny {
+		for _, candidate := range []Type{TypeNumber, TypeString, TypeBool} {
+			if n := normalize(v, candidate); n != nil {
+				return n
+			}
+		}
+		list, ok := normalize(v, TypeList).([]interface{})
+		if !ok {
+			return nil
+		}
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = normalize(item, TypeAny)
+		}
+		return items
+	}
+
+	switch t {
+	case TypeNumber:
+		switch n := v.(type) {
+		case float64:
+			return n
+		case float32:
+			return float64(n)
+		case int:
+			return float64(n)
+		case int64:
+			return float64(n)
+		case uint64:
+			return float64(n)
+		case uint32:
+			return float64(n)
+		case *big.Int:
+			if n == nil {
+				return nil
+			}
+			f, _ := new(big.Float).SetInt(n).Float64()
+			return f
+		}
+	case TypeString:
+		if s, ok := v.(string); ok {
+			return s
+		}
+		if s, ok := v.(fmt.Stringer); ok {
+			return s.String()
+		}
+	case TypeBool:
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	case TypeList:
+		switch l := v.(type) {
+		case []interface{}:
+			return l
+		case []string:
+			items := make([]interface{}, len(l))
+			for i, s := range l {
+				items[i] = s
+			}
+			return items
+		}
+	}
+	return nil
+}
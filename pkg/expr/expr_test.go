@@ -0,0 +1,283 @@
+package expr
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testEnv 测试用编译环境
+var testEnv = NewEnv(map[string]Type{
+	"tx.value":   TypeNumber,
+	"tx.gas":     TypeNumber,
+	"tx.to":      TypeString,
+	"tx.from":    TypeString,
+	"tx.success": TypeBool,
+	"log.topics": TypeList,
+	"tx.args.*":  TypeAny,
+})
+
+// mustCompile 编译表达式，失败时终止测试
+func mustCompile(t *testing.T, source string) *Program {
+	t.Helper()
+	program, err := Compile(source, testEnv)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", source, err)
+	}
+	return program
+}
+
+// mustEval 编译并求值表达式
+func mustEval(t *testing.T, source string, vars MapVars) bool {
+	t.Helper()
+	got, err := mustCompile(t, source).Eval(vars, nil)
+	if err != nil {
+		t.Fatalf("Eval(%q): %v", source, err)
+	}
+	return got
+}
+
+func TestEvalPrecedence(t *testing.T) {
+	vars := MapVars{"tx.value": 5, "tx.to": "0xABC"}
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"1 + 2 * 3 == 7", true},
+		{"(1 + 2) * 3 == 9", true},
+		{"10 - 4 - 3 == 3", true},
+		{"8 / 4 / 2 == 1", true},
+		{"-2 * 3 == -6", true},
+		{"1 gwei + 1 == 1000000001", true},
+		{"0x10 == 16", true},
+		{"1_000 == 1e3", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!false && false", false},
+		{"!(false && false)", true},
+		{"tx.value > 1 + 1 && tx.value < 2 * 5", true},
+		{"tx.value > 4 == true", true},
+		{`tx.to == "0xabc" || tx.value > 100`, true},
+		{`tx.to in ["0xdef", "0xabc"] && !(tx.value in [1, 2])`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := mustEval(t, tt.source, vars); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"", "empty expression"},
+		{"tx.value", "expression must be bool, got number"},
+		{`tx.value > "1"`, "operator > cannot be applied to number and string"},
+		{"tx.to == 1", "operator == cannot be applied to string and number"},
+		{"tx.value + tx.success > 1", "operator + cannot be applied to number and bool"},
+		{"tx.success && tx.to", "operator && cannot be applied to bool and string"},
+		{"log.topics == log.topics", "operator == cannot be applied to list and list"},
+		{"!tx.value", "operator ! requires bool, got number"},
+		{"-tx.to == 1", "operator - requires number, got string"},
+		{`"a" ether > 1`, "unit ether requires number, got string"},
+		{"len(1) > 0", "len expects string or list, got number"},
+		{`lower(tx.to, tx.from) == ""`, "lower expects 1 argument(s), got 2"},
+		{"tx.to in watchlist(tx.from)", "watchlist name must be a string literal"},
+		{`[1, "a"] contains 1`, "list elements must have the same type"},
+		{"tx.unknown > 1", "unknown field tx.unknown"},
+		{"tx.args > 1", "unknown field tx.args"},
+		{"foo(1)", "unknown function foo"},
+		{"tx.value > 1)", `unexpected`},
+		{"tx.value > 0xg", "invalid hex number"},
+		{"tx.value > 10x", "invalid number"},
+		{`tx.to == "abc`, "unterminated string"},
+		{"tx.value # 1", "unexpected character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := Compile(tt.source, testEnv)
+			var exprErr *Error
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("expected an *Error, got %v", err)
+			}
+			if !strings.Contains(exprErr.Msg, tt.want) {
+				t.Fatalf("expected error containing %q, got %q", tt.want, exprErr.Msg)
+			}
+		})
+	}
+}
+
+func TestCompileErrorPosition(t *testing.T) {
+	_, err := Compile(`tx.value > 1 && tx.to > "1"`, testEnv)
+	var exprErr *Error
+	if !errors.As(err, &exprErr) || exprErr.Pos != 22 {
+		t.Fatalf("expected an error at the second '>' (position 22), got %v", err)
+	}
+}
+
+func TestCompileLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"too long", "tx.value > " + strings.Repeat("1", MaxLength), "expression longer than 4096 characters"},
+		{"nested parentheses", strings.Repeat("(", 100) + "true" + strings.Repeat(")", 100), "expression nested too deeply"},
+		{"nested negation", strings.Repeat("!", 100) + "true", "expression nested too deeply"},
+		{"nested lists", "1 in [[1]]", "nested lists are not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.source, testEnv)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// 未超过限制的嵌套正常编译
+	mustEval(t, strings.Repeat("(", 10)+"true"+strings.Repeat(")", 10), nil)
+	mustEval(t, strings.Repeat("!", 20)+"true", nil)
+}
+
+func TestEvalMatches(t *testing.T) {
+	tests := []struct {
+		source string
+		vars   MapVars
+		want   bool
+	}{
+		{`tx.to matches "^0x[0-9a-f]+$"`, MapVars{"tx.to": "0xabc"}, true},
+		// 正则匹配区分大小写，与其他字符串运算不同
+		{`tx.to matches "^0x[0-9a-f]+$"`, MapVars{"tx.to": "0xABC"}, false},
+		{`tx.to matches "(?i)^0x[0-9a-f]+$"`, MapVars{"tx.to": "0xABC"}, true},
+		{`tx.to matches "^\\d+$"`, MapVars{"tx.to": "123"}, true},
+		{`tx.to matches "^0x"`, MapVars{}, false},
+		{`tx.args.name matches "^USD"`, MapVars{"tx.args.name": "USDC"}, true},
+		{`tx.args.name matches "^USD"`, MapVars{"tx.args.name": 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := mustEval(t, tt.source, tt.vars); got != tt.want {
+				t.Fatalf("expected %v for %v, got %v", tt.want, tt.vars, got)
+			}
+		})
+	}
+
+	for _, source := range []string{
+		`tx.to matches "("`,
+		`tx.to matches tx.from`,
+		`tx.value matches "1"`,
+	} {
+		if _, err := Compile(source, testEnv); err == nil {
+			t.Fatalf("expected %q to be rejected", source)
+		}
+	}
+}
+
+func TestEvalMissingFields(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"tx.value > 0", false},
+		{"tx.value < 0", false},
+		{"tx.value == 0", false},
+		{"tx.value != 0", true},
+		{"tx.value + 1 > 0", false},
+		{"-tx.value < 0", false},
+		{`tx.to == "0xabc"`, false},
+		{`tx.to != "0xabc"`, true},
+		{`tx.to startsWith "0x"`, false},
+		{`tx.to in ["0xabc"]`, false},
+		{`lower(tx.to) == "0xabc"`, false},
+		{"len(tx.to) == 0", false},
+		{"log.topics contains \"0x1\"", false},
+		{"tx.args.amount > 1", false},
+		{"tx.success", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := mustEval(t, tt.source, MapVars{}); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// 动态字段的运行时类型与运算不符时同样按空值处理
+	if mustEval(t, "tx.args.amount > 1", MapVars{"tx.args.amount": "abc"}) {
+		t.Fatal("expected a string argument not to compare as a number")
+	}
+	if !mustEval(t, "tx.args.amount > 1", MapVars{"tx.args.amount": big.NewInt(2)}) {
+		t.Fatal("expected a big.Int argument to compare as a number")
+	}
+}
+
+func TestEvalDivisionByZero(t *testing.T) {
+	tests := []struct {
+		source string
+		vars   MapVars
+	}{
+		{"1 / 0 > 1", nil},
+		{"tx.value / tx.gas > 1", MapVars{"tx.value": 1, "tx.gas": 0}},
+		{"tx.success && tx.value / 0 > 1", MapVars{"tx.success": true, "tx.value": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := mustCompile(t, tt.source).Eval(tt.vars, nil)
+			if err == nil || !strings.Contains(err.Error(), "division by zero") {
+				t.Fatalf("expected a division by zero error, got %v", err)
+			}
+		})
+	}
+
+	// 短路求值不会执行右侧的除法，除数为空值时结果为空值而不是错误
+	if !mustEval(t, "true || 1 / 0 > 1", nil) {
+		t.Fatal("expected || to short-circuit")
+	}
+	if mustEval(t, "tx.value / tx.gas > 1", MapVars{"tx.value": 1}) {
+		t.Fatal("expected a missing divisor to make the comparison false")
+	}
+}
+
+func TestEvalWatchlist(t *testing.T) {
+	program := mustCompile(t, `tx.to in watchlist("exchanges")`)
+	if got := program.Watchlists(); len(got) != 1 || got[0] != "exchanges" {
+		t.Fatalf("unexpected watchlists: %v", got)
+	}
+
+	lists := MapWatchlists{"exchanges": {"0xABC"}}
+	if got, err := program.Eval(MapVars{"tx.to": "0xabc"}, lists); err != nil || !got {
+		t.Fatalf("expected address to be in watchlist, got %v, %v", got, err)
+	}
+	if _, err := program.Eval(MapVars{"tx.to": "0xabc"}, MapWatchlists{}); err == nil {
+		t.Fatal("expected an error for an unknown watchlist")
+	}
+}
+
+func TestEvalWeiPrecision(t *testing.T) {
+	oneEtherAndOneWei, _ := new(big.Int).SetString("1000000000000000001", 10)
+	vars := MapVars{"tx.value": oneEtherAndOneWei}
+
+	// 超过 2^53 的整数以 float64 近似，相差 1 wei 的金额无法区分
+	if mustEval(t, "tx.value > 1 ether", vars) {
+		t.Fatal("expected 1 ether + 1 wei to round to 1 ether")
+	}
+	if !mustEval(t, "tx.value == 1 ether", vars) {
+		t.Fatal("expected 1 ether + 1 wei to compare equal to 1 ether")
+	}
+
+	// 2^53 以内的整数精确比较
+	if !mustEval(t, "tx.value > 9007199254740990", MapVars{"tx.value": uint64(9007199254740991)}) {
+		t.Fatal("expected integers below 2^53 to compare exactly")
+	}
+	// gwei 量级的差异仍可区分
+	if !mustEval(t, "tx.value > 1000 ether", MapVars{"tx.value": new(big.Int).Add(
+		new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)), big.NewInt(1e9))}) {
+		t.Fatal("expected a 1 gwei difference on 1000 ether to be visible")
+	}
+}
@@ -0,0 +1,403 @@
+package expr
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// binaryPrecedence 二元运算符优先级，数值越大结合越紧
+var binaryPrecedence = map[string]int{
+	"||":         1,
+	"&&":         2,
+	"==":         3,
+	"!=":         3,
+	"<":          3,
+	"<=":         3,
+	">":          3,
+	">=":         3,
+	"in":         3,
+	"contains":   3,
+	"startsWith": 3,
+	"endsWith":   3,
+	"matches":    3,
+	"+":          4,
+	"-":          4,
+	"*":          5,
+	"/":          5,
+}
+
+// functions 内置函数的参数与返回类型
+var functions = map[string]struct {
+	args   []Type
+	result Type
+}{
+	"watchlist": {args: []Type{TypeString}, result: TypeList},
+	"lower":     {args: []Type{TypeString}, result: TypeString},
+	"len":       {args: []Type{0}, result: TypeNumber}, // 参数为字符串或列表
+}
+
+// parser 语法分析器，构建语法树的同时完成类型检查
+type parser struct {
+	lexer *lexer
+	env   *Env
+	tok   token
+	// 引用的地址名单
+	watchlists []string
+}
+
+// next 前进到下一个词法单元
+func (p *parser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// errorf 在当前位置构造编译错误
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{Pos: p.tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// binaryOp 检查当前词法单元是否为二元运算符，返回运算符及其优先级
+func (p *parser) binaryOp() (string, int, bool) {
+	if p.tok.kind != tokOp && p.tok.kind != tokIdent {
+		return "", 0, false
+	}
+	prec, ok := binaryPrecedence[p.tok.text]
+	return p.tok.text, prec, ok
+}
+
+// parseExpr 解析完整表达式
+func (p *parser) parseExpr(depth int) (node, error) {
+	return p.parseBinary(1, depth)
+}
+
+// parseBinary 按优先级爬升解析二元表达式
+func (p *parser) parseBinary(minPrec, depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, p.errorf("expression nested too deeply")
+	}
+
+	left, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, prec, ok := p.binaryOp()
+		if !ok || prec < minPrec {
+			return left, nil
+		}
+		pos := p.tok.pos
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseBinary(prec+1, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary(op, left, right, pos); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseUnary 解析一元表达式与单位后缀
+func (p *parser) parseUnary(depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, p.errorf("expression nested too deeply")
+	}
+
+	if p.tok.kind == tokOp && (p.tok.text == "!" || p.tok.text == "-") {
+		op, pos := p.tok.text, p.tok.pos
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		want := TypeBool
+		if op == "-" {
+			want = TypeNumber
+		}
+		if !accepts(x.typ(), want) {
+			return nil, &Error{Pos: pos, Msg: fmt.Sprintf("operator %s requires %s, got %s", op, want, x.typ())}
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+
+	x, err := p.parsePrimary(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// 单位后缀
+	if p.tok.kind == tokIdent {
+		if factor, ok := units[p.tok.text]; ok {
+			if !accepts(x.typ(), TypeNumber) {
+				return nil, p.errorf("unit %s requires number, got %s", p.tok.text, x.typ())
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			x = &binaryNode{op: "*", left: x, right: &literalNode{value: factor, t: TypeNumber}, t: TypeNumber}
+		}
+	}
+	return x, nil
+}
+
+// parsePrimary 解析字面量、字段、函数调用、列表与括号表达式
+func (p *parser) parsePrimary(depth int) (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		value, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, &Error{Pos: tok.pos, Msg: err.Error()}
+		}
+		return &literalNode{value: value, t: TypeNumber}, p.next()
+
+	case tokString:
+		return &literalNode{value: tok.text, t: TypeString}, p.next()
+
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return &literalNode{value: tok.text == "true", t: TypeBool}, p.next()
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.parseCall(tok, depth)
+		}
+		return p.parseField(tok)
+
+	case tokOp:
+		switch tok.text {
+		case "(":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			x, err := p.parseExpr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			return p.parseList(depth)
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+// parseField 解析以点号分隔的字段名
+func (p *parser) parseField(first token) (node, error) {
+	name := first.text
+	for p.tok.kind == tokOp && p.tok.text == "." {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokIdent {
+			return nil, p.errorf("expected field name after '.'")
+		}
+		name += "." + p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	t, ok := p.env.lookup(name)
+	if !ok {
+		return nil, &Error{Pos: first.pos, Msg: fmt.Sprintf("unknown field %s", name)}
+	}
+	return &fieldNode{name: name, t: t}, nil
+}
+
+// parseCall 解析函数调用，当前词法单元为左括号
+func (p *parser) parseCall(name token, depth int) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("unknown function %s", name.text)}
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var args []node
+	for !(p.tok.kind == tokOp && p.tok.text == ")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if len(args) != len(fn.args) {
+		return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("%s expects %d argument(s), got %d", name.text, len(fn.args), len(args))}
+	}
+	for i, want := range fn.args {
+		got := args[i].typ()
+		if want == 0 {
+			if !accepts(got, TypeString) && got != TypeList {
+				return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("%s expects string or list, got %s", name.text, got)}
+			}
+			continue
+		}
+		if !accepts(got, want) {
+			return nil, &Error{Pos: name.pos, Msg: fmt.Sprintf("%s expects %s, got %s", name.text, want, got)}
+		}
+	}
+
+	if name.text == "watchlist" {
+		lit, ok := args[0].(*literalNode)
+		if !ok || lit.t != TypeString {
+			return nil, &Error{Pos: name.pos, Msg: "watchlist name must be a string literal"}
+		}
+		listName := lit.value.(string)
+		p.watchlists = append(p.watchlists, listName)
+		return &watchlistNode{name: listName}, nil
+	}
+	return &callNode{fn: name.text, args: args, t: fn.result}, nil
+}
+
+// parseList 解析列表字面量，元素类型必须一致
+func (p *parser) parseList(depth int) (node, error) {
+	pos := p.tok.pos
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	list := &listNode{}
+	for !(p.tok.kind == tokOp && p.tok.text == "]") {
+		if len(list.items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		item, err := p.parseExpr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if item.typ() == TypeList {
+			return nil, &Error{Pos: pos, Msg: "nested lists are not supported"}
+		}
+		if len(list.items) > 0 && item.typ() != list.items[0].typ() {
+			return nil, &Error{Pos: pos, Msg: "list elements must have the same type"}
+		}
+		list.items = append(list.items, item)
+	}
+	return list, p.next()
+}
+
+// expect 检查并跳过指定运算符
+func (p *parser) expect(op string) error {
+	if p.tok.kind != tokOp || p.tok.text != op {
+		return p.errorf("expected %q, got %s", op, p.tok)
+	}
+	return p.next()
+}
+
+// newBinary 构建二元运算节点并检查操作数类型
+func newBinary(op string, left, right node, pos int) (node, error) {
+	lt, rt := left.typ(), right.typ()
+	mismatch := func() error {
+		return &Error{Pos: pos, Msg: fmt.Sprintf("operator %s cannot be applied to %s and %s", op, lt, rt)}
+	}
+
+	switch op {
+	case "||", "&&":
+		if !accepts(lt, TypeBool) || !accepts(rt, TypeBool) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "==", "!=":
+		if (lt != rt && lt != TypeAny && rt != TypeAny) || lt == TypeList || rt == TypeList {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "<", "<=", ">", ">=":
+		if !accepts(lt, TypeNumber) || !accepts(rt, TypeNumber) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "+", "-", "*", "/":
+		if !accepts(lt, TypeNumber) || !accepts(rt, TypeNumber) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeNumber}, nil
+
+	case "in":
+		if !accepts(rt, TypeList) || (!accepts(lt, TypeString) && lt != TypeNumber) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "contains":
+		if !(accepts(lt, TypeString) && accepts(rt, TypeString)) && !(accepts(lt, TypeList) && (accepts(rt, TypeString) || rt == TypeNumber)) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "startsWith", "endsWith":
+		if !accepts(lt, TypeString) || !accepts(rt, TypeString) {
+			return nil, mismatch()
+		}
+		return &binaryNode{op: op, left: left, right: right, t: TypeBool}, nil
+
+	case "matches":
+		lit, ok := right.(*literalNode)
+		if !accepts(lt, TypeString) || !ok || rt != TypeString {
+			return nil, &Error{Pos: pos, Msg: "matches requires a string and a string literal pattern"}
+		}
+		re, err := regexp.Compile(lit.value.(string))
+		if err != nil {
+			return nil, &Error{Pos: pos, Msg: fmt.Sprintf("invalid pattern: %v", err)}
+		}
+		return &matchNode{x: left, re: re}, nil
+	}
+	return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unknown operator %s", op)}
+}
+
+// accepts 检查操作数类型是否满足要求，动态类型推迟到运行时检查
+func accepts(got, want Type) bool {
+	return got == want || got == TypeAny
+}
+
+// parseNumber 解析十进制或十六进制数字，允许下划线分隔
+func parseNumber(text string) (float64, error) {
+	text = strings.ReplaceAll(text, "_", "")
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		n, ok := new(big.Int).SetString(text[2:], 16)
+		if !ok {
+			return 0, fmt.Errorf("invalid hex number %s", text)
+		}
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %s", text)
+	}
+	return f, nil
+}
@@ -35,12 +35,26 @@ type Metrics struct {
 	BlockchainBlocksProcessed *prometheus.CounterVec
 	// 区块链最新区块高度
 	BlockchainLatestBlock prometheus.Gauge
+	// RPC节点落后于连接池最高区块的区块数
+	RPCNodeHeadLag *prometheus.GaugeVec
+
+	// 历史回填相关指标
+	// 回填写入的区块总数
+	BackfillBlocksTotal prometheus.Counter
+	// 回填写入的交易总数
+	BackfillTransactionsTotal prometheus.Counter
+	// 回填速率（区块/秒）
+	BackfillBlocksPerSecond prometheus.Gauge
+	// 回填当前进度区块高度
+	BackfillCurrentBlock prometheus.Gauge
 
 	// 告警相关指标
 	// 告警总数
 	AlertsTotal *prometheus.CounterVec
 	// 激活的告警数
 	AlertsActive prometheus.Gauge
+	// 被限流抑制的告警数
+	AlertsSuppressedTotal *prometheus.CounterVec
 
 	// 系统相关指标
 	// 应用程序信息
@@ -127,6 +141,47 @@ func NewMetrics(namespace string) *Metrics {
 				Help:      "Latest block number processed",
 			},
 		),
+		// RPC节点落后区块数
+		RPCNodeHeadLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rpc_node_head_lag_blocks",
+				Help:      "Number of blocks an RPC endpoint lags behind the highest endpoint in the pool",
+			},
+			[]string{"endpoint"},
+		),
+		// 回填写入的区块总数
+		BackfillBlocksTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "backfill_blocks_total",
+				Help:      "Total number of blocks written by backfill",
+			},
+		),
+		// 回填写入的交易总数
+		BackfillTransactionsTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "backfill_transactions_total",
+				Help:      "Total number of transactions written by backfill",
+			},
+		),
+		// 回填速率
+		BackfillBlocksPerSecond: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "backfill_blocks_per_second",
+				Help:      "Backfill throughput in blocks per second",
+			},
+		),
+		// 回填当前进度
+		BackfillCurrentBlock: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "backfill_current_block_number",
+				Help:      "Last block number committed by backfill",
+			},
+		),
 		// 告警相关指标
 		AlertsTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -144,6 +199,15 @@ func NewMetrics(namespace string) *Metrics {
 				Help:      "Number of active alerts",
 			},
 		),
+		// 被限流抑制的告警数
+		AlertsSuppressedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "alerts_suppressed_total",
+				Help:      "Total number of alerts or notifications suppressed by throttling",
+			},
+			[]string{"type", "reason"},
+		),
 		// 应用程序信息
 		ApplicationInfo: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -177,8 +241,14 @@ func (m *Metrics) Register() error {
 		m.DatabaseQueriesTotal,
 		m.BlockchainBlocksProcessed,
 		m.BlockchainLatestBlock,
+		m.RPCNodeHeadLag,
+		m.BackfillBlocksTotal,
+		m.BackfillTransactionsTotal,
+		m.BackfillBlocksPerSecond,
+		m.BackfillCurrentBlock,
 		m.AlertsTotal,
 		m.AlertsActive,
+		m.AlertsSuppressedTotal,
 		m.ApplicationInfo,
 		m.ApplicationUptime,
 	}